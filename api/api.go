@@ -0,0 +1,21 @@
+package api
+
+import "embed"
+
+//go:generate go run ./internal/swaggerui swagger-ui
+
+// OpenAPI is the OpenAPI 3.1 document describing the HTTP API.
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// SwaggerUI is the page rendering OpenAPI with Swagger UI.
+//
+//go:embed swagger.html
+var SwaggerUI []byte
+
+// SwaggerUIAssets holds the vendored swagger-ui-dist release the page loads,
+// pinned in swagger-ui/VERSION and refreshed with go generate ./api.
+//
+//go:embed swagger-ui
+var SwaggerUIAssets embed.FS
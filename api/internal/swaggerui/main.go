@@ -0,0 +1,118 @@
+// Command swaggerui vendors the swagger-ui-dist release pinned in
+// <dir>/VERSION, checking the npm tarball against the integrity the registry
+// publishes for that version before extracting the files the docs page loads.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const registry = "https://registry.npmjs.org/swagger-ui-dist/"
+
+var files = []string{"swagger-ui.css", "swagger-ui-bundle.js", "LICENSE"}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: swaggerui <dir>")
+		os.Exit(2)
+	}
+	if err := run(os.Args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, "swaggerui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	version, err := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: time.Minute}
+
+	var meta struct {
+		Dist struct {
+			Tarball   string `json:"tarball"`
+			Integrity string `json:"integrity"`
+		} `json:"dist"`
+	}
+	body, err := get(client, registry+strings.TrimSpace(string(version)))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return fmt.Errorf("decode registry metadata: %w", err)
+	}
+
+	tarball, err := get(client, meta.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+	sum := sha512.Sum512(tarball)
+	if "sha512-"+base64.StdEncoding.EncodeToString(sum[:]) != meta.Dist.Integrity {
+		return errors.New("tarball does not match the registry integrity")
+	}
+	return extract(tarball, dir)
+}
+
+func get(client *http.Client, url string) ([]byte, error) {
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func extract(tarball []byte, dir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(files))
+	for _, name := range files {
+		wanted["package/"+name] = true
+	}
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !wanted[header.Name] {
+			continue
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(header.Name, "package/")
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+		delete(wanted, header.Name)
+	}
+	if len(wanted) > 0 {
+		return fmt.Errorf("tarball is missing %d of the vendored files", len(wanted))
+	}
+	return nil
+}
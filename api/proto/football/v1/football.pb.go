@@ -0,0 +1,1100 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: football/v1/football.proto
+
+package footballv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Competition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	CurrentSeason *Season                `protobuf:"bytes,4,opt,name=current_season,json=currentSeason,proto3" json:"current_season,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Competition) Reset() {
+	*x = Competition{}
+	mi := &file_football_v1_football_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Competition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Competition) ProtoMessage() {}
+
+func (x *Competition) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Competition.ProtoReflect.Descriptor instead.
+func (*Competition) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Competition) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Competition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Competition) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Competition) GetCurrentSeason() *Season {
+	if x != nil {
+		return x.CurrentSeason
+	}
+	return nil
+}
+
+type Season struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Dates use the YYYY-MM-DD format.
+	StartDate       string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate         string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	CurrentMatchday *int32 `protobuf:"varint,4,opt,name=current_matchday,json=currentMatchday,proto3,oneof" json:"current_matchday,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Season) Reset() {
+	*x = Season{}
+	mi := &file_football_v1_football_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Season) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Season) ProtoMessage() {}
+
+func (x *Season) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Season.ProtoReflect.Descriptor instead.
+func (*Season) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Season) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Season) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Season) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *Season) GetCurrentMatchday() int32 {
+	if x != nil && x.CurrentMatchday != nil {
+		return *x.CurrentMatchday
+	}
+	return 0
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName     string                 `protobuf:"bytes,3,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	Tla           string                 `protobuf:"bytes,4,opt,name=tla,proto3" json:"tla,omitempty"`
+	Crest         string                 `protobuf:"bytes,5,opt,name=crest,proto3" json:"crest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_football_v1_football_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Team) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Team) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *Team) GetTla() string {
+	if x != nil {
+		return x.Tla
+	}
+	return ""
+}
+
+func (x *Team) GetCrest() string {
+	if x != nil {
+		return x.Crest
+	}
+	return ""
+}
+
+type ListCompetitionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCompetitionsRequest) Reset() {
+	*x = ListCompetitionsRequest{}
+	mi := &file_football_v1_football_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCompetitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCompetitionsRequest) ProtoMessage() {}
+
+func (x *ListCompetitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCompetitionsRequest.ProtoReflect.Descriptor instead.
+func (*ListCompetitionsRequest) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{3}
+}
+
+type ListCompetitionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Competitions  []*Competition         `protobuf:"bytes,1,rep,name=competitions,proto3" json:"competitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCompetitionsResponse) Reset() {
+	*x = ListCompetitionsResponse{}
+	mi := &file_football_v1_football_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCompetitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCompetitionsResponse) ProtoMessage() {}
+
+func (x *ListCompetitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCompetitionsResponse.ProtoReflect.Descriptor instead.
+func (*ListCompetitionsResponse) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCompetitionsResponse) GetCompetitions() []*Competition {
+	if x != nil {
+		return x.Competitions
+	}
+	return nil
+}
+
+type GetStandingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CompetitionId string                 `protobuf:"bytes,1,opt,name=competition_id,json=competitionId,proto3" json:"competition_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStandingsRequest) Reset() {
+	*x = GetStandingsRequest{}
+	mi := &file_football_v1_football_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStandingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStandingsRequest) ProtoMessage() {}
+
+func (x *GetStandingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStandingsRequest.ProtoReflect.Descriptor instead.
+func (*GetStandingsRequest) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetStandingsRequest) GetCompetitionId() string {
+	if x != nil {
+		return x.CompetitionId
+	}
+	return ""
+}
+
+type GetStandingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Season        *Season                `protobuf:"bytes,1,opt,name=season,proto3" json:"season,omitempty"`
+	Standings     []*Standing            `protobuf:"bytes,2,rep,name=standings,proto3" json:"standings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStandingsResponse) Reset() {
+	*x = GetStandingsResponse{}
+	mi := &file_football_v1_football_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStandingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStandingsResponse) ProtoMessage() {}
+
+func (x *GetStandingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStandingsResponse.ProtoReflect.Descriptor instead.
+func (*GetStandingsResponse) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStandingsResponse) GetSeason() *Season {
+	if x != nil {
+		return x.Season
+	}
+	return nil
+}
+
+func (x *GetStandingsResponse) GetStandings() []*Standing {
+	if x != nil {
+		return x.Standings
+	}
+	return nil
+}
+
+type Standing struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Stage string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	// TOTAL, HOME or AWAY.
+	Type          string      `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Group         *string     `protobuf:"bytes,3,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Table         []*TableRow `protobuf:"bytes,4,rep,name=table,proto3" json:"table,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Standing) Reset() {
+	*x = Standing{}
+	mi := &file_football_v1_football_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Standing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Standing) ProtoMessage() {}
+
+func (x *Standing) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Standing.ProtoReflect.Descriptor instead.
+func (*Standing) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Standing) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Standing) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Standing) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *Standing) GetTable() []*TableRow {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+type TableRow struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Position       int32                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	Team           *Team                  `protobuf:"bytes,2,opt,name=team,proto3" json:"team,omitempty"`
+	PlayedGames    int32                  `protobuf:"varint,3,opt,name=played_games,json=playedGames,proto3" json:"played_games,omitempty"`
+	Won            int32                  `protobuf:"varint,4,opt,name=won,proto3" json:"won,omitempty"`
+	Draw           int32                  `protobuf:"varint,5,opt,name=draw,proto3" json:"draw,omitempty"`
+	Lost           int32                  `protobuf:"varint,6,opt,name=lost,proto3" json:"lost,omitempty"`
+	Points         int32                  `protobuf:"varint,7,opt,name=points,proto3" json:"points,omitempty"`
+	GoalsFor       int32                  `protobuf:"varint,8,opt,name=goals_for,json=goalsFor,proto3" json:"goals_for,omitempty"`
+	GoalsAgainst   int32                  `protobuf:"varint,9,opt,name=goals_against,json=goalsAgainst,proto3" json:"goals_against,omitempty"`
+	GoalDifference int32                  `protobuf:"varint,10,opt,name=goal_difference,json=goalDifference,proto3" json:"goal_difference,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TableRow) Reset() {
+	*x = TableRow{}
+	mi := &file_football_v1_football_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableRow) ProtoMessage() {}
+
+func (x *TableRow) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableRow.ProtoReflect.Descriptor instead.
+func (*TableRow) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TableRow) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TableRow) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+func (x *TableRow) GetPlayedGames() int32 {
+	if x != nil {
+		return x.PlayedGames
+	}
+	return 0
+}
+
+func (x *TableRow) GetWon() int32 {
+	if x != nil {
+		return x.Won
+	}
+	return 0
+}
+
+func (x *TableRow) GetDraw() int32 {
+	if x != nil {
+		return x.Draw
+	}
+	return 0
+}
+
+func (x *TableRow) GetLost() int32 {
+	if x != nil {
+		return x.Lost
+	}
+	return 0
+}
+
+func (x *TableRow) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *TableRow) GetGoalsFor() int32 {
+	if x != nil {
+		return x.GoalsFor
+	}
+	return 0
+}
+
+func (x *TableRow) GetGoalsAgainst() int32 {
+	if x != nil {
+		return x.GoalsAgainst
+	}
+	return 0
+}
+
+func (x *TableRow) GetGoalDifference() int32 {
+	if x != nil {
+		return x.GoalDifference
+	}
+	return 0
+}
+
+type ListMatchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CompetitionId string                 `protobuf:"bytes,1,opt,name=competition_id,json=competitionId,proto3" json:"competition_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Dates use the YYYY-MM-DD format.
+	DateFrom      string `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo        string `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchesRequest) Reset() {
+	*x = ListMatchesRequest{}
+	mi := &file_football_v1_football_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesRequest) ProtoMessage() {}
+
+func (x *ListMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListMatchesRequest) GetCompetitionId() string {
+	if x != nil {
+		return x.CompetitionId
+	}
+	return ""
+}
+
+func (x *ListMatchesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListMatchesRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *ListMatchesRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+type ListMatchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*Match               `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchesResponse) Reset() {
+	*x = ListMatchesResponse{}
+	mi := &file_football_v1_football_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesResponse) ProtoMessage() {}
+
+func (x *ListMatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListMatchesResponse) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListMatchesResponse) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type Match struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CompetitionId string                 `protobuf:"bytes,2,opt,name=competition_id,json=competitionId,proto3" json:"competition_id,omitempty"`
+	UtcDate       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=utc_date,json=utcDate,proto3" json:"utc_date,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Matchday      *int32                 `protobuf:"varint,5,opt,name=matchday,proto3,oneof" json:"matchday,omitempty"`
+	Stage         string                 `protobuf:"bytes,6,opt,name=stage,proto3" json:"stage,omitempty"`
+	HomeTeam      *Team                  `protobuf:"bytes,7,opt,name=home_team,json=homeTeam,proto3" json:"home_team,omitempty"`
+	AwayTeam      *Team                  `protobuf:"bytes,8,opt,name=away_team,json=awayTeam,proto3" json:"away_team,omitempty"`
+	Score         *Score                 `protobuf:"bytes,9,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	mi := &file_football_v1_football_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Match) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Match) GetCompetitionId() string {
+	if x != nil {
+		return x.CompetitionId
+	}
+	return ""
+}
+
+func (x *Match) GetUtcDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UtcDate
+	}
+	return nil
+}
+
+func (x *Match) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Match) GetMatchday() int32 {
+	if x != nil && x.Matchday != nil {
+		return *x.Matchday
+	}
+	return 0
+}
+
+func (x *Match) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Match) GetHomeTeam() *Team {
+	if x != nil {
+		return x.HomeTeam
+	}
+	return nil
+}
+
+func (x *Match) GetAwayTeam() *Team {
+	if x != nil {
+		return x.AwayTeam
+	}
+	return nil
+}
+
+func (x *Match) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+type Score struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HOME_TEAM, AWAY_TEAM or DRAW once decided.
+	Winner        *string   `protobuf:"bytes,1,opt,name=winner,proto3,oneof" json:"winner,omitempty"`
+	FullTime      *ScoreSet `protobuf:"bytes,2,opt,name=full_time,json=fullTime,proto3" json:"full_time,omitempty"`
+	HalfTime      *ScoreSet `protobuf:"bytes,3,opt,name=half_time,json=halfTime,proto3" json:"half_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	mi := &file_football_v1_football_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Score) GetWinner() string {
+	if x != nil && x.Winner != nil {
+		return *x.Winner
+	}
+	return ""
+}
+
+func (x *Score) GetFullTime() *ScoreSet {
+	if x != nil {
+		return x.FullTime
+	}
+	return nil
+}
+
+func (x *Score) GetHalfTime() *ScoreSet {
+	if x != nil {
+		return x.HalfTime
+	}
+	return nil
+}
+
+type ScoreSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Home          *int32                 `protobuf:"varint,1,opt,name=home,proto3,oneof" json:"home,omitempty"`
+	Away          *int32                 `protobuf:"varint,2,opt,name=away,proto3,oneof" json:"away,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreSet) Reset() {
+	*x = ScoreSet{}
+	mi := &file_football_v1_football_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreSet) ProtoMessage() {}
+
+func (x *ScoreSet) ProtoReflect() protoreflect.Message {
+	mi := &file_football_v1_football_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreSet.ProtoReflect.Descriptor instead.
+func (*ScoreSet) Descriptor() ([]byte, []int) {
+	return file_football_v1_football_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ScoreSet) GetHome() int32 {
+	if x != nil && x.Home != nil {
+		return *x.Home
+	}
+	return 0
+}
+
+func (x *ScoreSet) GetAway() int32 {
+	if x != nil && x.Away != nil {
+		return *x.Away
+	}
+	return 0
+}
+
+var File_football_v1_football_proto protoreflect.FileDescriptor
+
+const file_football_v1_football_proto_rawDesc = "" +
+	"\n" +
+	"\x1afootball/v1/football.proto\x12\x12futapp.football.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x88\x01\n" +
+	"\vCompetition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12A\n" +
+	"\x0ecurrent_season\x18\x04 \x01(\v2\x1a.futapp.football.v1.SeasonR\rcurrentSeason\"\x97\x01\n" +
+	"\x06Season\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\x12.\n" +
+	"\x10current_matchday\x18\x04 \x01(\x05H\x00R\x0fcurrentMatchday\x88\x01\x01B\x13\n" +
+	"\x11_current_matchday\"q\n" +
+	"\x04Team\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x03 \x01(\tR\tshortName\x12\x10\n" +
+	"\x03tla\x18\x04 \x01(\tR\x03tla\x12\x14\n" +
+	"\x05crest\x18\x05 \x01(\tR\x05crest\"\x19\n" +
+	"\x17ListCompetitionsRequest\"_\n" +
+	"\x18ListCompetitionsResponse\x12C\n" +
+	"\fcompetitions\x18\x01 \x03(\v2\x1f.futapp.football.v1.CompetitionR\fcompetitions\"<\n" +
+	"\x13GetStandingsRequest\x12%\n" +
+	"\x0ecompetition_id\x18\x01 \x01(\tR\rcompetitionId\"\x86\x01\n" +
+	"\x14GetStandingsResponse\x122\n" +
+	"\x06season\x18\x01 \x01(\v2\x1a.futapp.football.v1.SeasonR\x06season\x12:\n" +
+	"\tstandings\x18\x02 \x03(\v2\x1c.futapp.football.v1.StandingR\tstandings\"\x8d\x01\n" +
+	"\bStanding\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x19\n" +
+	"\x05group\x18\x03 \x01(\tH\x00R\x05group\x88\x01\x01\x122\n" +
+	"\x05table\x18\x04 \x03(\v2\x1c.futapp.football.v1.TableRowR\x05tableB\b\n" +
+	"\x06_group\"\xb4\x02\n" +
+	"\bTableRow\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x12,\n" +
+	"\x04team\x18\x02 \x01(\v2\x18.futapp.football.v1.TeamR\x04team\x12!\n" +
+	"\fplayed_games\x18\x03 \x01(\x05R\vplayedGames\x12\x10\n" +
+	"\x03won\x18\x04 \x01(\x05R\x03won\x12\x12\n" +
+	"\x04draw\x18\x05 \x01(\x05R\x04draw\x12\x12\n" +
+	"\x04lost\x18\x06 \x01(\x05R\x04lost\x12\x16\n" +
+	"\x06points\x18\a \x01(\x05R\x06points\x12\x1b\n" +
+	"\tgoals_for\x18\b \x01(\x05R\bgoalsFor\x12#\n" +
+	"\rgoals_against\x18\t \x01(\x05R\fgoalsAgainst\x12'\n" +
+	"\x0fgoal_difference\x18\n" +
+	" \x01(\x05R\x0egoalDifference\"\x89\x01\n" +
+	"\x12ListMatchesRequest\x12%\n" +
+	"\x0ecompetition_id\x18\x01 \x01(\tR\rcompetitionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tdate_from\x18\x03 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x04 \x01(\tR\x06dateTo\"J\n" +
+	"\x13ListMatchesResponse\x123\n" +
+	"\amatches\x18\x01 \x03(\v2\x19.futapp.football.v1.MatchR\amatches\"\xf0\x02\n" +
+	"\x05Match\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12%\n" +
+	"\x0ecompetition_id\x18\x02 \x01(\tR\rcompetitionId\x125\n" +
+	"\butc_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\autcDate\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\bmatchday\x18\x05 \x01(\x05H\x00R\bmatchday\x88\x01\x01\x12\x14\n" +
+	"\x05stage\x18\x06 \x01(\tR\x05stage\x125\n" +
+	"\thome_team\x18\a \x01(\v2\x18.futapp.football.v1.TeamR\bhomeTeam\x125\n" +
+	"\taway_team\x18\b \x01(\v2\x18.futapp.football.v1.TeamR\bawayTeam\x12/\n" +
+	"\x05score\x18\t \x01(\v2\x19.futapp.football.v1.ScoreR\x05scoreB\v\n" +
+	"\t_matchday\"\xa5\x01\n" +
+	"\x05Score\x12\x1b\n" +
+	"\x06winner\x18\x01 \x01(\tH\x00R\x06winner\x88\x01\x01\x129\n" +
+	"\tfull_time\x18\x02 \x01(\v2\x1c.futapp.football.v1.ScoreSetR\bfullTime\x129\n" +
+	"\thalf_time\x18\x03 \x01(\v2\x1c.futapp.football.v1.ScoreSetR\bhalfTimeB\t\n" +
+	"\a_winner\"N\n" +
+	"\bScoreSet\x12\x17\n" +
+	"\x04home\x18\x01 \x01(\x05H\x00R\x04home\x88\x01\x01\x12\x17\n" +
+	"\x04away\x18\x02 \x01(\x05H\x01R\x04away\x88\x01\x01B\a\n" +
+	"\x05_homeB\a\n" +
+	"\x05_away2\xc3\x02\n" +
+	"\x0fFootballService\x12m\n" +
+	"\x10ListCompetitions\x12+.futapp.football.v1.ListCompetitionsRequest\x1a,.futapp.football.v1.ListCompetitionsResponse\x12a\n" +
+	"\fGetStandings\x12'.futapp.football.v1.GetStandingsRequest\x1a(.futapp.football.v1.GetStandingsResponse\x12^\n" +
+	"\vListMatches\x12&.futapp.football.v1.ListMatchesRequest\x1a'.futapp.football.v1.ListMatchesResponseB5Z3github.com/fut-app/api/proto/football/v1;footballv1b\x06proto3"
+
+var (
+	file_football_v1_football_proto_rawDescOnce sync.Once
+	file_football_v1_football_proto_rawDescData []byte
+)
+
+func file_football_v1_football_proto_rawDescGZIP() []byte {
+	file_football_v1_football_proto_rawDescOnce.Do(func() {
+		file_football_v1_football_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_football_v1_football_proto_rawDesc), len(file_football_v1_football_proto_rawDesc)))
+	})
+	return file_football_v1_football_proto_rawDescData
+}
+
+var file_football_v1_football_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_football_v1_football_proto_goTypes = []any{
+	(*Competition)(nil),              // 0: futapp.football.v1.Competition
+	(*Season)(nil),                   // 1: futapp.football.v1.Season
+	(*Team)(nil),                     // 2: futapp.football.v1.Team
+	(*ListCompetitionsRequest)(nil),  // 3: futapp.football.v1.ListCompetitionsRequest
+	(*ListCompetitionsResponse)(nil), // 4: futapp.football.v1.ListCompetitionsResponse
+	(*GetStandingsRequest)(nil),      // 5: futapp.football.v1.GetStandingsRequest
+	(*GetStandingsResponse)(nil),     // 6: futapp.football.v1.GetStandingsResponse
+	(*Standing)(nil),                 // 7: futapp.football.v1.Standing
+	(*TableRow)(nil),                 // 8: futapp.football.v1.TableRow
+	(*ListMatchesRequest)(nil),       // 9: futapp.football.v1.ListMatchesRequest
+	(*ListMatchesResponse)(nil),      // 10: futapp.football.v1.ListMatchesResponse
+	(*Match)(nil),                    // 11: futapp.football.v1.Match
+	(*Score)(nil),                    // 12: futapp.football.v1.Score
+	(*ScoreSet)(nil),                 // 13: futapp.football.v1.ScoreSet
+	(*timestamppb.Timestamp)(nil),    // 14: google.protobuf.Timestamp
+}
+var file_football_v1_football_proto_depIdxs = []int32{
+	1,  // 0: futapp.football.v1.Competition.current_season:type_name -> futapp.football.v1.Season
+	0,  // 1: futapp.football.v1.ListCompetitionsResponse.competitions:type_name -> futapp.football.v1.Competition
+	1,  // 2: futapp.football.v1.GetStandingsResponse.season:type_name -> futapp.football.v1.Season
+	7,  // 3: futapp.football.v1.GetStandingsResponse.standings:type_name -> futapp.football.v1.Standing
+	8,  // 4: futapp.football.v1.Standing.table:type_name -> futapp.football.v1.TableRow
+	2,  // 5: futapp.football.v1.TableRow.team:type_name -> futapp.football.v1.Team
+	11, // 6: futapp.football.v1.ListMatchesResponse.matches:type_name -> futapp.football.v1.Match
+	14, // 7: futapp.football.v1.Match.utc_date:type_name -> google.protobuf.Timestamp
+	2,  // 8: futapp.football.v1.Match.home_team:type_name -> futapp.football.v1.Team
+	2,  // 9: futapp.football.v1.Match.away_team:type_name -> futapp.football.v1.Team
+	12, // 10: futapp.football.v1.Match.score:type_name -> futapp.football.v1.Score
+	13, // 11: futapp.football.v1.Score.full_time:type_name -> futapp.football.v1.ScoreSet
+	13, // 12: futapp.football.v1.Score.half_time:type_name -> futapp.football.v1.ScoreSet
+	3,  // 13: futapp.football.v1.FootballService.ListCompetitions:input_type -> futapp.football.v1.ListCompetitionsRequest
+	5,  // 14: futapp.football.v1.FootballService.GetStandings:input_type -> futapp.football.v1.GetStandingsRequest
+	9,  // 15: futapp.football.v1.FootballService.ListMatches:input_type -> futapp.football.v1.ListMatchesRequest
+	4,  // 16: futapp.football.v1.FootballService.ListCompetitions:output_type -> futapp.football.v1.ListCompetitionsResponse
+	6,  // 17: futapp.football.v1.FootballService.GetStandings:output_type -> futapp.football.v1.GetStandingsResponse
+	10, // 18: futapp.football.v1.FootballService.ListMatches:output_type -> futapp.football.v1.ListMatchesResponse
+	16, // [16:19] is the sub-list for method output_type
+	13, // [13:16] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_football_v1_football_proto_init() }
+func file_football_v1_football_proto_init() {
+	if File_football_v1_football_proto != nil {
+		return
+	}
+	file_football_v1_football_proto_msgTypes[1].OneofWrappers = []any{}
+	file_football_v1_football_proto_msgTypes[7].OneofWrappers = []any{}
+	file_football_v1_football_proto_msgTypes[11].OneofWrappers = []any{}
+	file_football_v1_football_proto_msgTypes[12].OneofWrappers = []any{}
+	file_football_v1_football_proto_msgTypes[13].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_football_v1_football_proto_rawDesc), len(file_football_v1_football_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_football_v1_football_proto_goTypes,
+		DependencyIndexes: file_football_v1_football_proto_depIdxs,
+		MessageInfos:      file_football_v1_football_proto_msgTypes,
+	}.Build()
+	File_football_v1_football_proto = out.File
+	file_football_v1_football_proto_goTypes = nil
+	file_football_v1_football_proto_depIdxs = nil
+}
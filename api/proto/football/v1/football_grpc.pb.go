@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: football/v1/football.proto
+
+package footballv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FootballService_ListCompetitions_FullMethodName = "/futapp.football.v1.FootballService/ListCompetitions"
+	FootballService_GetStandings_FullMethodName     = "/futapp.football.v1.FootballService/GetStandings"
+	FootballService_ListMatches_FullMethodName      = "/futapp.football.v1.FootballService/ListMatches"
+)
+
+// FootballServiceClient is the client API for FootballService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FootballService serves the football app data of the HTTP API. Ids are
+// the public ones, e.g. campeonato_2021 and time_057.
+type FootballServiceClient interface {
+	ListCompetitions(ctx context.Context, in *ListCompetitionsRequest, opts ...grpc.CallOption) (*ListCompetitionsResponse, error)
+	// GetStandings lists every table of a competition: overall, home and
+	// away, one per group in cups.
+	GetStandings(ctx context.Context, in *GetStandingsRequest, opts ...grpc.CallOption) (*GetStandingsResponse, error)
+	// ListMatches lists the matches of a competition, or of every
+	// competition when competition_id is empty.
+	ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error)
+}
+
+type footballServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFootballServiceClient(cc grpc.ClientConnInterface) FootballServiceClient {
+	return &footballServiceClient{cc}
+}
+
+func (c *footballServiceClient) ListCompetitions(ctx context.Context, in *ListCompetitionsRequest, opts ...grpc.CallOption) (*ListCompetitionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCompetitionsResponse)
+	err := c.cc.Invoke(ctx, FootballService_ListCompetitions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *footballServiceClient) GetStandings(ctx context.Context, in *GetStandingsRequest, opts ...grpc.CallOption) (*GetStandingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStandingsResponse)
+	err := c.cc.Invoke(ctx, FootballService_GetStandings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *footballServiceClient) ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMatchesResponse)
+	err := c.cc.Invoke(ctx, FootballService_ListMatches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FootballServiceServer is the server API for FootballService service.
+// All implementations must embed UnimplementedFootballServiceServer
+// for forward compatibility.
+//
+// FootballService serves the football app data of the HTTP API. Ids are
+// the public ones, e.g. campeonato_2021 and time_057.
+type FootballServiceServer interface {
+	ListCompetitions(context.Context, *ListCompetitionsRequest) (*ListCompetitionsResponse, error)
+	// GetStandings lists every table of a competition: overall, home and
+	// away, one per group in cups.
+	GetStandings(context.Context, *GetStandingsRequest) (*GetStandingsResponse, error)
+	// ListMatches lists the matches of a competition, or of every
+	// competition when competition_id is empty.
+	ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error)
+	mustEmbedUnimplementedFootballServiceServer()
+}
+
+// UnimplementedFootballServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFootballServiceServer struct{}
+
+func (UnimplementedFootballServiceServer) ListCompetitions(context.Context, *ListCompetitionsRequest) (*ListCompetitionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCompetitions not implemented")
+}
+func (UnimplementedFootballServiceServer) GetStandings(context.Context, *GetStandingsRequest) (*GetStandingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStandings not implemented")
+}
+func (UnimplementedFootballServiceServer) ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListMatches not implemented")
+}
+func (UnimplementedFootballServiceServer) mustEmbedUnimplementedFootballServiceServer() {}
+func (UnimplementedFootballServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeFootballServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FootballServiceServer will
+// result in compilation errors.
+type UnsafeFootballServiceServer interface {
+	mustEmbedUnimplementedFootballServiceServer()
+}
+
+func RegisterFootballServiceServer(s grpc.ServiceRegistrar, srv FootballServiceServer) {
+	// If the following call panics, it indicates UnimplementedFootballServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FootballService_ServiceDesc, srv)
+}
+
+func _FootballService_ListCompetitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCompetitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FootballServiceServer).ListCompetitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FootballService_ListCompetitions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FootballServiceServer).ListCompetitions(ctx, req.(*ListCompetitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FootballService_GetStandings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStandingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FootballServiceServer).GetStandings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FootballService_GetStandings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FootballServiceServer).GetStandings(ctx, req.(*GetStandingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FootballService_ListMatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FootballServiceServer).ListMatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FootballService_ListMatches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FootballServiceServer).ListMatches(ctx, req.(*ListMatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FootballService_ServiceDesc is the grpc.ServiceDesc for FootballService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FootballService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "futapp.football.v1.FootballService",
+	HandlerType: (*FootballServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCompetitions",
+			Handler:    _FootballService_ListCompetitions_Handler,
+		},
+		{
+			MethodName: "GetStandings",
+			Handler:    _FootballService_GetStandings_Handler,
+		},
+		{
+			MethodName: "ListMatches",
+			Handler:    _FootballService_ListMatches_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "football/v1/football.proto",
+}
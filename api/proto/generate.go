@@ -0,0 +1,8 @@
+// Package proto holds the gRPC API definitions. After editing a .proto
+// file, regenerate the Go code with go generate ./api/proto. It needs
+// protoc on the PATH; the plugins are built at the versions pinned by the
+// tool directives of go.mod.
+package proto
+
+//go:generate go build -o ../../bin/ google.golang.org/protobuf/cmd/protoc-gen-go google.golang.org/grpc/cmd/protoc-gen-go-grpc
+//go:generate protoc --plugin=protoc-gen-go=../../bin/protoc-gen-go --plugin=protoc-gen-go-grpc=../../bin/protoc-gen-go-grpc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative auth/v1/auth.proto football/v1/football.proto
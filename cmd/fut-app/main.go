@@ -5,23 +5,36 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Calendars resolve IANA zones, which slim images do not ship.
+	_ "time/tzdata"
 
 	"github.com/fut-app/internal/config"
 	"github.com/fut-app/internal/controller"
+	"github.com/fut-app/internal/graph"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
 	"github.com/fut-app/internal/router"
+	"github.com/fut-app/internal/rpc"
 	"github.com/fut-app/internal/service"
 	"github.com/fut-app/pkg/gorm"
-	"github.com/fut-app/pkg/postgres"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/logger"
+	"github.com/fut-app/pkg/mailer"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-func shutdownAPP(app *echo.Echo) {
+// shutdownAPP drains the HTTP and gRPC servers once the process is
+// signalled. The returned channel is closed when both have stopped.
+func shutdownAPP(app *echo.Echo, grpcServer *grpc.Server, timeout time.Duration, log *slog.Logger) <-chan struct{} {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(
 		quit,
@@ -30,44 +43,220 @@ func shutdownAPP(app *echo.Echo) {
 		syscall.SIGQUIT,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		<-quit
-		app.Logger.Print("gracefully shutting down...")
+		log.Info("gracefully shutting down...")
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		grpcStopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
 
 		if err := app.Shutdown(ctx); err != nil {
-			app.Logger.Errorf("error during shutdown: %v", err)
+			log.Error("error during shutdown", "error", err)
+		}
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			// Cancels the calls still in flight.
+			grpcServer.Stop()
 		}
 
-		app.Logger.Info("server shutdown completed")
+		log.Info("server shutdown completed")
 	}()
+	return done
 }
 
 func addressFormater(host string, port string) string {
 	return fmt.Sprintf("%s:%s", host, port)
 }
 
+// printConfig writes the effective config with secrets masked and exits.
+func printConfig(cfg *config.Config, validationErr error) {
+	out, err := cfg.Masked()
+	if err != nil {
+		slog.Error("failed to render config", "error", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+
+	if validationErr != nil {
+		fmt.Fprintf(os.Stderr, "\ninvalid config:\n%v\n", validationErr)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
 func main() {
+	cfg, err := config.NewConfig(os.Args[1:])
+	if cfg.PrintConfig {
+		printConfig(cfg, err)
+	}
+	if err != nil {
+		slog.Error("failed to instatiate configs", "error", err)
+		os.Exit(1)
+	}
+
+	log, err := logger.NewLogger(os.Stdout, cfg.Log)
+	if err != nil {
+		slog.Error("failed to instatiate logger", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(log)
+
 	app := echo.New()
-	app.Use(middleware.Logger())
-	app.Use(middleware.Recover())
+	app.HideBanner = true
+	app.IPExtractor = middleware.IPExtractor(cfg.App.TrustedProxies)
+	app.Use(middleware.RequestID())
+	app.Use(middleware.RequestLogger(log))
+	app.Use(echomiddleware.Recover())
 
-	cfg, err := config.NewConfig()
+	dialector, err := gorm.NewDialector(cfg)
+	if err != nil {
+		log.Error("failed to select database dialector", "error", err)
+		os.Exit(1)
+	}
+	db, err := gorm.NewGorm(dialector, cfg.Database, log)
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	passwords, err := hasher.New(cfg.Auth.PasswordHash)
 	if err != nil {
-		app.Logger.Fatalf("failed to instatiate configs: %v", err)
+		log.Error("failed to instantiate password hasher", "error", err)
+		os.Exit(1)
 	}
+	if cfg.Database.AutoMigrate {
+		if err := db.AutoMigrate(model.Models()...); err != nil {
+			log.Error("failed to do automigrate", "error", err)
+			os.Exit(1)
+		}
+		credentials := service.NewDatabase(db, log)
+		if _, err := credentials.BackfillScopes(context.Background()); err != nil {
+			log.Error("failed to backfill credential scopes", "error", err)
+			os.Exit(1)
+		}
+		if cfg.Auth.BootstrapAdmin.User != "" {
+			policy := service.NewPasswordPolicy(cfg.Auth.Password)
+			if _, err := credentials.BootstrapAdmin(context.Background(), cfg.Auth.BootstrapAdmin, policy, passwords); err != nil {
+				log.Error("failed to bootstrap admin", "error", err)
+				os.Exit(1)
+			}
+		}
+	}
+	mail, err := mailer.NewMailer(cfg.Mail)
+	if err != nil {
+		log.Error("failed to instantiate mailer", "error", err)
+		os.Exit(1)
+	}
+	authService := service.NewDatabase(db, log)
+	auditService := service.NewAuditDatabase(db, log)
+	authController := controller.NewAuth(authService, &auditService, cfg.JWT.SecretKey, cfg.Auth, passwords, mail, log)
+	auditController := controller.NewAudit(auditService, log)
+	organizationsController := controller.NewOrganizations(authService, &auditService, log)
+	oidcController, err := controller.NewOIDC(context.Background(), authController, cfg.Auth.OIDC)
+	if err != nil {
+		log.Error("failed to discover oidc issuer", "issuer", cfg.Auth.OIDC.Issuer, "error", err)
+		os.Exit(1)
+	}
+	apiKeysService := service.NewAPIKeysDatabase(db, log)
+	apiKeysController := controller.NewAPIKeys(apiKeysService, authService, log)
+	footballService := service.NewFootball(cfg.FootballAPP.URL, cfg.FootballAPP.Token, cfg.FootballAPP.Timeout, log)
+	footbalController := controller.NewChampion(footballService, log)
+	favoritesService := service.NewFavoritesDatabase(db, cfg.Favorites.Max, log)
+	favoritesController := controller.NewFavorites(favoritesService, authService, footballService, cfg.Favorites, log)
+	calendarTokensService := service.NewCalendarTokensDatabase(db, log)
+	calendarController := controller.NewCalendar(footballService, calendarTokensService, authService, cfg.Calendar, log)
+	webhooksService := service.NewWebhooksDatabase(db, log)
+	webhooksController := controller.NewWebhooks(webhooksService, authService, log)
+	liveScores := service.NewLiveScores(footballService, cfg.Live.PollInterval, log)
+	liveController := controller.NewLive(liveScores, cfg.Live.Heartbeat, log)
+	hub := service.NewHub(liveScores, footballService, cfg.Socket.PollInterval, cfg.Socket.MaxSubscriptions, log)
+	socketController := controller.NewSocket(hub, cfg.Socket.PingInterval, log)
+	graphSchema, err := graph.NewSchema(&footballService, cfg.GraphQL, log)
+	if err != nil {
+		log.Error("failed to build graphql schema", "error", err)
+		os.Exit(1)
+	}
+	graphQLController := controller.NewGraphQL(&graphSchema, log)
+	docsController := controller.NewDocs()
+	control := controller.NewController(
+		authController,
+		oidcController,
+		apiKeysController,
+		auditController,
+		organizationsController,
+		footbalController,
+		favoritesController,
+		calendarController,
+		webhooksController,
+		liveController,
+		socketController,
+		graphQLController,
+		docsController,
+	)
 
-	dialector := postgres.NewPostgres(cfg.Postgres)
-	db := gorm.NewGorm(dialector)
-	authService := service.NewDatabase(db)
-	authController := controller.NewAuth(authService, cfg.JWT.SecretKey)
-	footballService := service.NewFootball(cfg.FootballAPP.URL)
-	footbalController := controller.NewChampion(footballService)
-	control := controller.NewController(authController, footbalController)
+	var rateLimitStore middleware.RateLimitStore = middleware.NewMemoryRateLimitStore()
+	if cfg.RateLimit.Store == "database" {
+		rateLimitService := service.NewRateLimitDatabase(db, log)
+		rateLimitStore = &rateLimitService
+	}
+	rateLimit := middleware.RateLimit(middleware.NewRateLimitPolicy(cfg.RateLimit), rateLimitStore)
+	authRateLimit := middleware.RateLimit(middleware.NewAuthRateLimitPolicy(cfg.RateLimit), rateLimitStore)
+
+	router.Setup(app, control, cfg.JWT.SecretKey, &apiKeysService, &authService, &calendarTokensService, rateLimit, authRateLimit)
+	// Streams never finish on their own, so end them before draining.
+	app.Server.RegisterOnShutdown(liveScores.Close)
+	app.Server.RegisterOnShutdown(hub.Close)
+
+	if cfg.Webhook.Enabled {
+		poller := service.NewWebhookPoller(footballService, webhooksService, cfg.Webhook, log)
+		go poller.Run(context.Background())
+	}
+
+	var grpcOptions []grpc.ServerOption
+	if cfg.GRPC.Enabled && cfg.GRPC.TLSCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.GRPC.TLSCertFile, cfg.GRPC.TLSKeyFile)
+		if err != nil {
+			log.Error("failed to load grpc tls certificate", "error", err)
+			os.Exit(1)
+		}
+		grpcOptions = append(grpcOptions, grpc.Creds(creds))
+	}
+	grpcServer := rpc.NewServer(
+		rpc.NewAuthServer(authService, &auditService, cfg.JWT.SecretKey, cfg.Auth, passwords, log),
+		rpc.NewFootballServer(&footballService, log),
+		cfg.JWT.SecretKey,
+		&authService,
+		rpc.RateLimiter{
+			Callers:   middleware.NewRateLimitPolicy(cfg.RateLimit),
+			Anonymous: middleware.NewAuthRateLimitPolicy(cfg.RateLimit),
+			Store:     rateLimitStore,
+		},
+		log,
+		grpcOptions...,
+	)
+	if cfg.GRPC.Enabled {
+		listener, err := net.Listen("tcp", addressFormater(cfg.App.Host, cfg.GRPC.Port))
+		if err != nil {
+			log.Error("failed to listen for grpc", "port", cfg.GRPC.Port, "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			log.Info("grpc server started", "address", listener.Addr().String())
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Error("failed to serve grpc", "error", err)
+			}
+		}()
+	}
 
-	router.Setup(app, control, cfg.JWT.SecretKey)
+	done := shutdownAPP(app, grpcServer, cfg.App.ShutdownTimeout, log)
 
 	err = app.Start(
 		addressFormater(
@@ -77,8 +266,10 @@ func main() {
 	)
 
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		slog.Error("failed to start server", "error", err)
+		log.Error("failed to start server", "error", err)
+		os.Exit(1)
 	}
 
-	shutdownAPP(app)
+	<-done
+	authController.WaitMails()
 }
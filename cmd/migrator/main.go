@@ -1,33 +1,75 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
 
 	"github.com/fut-app/internal/config"
 	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
 	"github.com/fut-app/pkg/gorm"
-	"github.com/fut-app/pkg/postgres"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/logger"
 )
 
 const filename = "payload.json"
 
 func main() {
-	log.Println("migrator: starting migration")
-	cfg, err := config.NewConfig()
+	cfg, err := config.NewConfig(os.Args[1:])
 	if err != nil {
-		log.Fatal("migrator: failed to initiate config")
+		slog.Error("migrator: failed to initiate config", "error", err)
+		os.Exit(1)
 	}
-	dialector := postgres.NewPostgres(cfg.Postgres)
-	db := gorm.NewGorm(dialector)
 
-	log.Println("migrator: initiang automigrate")
+	log, err := logger.NewLogger(os.Stdout, cfg.Log)
+	if err != nil {
+		slog.Error("migrator: failed to initiate logger", "error", err)
+		os.Exit(1)
+	}
 
-	err = db.AutoMigrate(
-		model.AuthRequest{},
-	)
+	log.Info("migrator: starting migration")
+	dialector, err := gorm.NewDialector(cfg)
+	if err != nil {
+		log.Error("migrator: failed to select database dialector", "error", err)
+		os.Exit(1)
+	}
+	db, err := gorm.NewGorm(dialector, cfg.Database, log)
 	if err != nil {
-		log.Fatalf("migrator: failed to do automigrate: %s", err.Error())
+		log.Error("migrator: failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("migrator: initiang automigrate")
+
+	err = db.AutoMigrate(model.Models()...)
+	if err != nil {
+		log.Error("migrator: failed to do automigrate", "error", err)
+		os.Exit(1)
+	}
+
+	credentials := service.NewDatabase(db, log)
+	backfilled, err := credentials.BackfillScopes(context.Background())
+	if err != nil {
+		log.Error("migrator: failed to backfill credential scopes", "error", err)
+		os.Exit(1)
+	}
+	log.Info("migrator: backfilled credential scopes", "credentials", backfilled)
+
+	if cfg.Auth.BootstrapAdmin.User != "" {
+		passwords, err := hasher.New(cfg.Auth.PasswordHash)
+		if err != nil {
+			log.Error("migrator: failed to instantiate password hasher", "error", err)
+			os.Exit(1)
+		}
+		policy := service.NewPasswordPolicy(cfg.Auth.Password)
+		created, err := credentials.BootstrapAdmin(context.Background(), cfg.Auth.BootstrapAdmin, policy, passwords)
+		if err != nil {
+			log.Error("migrator: failed to bootstrap admin", "error", err)
+			os.Exit(1)
+		}
+		log.Info("migrator: bootstrapped admin", "user", cfg.Auth.BootstrapAdmin.User, "created", created)
 	}
 
-	log.Println("migrator: sucessfuly automigrate!")
+	log.Info("migrator: sucessfuly automigrate!")
 }
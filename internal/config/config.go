@@ -1,40 +1,479 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
 	goenv "github.com/Netflix/go-env"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFileEnv = "CONFIG_FILE"
+	maskedValue   = "******"
 )
 
 type Config struct {
-	App         AppConfig
-	Postgres    PostgresConfig
-	JWT         JWT
-	FootballAPP FootballAPP
+	App         AppConfig       `yaml:"app"`
+	Database    DatabaseConfig  `yaml:"database"`
+	Postgres    PostgresConfig  `yaml:"postgres"`
+	SQLite      SQLiteConfig    `yaml:"sqlite"`
+	JWT         JWT             `yaml:"jwt"`
+	Auth        AuthConfig      `yaml:"auth"`
+	Mail        MailConfig      `yaml:"mail"`
+	RateLimit   RateLimitConfig `yaml:"rate_limit"`
+	Favorites   FavoritesConfig `yaml:"favorites"`
+	Webhook     WebhookConfig   `yaml:"webhook"`
+	Live        LiveConfig      `yaml:"live"`
+	Socket      SocketConfig    `yaml:"socket"`
+	Calendar    CalendarConfig  `yaml:"calendar"`
+	GraphQL     GraphQLConfig   `yaml:"graphql"`
+	GRPC        GRPCConfig      `yaml:"grpc"`
+	FootballAPP FootballAPP     `yaml:"football_app"`
+	Log         LogConfig       `yaml:"log"`
+
+	PrintConfig bool `yaml:"-"`
 }
 
 type AppConfig struct {
-	Host string `env:"APP_HOST,required"`
-	Port string `env:"APP_PORT,required"`
-	Name string `env:"APP_NAME,required"`
+	Host            string        `env:"APP_HOST" yaml:"host"`
+	Port            string        `env:"APP_PORT" yaml:"port"`
+	Name            string        `env:"APP_NAME" yaml:"name"`
+	ShutdownTimeout time.Duration `env:"APP_SHUTDOWN_TIMEOUT" yaml:"shutdown_timeout"`
+	// TrustedProxies holds the CIDRs allowed to set X-Forwarded-For,
+	// separated by | in the environment. Without any, the client IP is the
+	// peer address.
+	TrustedProxies []string `env:"APP_TRUSTED_PROXIES" yaml:"trusted_proxies"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type DatabaseConfig struct {
+	Driver          string        `env:"DB_DRIVER" yaml:"driver"`
+	AutoMigrate     bool          `env:"DB_AUTO_MIGRATE" yaml:"auto_migrate"`
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" yaml:"max_open_conns"`
+	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" yaml:"conn_max_idle_time"`
+	ConnectRetries  int           `env:"DB_CONNECT_RETRIES" yaml:"connect_retries"`
+	RetryBackoff    time.Duration `env:"DB_RETRY_BACKOFF" yaml:"retry_backoff"`
 }
 
 type PostgresConfig struct {
-	Host     string `env:"PG_HOST,required"`
-	Port     string `env:"PG_PORT,required"`
-	User     string `env:"PG_USER,required"`
-	Password string `env:"PG_PASSWORD,required"`
-	Name     string `env:"PG_NAME,required"`
+	Host             string        `env:"PG_HOST" yaml:"host"`
+	Port             string        `env:"PG_PORT" yaml:"port"`
+	User             string        `env:"PG_USER" yaml:"user"`
+	Password         string        `env:"PG_PASSWORD" yaml:"password" secret:"true"`
+	Name             string        `env:"PG_NAME" yaml:"name"`
+	SSLMode          string        `env:"PG_SSL_MODE" yaml:"ssl_mode"`
+	SSLRootCert      string        `env:"PG_SSL_ROOT_CERT" yaml:"ssl_root_cert"`
+	ConnectTimeout   time.Duration `env:"PG_CONNECT_TIMEOUT" yaml:"connect_timeout"`
+	StatementTimeout time.Duration `env:"PG_STATEMENT_TIMEOUT" yaml:"statement_timeout"`
+}
+
+type SQLiteConfig struct {
+	Path string `env:"SQLITE_PATH" yaml:"path"`
 }
 
 type JWT struct {
-	SecretKey string `env:"JWT_SECRET_KEY,required"`
+	SecretKey string `env:"JWT_SECRET_KEY" yaml:"secret_key" secret:"true"`
+}
+
+type AuthConfig struct {
+	RequireAdmin2FA bool          `env:"AUTH_REQUIRE_ADMIN_2FA" yaml:"require_admin_2fa"`
+	TOTPIssuer      string        `env:"AUTH_TOTP_ISSUER" yaml:"totp_issuer"`
+	ChallengeTTL    time.Duration `env:"AUTH_MFA_CHALLENGE_TTL" yaml:"mfa_challenge_ttl"`
+	MFAMaxFailures  int           `env:"AUTH_MFA_MAX_FAILURES" yaml:"mfa_max_failures"`
+	MFALockout      time.Duration `env:"AUTH_MFA_LOCKOUT" yaml:"mfa_lockout"`
+	ResetTokenTTL   time.Duration `env:"AUTH_RESET_TOKEN_TTL" yaml:"reset_token_ttl"`
+	ResetURL        string        `env:"AUTH_RESET_URL" yaml:"reset_url"`
+	MaxSessionAge   time.Duration `env:"AUTH_MAX_SESSION_AGE" yaml:"max_session_age"`
+	// IntrospectionClients holds client_id:client_secret pairs allowed to
+	// call /auth/introspect, separated by | in the environment.
+	IntrospectionClients []string `env:"AUTH_INTROSPECTION_CLIENTS" yaml:"introspection_clients" secret:"true"`
+
+	Password       PasswordPolicyConfig `yaml:"password"`
+	PasswordHash   PasswordHashConfig   `yaml:"password_hash"`
+	OIDC           OIDCConfig           `yaml:"oidc"`
+	BootstrapAdmin BootstrapAdminConfig `yaml:"bootstrap_admin"`
+}
+
+// BootstrapAdminConfig names the platform admin created, or promoted when
+// the user already exists, on migration. Without one nobody could create
+// the first credential.
+type BootstrapAdminConfig struct {
+	User     string `env:"AUTH_BOOTSTRAP_ADMIN_USER" yaml:"user"`
+	Password string `env:"AUTH_BOOTSTRAP_ADMIN_PASSWORD" yaml:"password" secret:"true"`
+	Email    string `env:"AUTH_BOOTSTRAP_ADMIN_EMAIL" yaml:"email"`
+}
+
+// OIDCConfig enables login with an external OpenID Connect provider when
+// Issuer is set.
+type OIDCConfig struct {
+	Issuer        string   `env:"AUTH_OIDC_ISSUER" yaml:"issuer"`
+	ClientID      string   `env:"AUTH_OIDC_CLIENT_ID" yaml:"client_id"`
+	ClientSecret  string   `env:"AUTH_OIDC_CLIENT_SECRET" yaml:"client_secret" secret:"true"`
+	RedirectURL   string   `env:"AUTH_OIDC_REDIRECT_URL" yaml:"redirect_url"`
+	Scopes        []string `env:"AUTH_OIDC_SCOPES" yaml:"scopes"`
+	AutoProvision bool     `env:"AUTH_OIDC_AUTO_PROVISION" yaml:"auto_provision"`
+	DefaultRole   string   `env:"AUTH_OIDC_DEFAULT_ROLE" yaml:"default_role"`
+	DefaultScopes string   `env:"AUTH_OIDC_DEFAULT_SCOPES" yaml:"default_scopes"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength     int  `env:"PASSWORD_MIN_LENGTH" yaml:"min_length"`
+	RequireUpper  bool `env:"PASSWORD_REQUIRE_UPPER" yaml:"require_upper"`
+	RequireLower  bool `env:"PASSWORD_REQUIRE_LOWER" yaml:"require_lower"`
+	RequireDigit  bool `env:"PASSWORD_REQUIRE_DIGIT" yaml:"require_digit"`
+	RequireSymbol bool `env:"PASSWORD_REQUIRE_SYMBOL" yaml:"require_symbol"`
+}
+
+type PasswordHashConfig struct {
+	Algorithm         string `env:"PASSWORD_HASH_ALGORITHM" yaml:"algorithm"`
+	Argon2Memory      uint32 `env:"PASSWORD_ARGON2_MEMORY" yaml:"argon2_memory"`
+	Argon2Iterations  uint32 `env:"PASSWORD_ARGON2_ITERATIONS" yaml:"argon2_iterations"`
+	Argon2Parallelism uint8  `env:"PASSWORD_ARGON2_PARALLELISM" yaml:"argon2_parallelism"`
+	BcryptCost        int    `env:"PASSWORD_BCRYPT_COST" yaml:"bcrypt_cost"`
+}
+
+type MailConfig struct {
+	Driver string `env:"MAIL_DRIVER" yaml:"driver"`
+	// Development allows the stdout and file drivers, which write one-time
+	// reset tokens where anyone reading the logs can use them.
+	Development  bool   `env:"MAIL_DEVELOPMENT" yaml:"development"`
+	From         string `env:"MAIL_FROM" yaml:"from"`
+	SMTPHost     string `env:"MAIL_SMTP_HOST" yaml:"smtp_host"`
+	SMTPPort     string `env:"MAIL_SMTP_PORT" yaml:"smtp_port"`
+	SMTPUser     string `env:"MAIL_SMTP_USER" yaml:"smtp_user"`
+	SMTPPassword string `env:"MAIL_SMTP_PASSWORD" yaml:"smtp_password" secret:"true"`
+	// SMTPTimeout bounds dialing and the whole exchange with the relay.
+	SMTPTimeout time.Duration `env:"MAIL_SMTP_TIMEOUT" yaml:"smtp_timeout"`
+	FilePath    string        `env:"MAIL_FILE_PATH" yaml:"file_path"`
+}
+
+// RateLimitConfig throttles requests per authenticated user, or per client
+// IP when there is none, over a sliding window.
+type RateLimitConfig struct {
+	Enabled bool          `env:"RATE_LIMIT_ENABLED" yaml:"enabled"`
+	Store   string        `env:"RATE_LIMIT_STORE" yaml:"store"`
+	Window  time.Duration `env:"RATE_LIMIT_WINDOW" yaml:"window"`
+	// Default applies to anonymous clients and roles missing from Roles.
+	Default int `env:"RATE_LIMIT_DEFAULT" yaml:"default"`
+	// Roles holds role:limit pairs, separated by | in the environment.
+	Roles []string `env:"RATE_LIMIT_ROLES" yaml:"roles"`
+	// Auth applies per client IP to the /auth endpoints, before any
+	// authentication.
+	Auth int `env:"RATE_LIMIT_AUTH" yaml:"auth"`
+}
+
+// FavoritesConfig bounds the favorites of each user and how long the feed
+// reuses the matches and standings fetched from the football app.
+type FavoritesConfig struct {
+	Max          int           `env:"FAVORITES_MAX" yaml:"max"`
+	FeedCacheTTL time.Duration `env:"FAVORITES_FEED_CACHE_TTL" yaml:"feed_cache_ttl"`
+}
+
+// WebhookConfig drives the poller turning football app changes into
+// webhook deliveries. It is off by default: the poller keeps match state in
+// memory, so enable it on a single replica to avoid duplicates.
+type WebhookConfig struct {
+	Enabled      bool          `env:"WEBHOOK_ENABLED" yaml:"enabled"`
+	PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" yaml:"poll_interval"`
+	Timeout      time.Duration `env:"WEBHOOK_TIMEOUT" yaml:"timeout"`
+	MaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" yaml:"max_attempts"`
+	// Workers bounds the deliveries sent at once.
+	Workers int `env:"WEBHOOK_WORKERS" yaml:"workers"`
+	// Backoff is the wait before the first retry, doubled on each attempt.
+	Backoff time.Duration `env:"WEBHOOK_BACKOFF" yaml:"backoff"`
+	// AllowPrivateNetworks lets webhooks target loopback and private
+	// addresses, which is only meant for local development.
+	AllowPrivateNetworks bool `env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" yaml:"allow_private_networks"`
+}
+
+// LiveConfig tunes the live scores stream. Every client shares one poll of
+// the football app per PollInterval.
+type LiveConfig struct {
+	PollInterval time.Duration `env:"LIVE_POLL_INTERVAL" yaml:"poll_interval"`
+	// Heartbeat keeps idle streams open through proxies.
+	Heartbeat time.Duration `env:"LIVE_HEARTBEAT" yaml:"heartbeat"`
+}
+
+// SocketConfig tunes the WebSocket hub. Live matches come from the same
+// poller as the live stream; standings and scorers of subscribed
+// competitions are polled every PollInterval.
+type SocketConfig struct {
+	PollInterval     time.Duration `env:"SOCKET_POLL_INTERVAL" yaml:"poll_interval"`
+	PingInterval     time.Duration `env:"SOCKET_PING_INTERVAL" yaml:"ping_interval"`
+	MaxSubscriptions int           `env:"SOCKET_MAX_SUBSCRIPTIONS" yaml:"max_subscriptions"`
+}
+
+// CalendarConfig shapes the iCalendar fixtures. Event times are written in
+// UTC; TimeZone is the default display zone, overridable per request.
+type CalendarConfig struct {
+	TimeZone      string        `env:"CALENDAR_TIME_ZONE" yaml:"time_zone"`
+	MatchDuration time.Duration `env:"CALENDAR_MATCH_DURATION" yaml:"match_duration"`
+	// Refresh suggests how often calendar apps reload the subscription.
+	Refresh time.Duration `env:"CALENDAR_REFRESH" yaml:"refresh"`
+}
+
+// GraphQLConfig bounds the queries /graphql accepts. Depth counts nested
+// fields; complexity weighs fields calling the football app and multiplies
+// those selected inside lists.
+type GraphQLConfig struct {
+	MaxDepth      int `env:"GRAPHQL_MAX_DEPTH" yaml:"max_depth"`
+	MaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY" yaml:"max_complexity"`
+}
+
+// GRPCConfig serves the auth and football APIs over gRPC on Port, next to
+// the HTTP server on the same host. It serves TLS with the certificate and
+// key files, or plaintext only when Insecure is set.
+type GRPCConfig struct {
+	Enabled     bool   `env:"GRPC_ENABLED" yaml:"enabled"`
+	Port        string `env:"GRPC_PORT" yaml:"port"`
+	TLSCertFile string `env:"GRPC_TLS_CERT_FILE" yaml:"tls_cert_file"`
+	TLSKeyFile  string `env:"GRPC_TLS_KEY_FILE" yaml:"tls_key_file"`
+	Insecure    bool   `env:"GRPC_INSECURE" yaml:"insecure"`
 }
 
 type FootballAPP struct {
-	URL string `env:"FOOTBALL_APP_BASE_URL,required"`
+	URL     string        `env:"FOOTBALL_APP_BASE_URL" yaml:"base_url"`
+	Token   string        `env:"FOOTBALL_APP_TOKEN" yaml:"token" secret:"true"`
+	Timeout time.Duration `env:"FOOTBALL_APP_TIMEOUT" yaml:"timeout"`
+}
+
+type LogConfig struct {
+	Level  string `env:"LOG_LEVEL" yaml:"level"`
+	Format string `env:"LOG_FORMAT" yaml:"format"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		App: AppConfig{
+			Host:            "0.0.0.0",
+			Port:            "8000",
+			Name:            "fut-app",
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnectRetries:  5,
+			RetryBackoff:    time.Second,
+		},
+		Postgres: PostgresConfig{
+			Port:             "5432",
+			SSLMode:          "disable",
+			ConnectTimeout:   5 * time.Second,
+			StatementTimeout: 30 * time.Second,
+		},
+		SQLite: SQLiteConfig{
+			Path: "fut-app.db",
+		},
+		Auth: AuthConfig{
+			TOTPIssuer:     "fut-app",
+			ChallengeTTL:   5 * time.Minute,
+			MFAMaxFailures: 5,
+			MFALockout:     15 * time.Minute,
+			ResetTokenTTL:  30 * time.Minute,
+			MaxSessionAge:  24 * time.Hour,
+			Password: PasswordPolicyConfig{
+				MinLength:    10,
+				RequireUpper: true,
+				RequireLower: true,
+				RequireDigit: true,
+			},
+			PasswordHash: PasswordHashConfig{
+				Algorithm:         "argon2id",
+				Argon2Memory:      64 * 1024,
+				Argon2Iterations:  3,
+				Argon2Parallelism: 2,
+				BcryptCost:        10,
+			},
+			OIDC: OIDCConfig{
+				Scopes:        []string{"openid", "profile", "email"},
+				AutoProvision: true,
+				DefaultRole:   "user",
+				DefaultScopes: "competitions:read favorites:write webhooks:write calendar:write",
+			},
+		},
+		Mail: MailConfig{
+			Driver:      "smtp",
+			From:        "fut-app <no-reply@localhost>",
+			SMTPPort:    "587",
+			SMTPTimeout: 10 * time.Second,
+			FilePath:    "mail.log",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Window:  time.Minute,
+			Default: 60,
+			Roles:   []string{"admin:600", "user:120"},
+			Auth:    20,
+		},
+		Favorites: FavoritesConfig{
+			Max:          50,
+			FeedCacheTTL: time.Minute,
+		},
+		Webhook: WebhookConfig{
+			PollInterval: time.Minute,
+			Timeout:      10 * time.Second,
+			MaxAttempts:  5,
+			Workers:      8,
+			Backoff:      30 * time.Second,
+		},
+		Live: LiveConfig{
+			PollInterval: 15 * time.Second,
+			Heartbeat:    20 * time.Second,
+		},
+		Socket: SocketConfig{
+			PollInterval:     time.Minute,
+			PingInterval:     30 * time.Second,
+			MaxSubscriptions: 20,
+		},
+		Calendar: CalendarConfig{
+			TimeZone:      "America/Sao_Paulo",
+			MatchDuration: 2 * time.Hour,
+			Refresh:       time.Hour,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      10,
+			MaxComplexity: 1000,
+		},
+		GRPC: GRPCConfig{
+			Enabled: false,
+			Port:    "9000",
+		},
+		FootballAPP: FootballAPP{
+			URL:     "https://api.football-data.org",
+			Timeout: 30 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+	}
+}
+
+// NewConfig loads the configuration layering, from lowest to highest
+// precedence: defaults, config file, environment variables and CLI flags.
+// The returned config is always populated so it can be printed even when
+// validation fails.
+func NewConfig(args []string) (*Config, error) {
+	cfg := defaultConfig()
+
+	fs := flag.NewFlagSet("fut-app", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(ConfigFileEnv), "path to a YAML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the effective config with secrets masked and exit")
+
+	flags := goenv.EnvSet{}
+	for _, key := range envKeys(reflect.TypeOf(cfg)) {
+		fs.Func(flagName(key), "overrides "+key, func(value string) error {
+			flags[key] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, &cfg); err != nil {
+			return &cfg, err
+		}
+	}
+
+	if _, err := goenv.UnmarshalFromEnviron(&cfg); err != nil {
+		return &cfg, fmt.Errorf("failed to load environment: %w", err)
+	}
+
+	if err := goenv.Unmarshal(flags, &cfg); err != nil {
+		return &cfg, fmt.Errorf("failed to load flags: %w", err)
+	}
+
+	return &cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("unsupported config file %q: only YAML is supported", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+	return nil
+}
+
+// Masked renders the config as YAML replacing secret values.
+func (c Config) Masked() ([]byte, error) {
+	masked := c
+	mask(reflect.ValueOf(&masked).Elem())
+	return yaml.Marshal(masked)
+}
+
+func mask(v reflect.Value) {
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			mask(field)
+			continue
+		}
+		if v.Type().Field(i).Tag.Get("secret") != "true" {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.String && field.String() != "":
+			field.SetString(maskedValue)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			masked := make([]string, field.Len())
+			for j := range masked {
+				masked[j] = maskedValue
+			}
+			field.Set(reflect.ValueOf(masked))
+		}
+	}
+}
+
+func envKeys(t reflect.Type) []string {
+	var keys []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath() {
+			keys = append(keys, envKeys(field.Type)...)
+			continue
+		}
+		if tag := field.Tag.Get("env"); tag != "" {
+			keys = append(keys, strings.Split(tag, ",")[0])
+		}
+	}
+	return keys
 }
 
-func NewConfig() (*Config, error) {
-	var envs Config
-	_, err := goenv.UnmarshalFromEnviron(&envs)
-	return &envs, err
+// flagName maps an environment key such as APP_PORT to the app-port flag.
+func flagName(envKey string) string {
+	return strings.ReplaceAll(strings.ToLower(envKey), "_", "-")
 }
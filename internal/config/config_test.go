@@ -0,0 +1,143 @@
+//go:build unit
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() Config {
+	cfg := defaultConfig()
+	cfg.Postgres.Host = "localhost"
+	cfg.Postgres.User = "postgres"
+	cfg.Postgres.Password = "123456"
+	cfg.Postgres.Name = "futapp"
+	cfg.JWT.SecretKey = "D41D8CD98F00B204E9800998ECF8427E"
+	cfg.Mail.SMTPHost = "smtp.example.com"
+	cfg.FootballAPP.Token = "football-data-token"
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	t.Run("when config is valid, should return nil", func(t *testing.T) {
+		t.Parallel()
+		cfg := validConfig()
+		is.Nil(cfg.Validate())
+	})
+
+	t.Run("when driver is sqlite, should not require postgres values", func(t *testing.T) {
+		t.Parallel()
+		cfg := validConfig()
+		cfg.Database.Driver = DriverSQLite
+		cfg.Postgres = PostgresConfig{}
+		is.Nil(cfg.Validate())
+	})
+
+	t.Run("when mail is written locally in development, should return nil", func(t *testing.T) {
+		t.Parallel()
+		cfg := validConfig()
+		cfg.Mail.Driver = "stdout"
+		cfg.Mail.Development = true
+		is.Nil(cfg.Validate())
+	})
+
+	t.Run("when many values are invalid, should report all of them", func(t *testing.T) {
+		t.Parallel()
+		cfg := validConfig()
+		cfg.App.Port = "70000"
+		cfg.App.TrustedProxies = []string{"10.0.0.1"}
+		cfg.Postgres.Host = ""
+		cfg.JWT.SecretKey = "xpto"
+		cfg.FootballAPP.URL = "api.football-data.org"
+		cfg.FootballAPP.Token = ""
+		cfg.FootballAPP.Timeout = 0
+		cfg.Database.MaxIdleConns = 50
+		cfg.Postgres.SSLMode = "strict"
+		cfg.Mail.Driver = "stdout"
+		cfg.RateLimit.Roles = []string{"user"}
+		cfg.Calendar.TimeZone = "Mars/Olympus_Mons"
+		cfg.Auth.BootstrapAdmin.User = "admin"
+		cfg.GraphQL.MaxDepth = 0
+		cfg.GRPC.Enabled = true
+		cfg.GRPC.Port = "grpc"
+		cfg.GRPC.TLSCertFile = "grpc.crt"
+
+		err := cfg.Validate()
+		is.NotNil(err)
+
+		var fields []string
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var validationErr *ValidationError
+			is.True(errors.As(e, &validationErr))
+			fields = append(fields, validationErr.Field)
+		}
+		is.Equal([]string{
+			"APP_PORT",
+			"APP_TRUSTED_PROXIES",
+			"DB_MAX_IDLE_CONNS",
+			"PG_HOST",
+			"PG_SSL_MODE",
+			"JWT_SECRET_KEY",
+			"AUTH_BOOTSTRAP_ADMIN_PASSWORD",
+			"MAIL_DRIVER",
+			"RATE_LIMIT_ROLES",
+			"CALENDAR_TIME_ZONE",
+			"GRAPHQL_MAX_DEPTH",
+			"GRPC_PORT",
+			"GRPC_TLS_KEY_FILE",
+			"FOOTBALL_APP_BASE_URL",
+			"FOOTBALL_APP_TOKEN",
+			"FOOTBALL_APP_TIMEOUT",
+		}, fields)
+	})
+}
+
+func TestNewConfigLayers(t *testing.T) {
+	is := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	is.Nil(os.WriteFile(path, []byte(`
+app:
+  port: "7000"
+  name: from-file
+postgres:
+  host: db
+  user: postgres
+  password: "123456"
+  name: futapp
+jwt:
+  secret_key: D41D8CD98F00B204E9800998ECF8427E
+mail:
+  smtp_host: smtp.example.com
+football_app:
+  token: football-data-token
+`), 0o600))
+
+	t.Setenv("APP_PORT", "7001")
+	t.Setenv("APP_NAME", "from-env")
+	t.Setenv("AUTH_INTROSPECTION_CLIENTS", "billing:billing-secret|chat:chat-secret")
+
+	cfg, err := NewConfig([]string{"--config", path, "--app-name", "from-flag", "--print-config"})
+	is.Nil(err)
+	is.Equal("7001", cfg.App.Port)
+	is.Equal("from-flag", cfg.App.Name)
+	is.Equal("db", cfg.Postgres.Host)
+	is.True(cfg.PrintConfig)
+	is.Equal([]string{"billing:billing-secret", "chat:chat-secret"}, cfg.Auth.IntrospectionClients)
+
+	masked, err := cfg.Masked()
+	is.Nil(err)
+	is.NotContains(string(masked), "D41D8CD98F00B204E9800998ECF8427E")
+	is.NotContains(string(masked), "billing-secret")
+	is.NotContains(string(masked), "football-data-token")
+	is.Equal("billing:billing-secret", cfg.Auth.IntrospectionClients[0])
+	is.Equal("D41D8CD98F00B204E9800998ECF8427E", cfg.JWT.SecretKey)
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MinSecretKeyLength = 32
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+type ValidationError struct {
+	Field string
+	msg   string
+}
+
+func NewValidationError(field string, msg string) error {
+	return &ValidationError{
+		Field: field,
+		msg:   msg,
+	}
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Field, err.msg)
+}
+
+func (err *ValidationError) Message() string {
+	return err.msg
+}
+
+// Validate reports every invalid value at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, required("APP_HOST", c.App.Host))
+	errs = append(errs, required("APP_NAME", c.App.Name))
+	errs = append(errs, port("APP_PORT", c.App.Port))
+	errs = append(errs, positiveDuration("APP_SHUTDOWN_TIMEOUT", c.App.ShutdownTimeout))
+	for _, proxy := range c.App.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			errs = append(errs, NewValidationError("APP_TRUSTED_PROXIES", "entries must be CIDRs"))
+			break
+		}
+	}
+
+	errs = append(errs, nonNegative("DB_MAX_OPEN_CONNS", c.Database.MaxOpenConns))
+	errs = append(errs, nonNegative("DB_MAX_IDLE_CONNS", c.Database.MaxIdleConns))
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, NewValidationError("DB_MAX_IDLE_CONNS", "must not be greater than DB_MAX_OPEN_CONNS"))
+	}
+	errs = append(errs, nonNegativeDuration("DB_CONN_MAX_LIFETIME", c.Database.ConnMaxLifetime))
+	errs = append(errs, nonNegativeDuration("DB_CONN_MAX_IDLE_TIME", c.Database.ConnMaxIdleTime))
+	errs = append(errs, nonNegative("DB_CONNECT_RETRIES", c.Database.ConnectRetries))
+	errs = append(errs, positiveDuration("DB_RETRY_BACKOFF", c.Database.RetryBackoff))
+
+	switch c.Database.Driver {
+	case DriverPostgres:
+		errs = append(errs, c.Postgres.validate()...)
+	case DriverSQLite:
+		errs = append(errs, required("SQLITE_PATH", c.SQLite.Path))
+	default:
+		errs = append(errs, NewValidationError("DB_DRIVER", "must be postgres or sqlite"))
+	}
+
+	if len(c.JWT.SecretKey) < MinSecretKeyLength {
+		errs = append(errs, NewValidationError("JWT_SECRET_KEY", fmt.Sprintf("must have at least %d bytes", MinSecretKeyLength)))
+	}
+
+	errs = append(errs, required("AUTH_TOTP_ISSUER", c.Auth.TOTPIssuer))
+	errs = append(errs, positiveDuration("AUTH_MFA_CHALLENGE_TTL", c.Auth.ChallengeTTL))
+	if c.Auth.MFAMaxFailures < 1 {
+		errs = append(errs, NewValidationError("AUTH_MFA_MAX_FAILURES", "must be at least 1"))
+	}
+	errs = append(errs, positiveDuration("AUTH_MFA_LOCKOUT", c.Auth.MFALockout))
+	if c.Auth.Password.MinLength < 1 {
+		errs = append(errs, NewValidationError("PASSWORD_MIN_LENGTH", "must be at least 1"))
+	}
+	switch c.Auth.PasswordHash.Algorithm {
+	case "argon2id":
+		if c.Auth.PasswordHash.Argon2Memory < 8*uint32(c.Auth.PasswordHash.Argon2Parallelism) {
+			errs = append(errs, NewValidationError("PASSWORD_ARGON2_MEMORY", "must be at least 8 KiB per thread"))
+		}
+		if c.Auth.PasswordHash.Argon2Iterations < 1 {
+			errs = append(errs, NewValidationError("PASSWORD_ARGON2_ITERATIONS", "must be at least 1"))
+		}
+		if c.Auth.PasswordHash.Argon2Parallelism < 1 {
+			errs = append(errs, NewValidationError("PASSWORD_ARGON2_PARALLELISM", "must be at least 1"))
+		}
+	case "bcrypt":
+		if c.Auth.PasswordHash.BcryptCost < 4 || c.Auth.PasswordHash.BcryptCost > 31 {
+			errs = append(errs, NewValidationError("PASSWORD_BCRYPT_COST", "must be between 4 and 31"))
+		}
+	default:
+		errs = append(errs, NewValidationError("PASSWORD_HASH_ALGORITHM", "must be argon2id or bcrypt"))
+	}
+	errs = append(errs, positiveDuration("AUTH_RESET_TOKEN_TTL", c.Auth.ResetTokenTTL))
+	if c.Auth.ResetURL != "" {
+		errs = append(errs, httpURL("AUTH_RESET_URL", c.Auth.ResetURL))
+	}
+
+	errs = append(errs, positiveDuration("AUTH_MAX_SESSION_AGE", c.Auth.MaxSessionAge))
+
+	for _, client := range c.Auth.IntrospectionClients {
+		if id, secret, ok := strings.Cut(client, ":"); !ok || id == "" || secret == "" {
+			errs = append(errs, NewValidationError("AUTH_INTROSPECTION_CLIENTS", "entries must be client_id:client_secret"))
+			break
+		}
+	}
+
+	if c.Auth.OIDC.Issuer != "" {
+		errs = append(errs, c.Auth.OIDC.validate()...)
+	}
+	if c.Auth.BootstrapAdmin.User != "" || c.Auth.BootstrapAdmin.Password != "" {
+		errs = append(errs, required("AUTH_BOOTSTRAP_ADMIN_USER", c.Auth.BootstrapAdmin.User))
+		errs = append(errs, required("AUTH_BOOTSTRAP_ADMIN_PASSWORD", c.Auth.BootstrapAdmin.Password))
+	}
+
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		errs = append(errs, NewValidationError("MAIL_FROM", "must be an address such as fut-app <no-reply@example.com>"))
+	}
+	switch c.Mail.Driver {
+	case "smtp":
+		errs = append(errs, required("MAIL_SMTP_HOST", c.Mail.SMTPHost))
+		errs = append(errs, port("MAIL_SMTP_PORT", c.Mail.SMTPPort))
+		errs = append(errs, positiveDuration("MAIL_SMTP_TIMEOUT", c.Mail.SMTPTimeout))
+	case "file", "stdout":
+		if !c.Mail.Development {
+			errs = append(errs, NewValidationError("MAIL_DRIVER", "file and stdout are only allowed with MAIL_DEVELOPMENT=true"))
+		}
+		if c.Mail.Driver == "file" {
+			errs = append(errs, required("MAIL_FILE_PATH", c.Mail.FilePath))
+		}
+	default:
+		errs = append(errs, NewValidationError("MAIL_DRIVER", "must be smtp, file or stdout"))
+	}
+
+	if c.RateLimit.Enabled {
+		errs = append(errs, c.RateLimit.validate()...)
+	}
+
+	if c.Favorites.Max < 1 {
+		errs = append(errs, NewValidationError("FAVORITES_MAX", "must be at least 1"))
+	}
+	errs = append(errs, positiveDuration("FAVORITES_FEED_CACHE_TTL", c.Favorites.FeedCacheTTL))
+
+	if c.Webhook.Enabled {
+		errs = append(errs, positiveDuration("WEBHOOK_POLL_INTERVAL", c.Webhook.PollInterval))
+		errs = append(errs, positiveDuration("WEBHOOK_TIMEOUT", c.Webhook.Timeout))
+		errs = append(errs, positiveDuration("WEBHOOK_BACKOFF", c.Webhook.Backoff))
+		if c.Webhook.MaxAttempts < 1 {
+			errs = append(errs, NewValidationError("WEBHOOK_MAX_ATTEMPTS", "must be at least 1"))
+		}
+		if c.Webhook.Workers < 1 {
+			errs = append(errs, NewValidationError("WEBHOOK_WORKERS", "must be at least 1"))
+		}
+	}
+
+	errs = append(errs, positiveDuration("LIVE_POLL_INTERVAL", c.Live.PollInterval))
+	errs = append(errs, positiveDuration("LIVE_HEARTBEAT", c.Live.Heartbeat))
+	errs = append(errs, positiveDuration("SOCKET_POLL_INTERVAL", c.Socket.PollInterval))
+	errs = append(errs, positiveDuration("SOCKET_PING_INTERVAL", c.Socket.PingInterval))
+	if c.Socket.MaxSubscriptions < 1 {
+		errs = append(errs, NewValidationError("SOCKET_MAX_SUBSCRIPTIONS", "must be at least 1"))
+	}
+
+	if _, err := time.LoadLocation(c.Calendar.TimeZone); err != nil || c.Calendar.TimeZone == "" {
+		errs = append(errs, NewValidationError("CALENDAR_TIME_ZONE", "must be an IANA time zone such as America/Sao_Paulo"))
+	}
+	errs = append(errs, positiveDuration("CALENDAR_MATCH_DURATION", c.Calendar.MatchDuration))
+	errs = append(errs, positiveDuration("CALENDAR_REFRESH", c.Calendar.Refresh))
+	if c.GraphQL.MaxDepth < 1 {
+		errs = append(errs, NewValidationError("GRAPHQL_MAX_DEPTH", "must be at least 1"))
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		errs = append(errs, NewValidationError("GRAPHQL_MAX_COMPLEXITY", "must be at least 1"))
+	}
+	if c.GRPC.Enabled {
+		errs = append(errs, port("GRPC_PORT", c.GRPC.Port))
+		if c.GRPC.Port == c.App.Port {
+			errs = append(errs, NewValidationError("GRPC_PORT", "must differ from APP_PORT"))
+		}
+		if c.GRPC.TLSCertFile == "" && c.GRPC.TLSKeyFile == "" {
+			if !c.GRPC.Insecure {
+				errs = append(errs, NewValidationError("GRPC_TLS_CERT_FILE", "is required unless GRPC_INSECURE is true"))
+			}
+		} else {
+			errs = append(errs, required("GRPC_TLS_CERT_FILE", c.GRPC.TLSCertFile))
+			errs = append(errs, required("GRPC_TLS_KEY_FILE", c.GRPC.TLSKeyFile))
+		}
+	}
+
+	errs = append(errs, httpURL("FOOTBALL_APP_BASE_URL", c.FootballAPP.URL))
+	errs = append(errs, required("FOOTBALL_APP_TOKEN", c.FootballAPP.Token))
+	errs = append(errs, positiveDuration("FOOTBALL_APP_TIMEOUT", c.FootballAPP.Timeout))
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, NewValidationError("LOG_LEVEL", "must be one of debug, info, warn or error"))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, NewValidationError("LOG_FORMAT", "must be json or text"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c PostgresConfig) validate() []error {
+	var errs []error
+	errs = append(errs, required("PG_HOST", c.Host))
+	errs = append(errs, port("PG_PORT", c.Port))
+	errs = append(errs, required("PG_USER", c.User))
+	errs = append(errs, required("PG_PASSWORD", c.Password))
+	errs = append(errs, required("PG_NAME", c.Name))
+	if !slices.Contains(sslModes, c.SSLMode) {
+		errs = append(errs, NewValidationError("PG_SSL_MODE", "must be one of "+strings.Join(sslModes, ", ")))
+	}
+	if c.SSLRootCert != "" {
+		if _, err := os.Stat(c.SSLRootCert); err != nil {
+			errs = append(errs, NewValidationError("PG_SSL_ROOT_CERT", "must be a readable file"))
+		}
+	}
+	errs = append(errs, positiveDuration("PG_CONNECT_TIMEOUT", c.ConnectTimeout))
+	errs = append(errs, nonNegativeDuration("PG_STATEMENT_TIMEOUT", c.StatementTimeout))
+
+	return errs
+}
+
+func required(field string, value string) error {
+	if value == "" {
+		return NewValidationError(field, "is required")
+	}
+	return nil
+}
+
+func port(field string, value string) error {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 || number > 65535 {
+		return NewValidationError(field, "must be a port between 1 and 65535")
+	}
+	return nil
+}
+
+func httpURL(field string, value string) error {
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewValidationError(field, "must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func nonNegative(field string, value int) error {
+	if value < 0 {
+		return NewValidationError(field, "must not be negative")
+	}
+	return nil
+}
+
+func nonNegativeDuration(field string, value time.Duration) error {
+	if value < 0 {
+		return NewValidationError(field, "must not be a negative duration")
+	}
+	return nil
+}
+
+func positiveDuration(field string, value time.Duration) error {
+	if value <= 0 {
+		return NewValidationError(field, "must be a positive duration")
+	}
+	return nil
+}
+
+func (c OIDCConfig) validate() []error {
+	var errs []error
+	errs = append(errs, httpURL("AUTH_OIDC_ISSUER", c.Issuer))
+	errs = append(errs, required("AUTH_OIDC_CLIENT_ID", c.ClientID))
+	errs = append(errs, httpURL("AUTH_OIDC_REDIRECT_URL", c.RedirectURL))
+	if !slices.Contains(c.Scopes, "openid") {
+		errs = append(errs, NewValidationError("AUTH_OIDC_SCOPES", "must include openid"))
+	}
+	if c.DefaultRole != "admin" && c.DefaultRole != "user" {
+		errs = append(errs, NewValidationError("AUTH_OIDC_DEFAULT_ROLE", "must be admin or user"))
+	}
+	return errs
+}
+
+func (c RateLimitConfig) validate() []error {
+	var errs []error
+	if c.Store != "memory" && c.Store != "database" {
+		errs = append(errs, NewValidationError("RATE_LIMIT_STORE", "must be memory or database"))
+	}
+	errs = append(errs, positiveDuration("RATE_LIMIT_WINDOW", c.Window))
+	if c.Default < 1 {
+		errs = append(errs, NewValidationError("RATE_LIMIT_DEFAULT", "must be at least 1"))
+	}
+	if c.Auth < 1 {
+		errs = append(errs, NewValidationError("RATE_LIMIT_AUTH", "must be at least 1"))
+	}
+	for _, role := range c.Roles {
+		name, limit, ok := strings.Cut(role, ":")
+		number, err := strconv.Atoi(limit)
+		if !ok || name == "" || err != nil || number < 1 {
+			errs = append(errs, NewValidationError("RATE_LIMIT_ROLES", "entries must be role:limit with a positive limit"))
+			break
+		}
+	}
+	return errs
+}
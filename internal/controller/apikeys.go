@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeys struct {
+	Service     service.APIKeysRepository
+	Credentials service.CredentialsRepository
+	Logger      *slog.Logger
+}
+
+func NewAPIKeys(keys service.APIKeysDatabase, credentials service.CredentialsDatabase, logger *slog.Logger) APIKeys {
+	return APIKeys{
+		Service:     &keys,
+		Credentials: &credentials,
+		Logger:      logger,
+	}
+}
+
+func (k APIKeys) CreateAPIKey(c echo.Context) error {
+	var req model.APIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		k.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		k.Logger.WarnContext(c.Request().Context(), "failed to validate api key request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	granted, _ := c.Get("scopes").(model.Scopes)
+	if unknown := req.Scopes.Unknown(granted); len(unknown) > 0 {
+		return c.JSON(
+			http.StatusForbidden,
+			map[string]string{
+				"error": "cannot grant scopes not present in your token: " + unknown.String(),
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, k.Credentials)
+	if err != nil {
+		return err
+	}
+
+	key, raw, err := req.NewAPIKey(credential.ID)
+	if err != nil {
+		k.Logger.ErrorContext(c.Request().Context(), "failed to generate api key", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": service.ErrAPIKeyCreationFailed.Error(),
+			},
+		)
+	}
+
+	if err := k.Service.CreateAPIKey(c.Request().Context(), key); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	k.Logger.InfoContext(c.Request().Context(), "api key created", "key_id", key.ID, "prefix", key.Prefix)
+	return c.JSON(
+		http.StatusCreated,
+		model.CreatedAPIKey{
+			APIKey: *key,
+			Key:    raw,
+		},
+	)
+}
+
+func (k APIKeys) ListAPIKeys(c echo.Context) error {
+	credential, err := currentCredential(c, k.Credentials)
+	if err != nil {
+		return err
+	}
+
+	keys, err := k.Service.ListAPIKeys(c.Request().Context(), credential.ID)
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	return c.JSON(
+		http.StatusOK,
+		keys,
+	)
+}
+
+func (k APIKeys) DeleteAPIKey(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "invalid api key id",
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, k.Credentials)
+	if err != nil {
+		return err
+	}
+
+	err = k.Service.DeleteAPIKey(c.Request().Context(), credential.ID, id)
+	if errors.Is(err, service.ErrAPIKeyNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to delete api key",
+			},
+		)
+	}
+
+	k.Logger.InfoContext(c.Request().Context(), "api key revoked", "key_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type Audit struct {
+	Service service.AuditRepository
+	Logger  *slog.Logger
+}
+
+func NewAudit(db service.AuditDatabase, logger *slog.Logger) Audit {
+	return Audit{
+		Service: &db,
+		Logger:  logger,
+	}
+}
+
+func (a Audit) ListAuditEvents(c echo.Context) error {
+	var filter model.AuditFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "invalid query parameters",
+			},
+		)
+	}
+	if err := filter.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	page, err := a.Service.ListAuditEvents(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// recordAudit stores a security event with the request's actor, IP and
+// user agent. Failures are logged by the repository and never fail the
+// request being audited.
+func recordAudit(c echo.Context, audit service.AuditRepository, event string, target string, success bool, detail string) {
+	if audit == nil {
+		return
+	}
+	actor, _ := c.Get("user").(string)
+	_ = audit.RecordAuditEvent(c.Request().Context(), &model.AuditEvent{
+		Event:     event,
+		Actor:     actor,
+		Target:    target,
+		Success:   success,
+		Detail:    detail,
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
+}
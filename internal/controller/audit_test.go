@@ -0,0 +1,93 @@
+//go:build unit
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditTrail(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	audit := Audit{Service: auth.Audit, Logger: auth.Logger}
+
+	admin := map[string]any{"user": "root", "role": model.RoleAdmin, "scopes": model.KnownScopes}
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, admin)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.Authenticate, `{"user":"fan","password":"wrong"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusUnauthorized, rec.Code)
+
+	rec, err = doParam(auth.UpdateCredentials, "fan", `{"role":"admin"}`, admin)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doParam(auth.DeleteCredentials, "fan", ``, admin)
+	is.Nil(err)
+	is.Equal(http.StatusNoContent, rec.Code)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedEvents []string
+		expectedTotal  int64
+	}{
+		{
+			name:           "when no filter is given, should return every event newest first",
+			query:          "",
+			expectedEvents: []string{model.AuditCredentialDelete, model.AuditRoleChange, model.AuditCredentialUpdate, model.AuditLoginFailure, model.AuditCredentialCreate},
+			expectedTotal:  5,
+		},
+		{
+			name:           "when filtering by event, should return only matching events",
+			query:          "event=" + model.AuditLoginFailure,
+			expectedEvents: []string{model.AuditLoginFailure},
+			expectedTotal:  1,
+		},
+		{
+			name:           "when paginating, should return the requested page and the total",
+			query:          "target=fan&page=2&per_page=2",
+			expectedEvents: []string{model.AuditCredentialUpdate, model.AuditLoginFailure},
+			expectedTotal:  5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit?"+test.query, nil)
+			rec := httptest.NewRecorder()
+			is.Nil(audit.ListAuditEvents(e.NewContext(req, rec)))
+			is.Equal(http.StatusOK, rec.Code)
+
+			var page model.AuditPage
+			is.Nil(json.Unmarshal(rec.Body.Bytes(), &page))
+			is.Equal(test.expectedTotal, page.Total)
+			events := make([]string, 0, len(page.Events))
+			for _, event := range page.Events {
+				events = append(events, event.Event)
+				is.Equal("fan", event.Target)
+			}
+			is.Equal(test.expectedEvents, events)
+		})
+	}
+}
+
+// doParam calls handler with the :user path parameter set.
+func doParam(handler echo.HandlerFunc, user string, body string, values map[string]any) (*httptest.ResponseRecorder, error) {
+	return doJSON(func(c echo.Context) error {
+		c.SetParamNames("user")
+		c.SetParamValues(user)
+		return handler(c)
+	}, body, values)
+}
@@ -1,31 +1,60 @@
 package controller
 
 import (
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/fut-app/internal/config"
 	"github.com/fut-app/internal/model"
 	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/mailer"
 	"github.com/labstack/echo/v4"
 )
 
 type Auth struct {
+	SignIn    service.Login
 	Service   service.CredentialsRepository
+	Audit     service.AuditRepository
 	SecretKey string
+	Config    config.AuthConfig
+	Hasher    hasher.Hasher
+	Mailer    mailer.Mailer
+	Logger    *slog.Logger
+
+	// mails tracks reset mails still being sent in the background.
+	mails *sync.WaitGroup
 }
 
-func NewAuth(db service.CredentialsDatabase, secretKey string) Auth {
+func NewAuth(
+	db service.CredentialsDatabase,
+	audit service.AuditRepository,
+	secretKey string,
+	cfg config.AuthConfig,
+	passwords hasher.Hasher,
+	mail mailer.Mailer,
+	logger *slog.Logger,
+) Auth {
 	return Auth{
+		SignIn:    service.NewLogin(&db, secretKey, cfg, passwords, logger),
 		Service:   &db,
+		Audit:     audit,
 		SecretKey: secretKey,
+		Config:    cfg,
+		Hasher:    passwords,
+		Mailer:    mail,
+		Logger:    logger,
+		mails:     &sync.WaitGroup{},
 	}
 }
 
 func (a Auth) Authenticate(c echo.Context) error {
 	var req model.AuthRequest
 	if err := c.Bind(&req); err != nil {
-		log.Println("failed to bind request\n", err)
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
 		return c.JSON(
 			http.StatusBadRequest,
 			map[string]string{
@@ -34,54 +63,99 @@ func (a Auth) Authenticate(c echo.Context) error {
 		)
 	}
 	if err := req.Validate(); err != nil {
-		log.Println("failed to validate auth request\n", err)
+		a.Logger.WarnContext(c.Request().Context(), "failed to validate auth request", "error", err)
 		return c.JSON(
 			http.StatusBadRequest,
 			err,
 		)
 	}
 
-	credential, err := req.ParseAuthRequestToCredential()
-	if err != nil {
-		log.Println("failed to parse auth request to credential\n", err)
+	result, err := a.SignIn.Password(c.Request().Context(), req)
+	return a.respondLogin(c, req.User, result, err)
+}
+
+// completeLogin answers a login whose first factor succeeded: with a
+// two-factor challenge, an enrollment token for admins who must enroll, or
+// the access token.
+func (a Auth) completeLogin(c echo.Context, credential *model.Credential, scopes model.Scopes) error {
+	result, err := a.SignIn.Complete(c.Request().Context(), credential, scopes)
+	return a.respondLogin(c, credential.User, result, err)
+}
+
+// respondLogin maps the outcome of a login step to its HTTP answer and
+// audit event. user names the target until a credential is found.
+func (a Auth) respondLogin(c echo.Context, user string, result service.LoginResult, err error) error {
+	if result.Credential != nil {
+		user = result.Credential.User
+	}
+
+	var scopeErr *service.ScopeError
+	switch {
+	case errors.Is(err, service.ErrInvalidPassword):
+		recordAudit(c, a.Audit, model.AuditLoginFailure, user, false, "invalid credentials")
 		return c.JSON(
-			http.StatusInternalServerError,
+			http.StatusUnauthorized,
 			map[string]string{
-				"error": "failed to create credential",
+				"error": service.ErrInvalidPassword.Error(),
 			},
 		)
-	}
-
-	storedCredential, err := a.Service.FindCredentials(c.Request().Context(), credential)
-	if err != nil {
-		log.Println("failed to find credentials\n", err)
+	case errors.As(err, &scopeErr):
 		return c.JSON(
-			http.StatusInternalServerError,
-			err,
+			http.StatusBadRequest,
+			map[string]string{
+				"error":             "invalid_scope",
+				"error_description": scopeErr.Error(),
+			},
 		)
-	}
-
-	token, err := storedCredential.GenerateToken(a.SecretKey)
-	if err != nil {
-		log.Println("failed to generate token\n", err)
-		c.JSON(
+	case errors.Is(err, service.ErrInvalidChallenge):
+		return c.JSON(
+			http.StatusUnauthorized,
+			map[string]string{
+				"error": service.ErrInvalidChallenge.Error(),
+			},
+		)
+	case errors.Is(err, service.ErrTwoFactorLocked):
+		recordAudit(c, a.Audit, model.AuditLoginFailure, user, false, err.Error())
+		return c.JSON(
+			http.StatusTooManyRequests,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		recordAudit(c, a.Audit, model.AuditLoginFailure, user, false, err.Error())
+		return c.JSON(
+			http.StatusUnauthorized,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	case err != nil:
+		return c.JSON(
 			http.StatusInternalServerError,
 			map[string]string{
-				"error": "something is wrong with provided credentials",
+				"error": service.ErrTokenFailed.Error(),
 			},
 		)
 	}
 
+	if result.AccessToken == "" {
+		return c.JSON(
+			http.StatusOK,
+			result.Challenge,
+		)
+	}
+	recordAudit(c, a.Audit, model.AuditLoginSuccess, user, true, "scope="+result.Scopes.String())
 	return c.JSON(
 		http.StatusOK,
-		token,
+		result.AccessToken,
 	)
 }
 
 func (a Auth) CreateCredentials(c echo.Context) error {
 	var req model.AuthRequest
 	if err := c.Bind(&req); err != nil {
-		log.Println("failed to bind request\n", err)
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
 		return c.JSON(
 			http.StatusBadRequest,
 			map[string]string{
@@ -90,16 +164,20 @@ func (a Auth) CreateCredentials(c echo.Context) error {
 		)
 	}
 	if err := req.Validate(); err != nil {
-		log.Println("failed to validate auth request\n", err)
+		a.Logger.WarnContext(c.Request().Context(), "failed to validate auth request", "error", err)
 		return c.JSON(
 			http.StatusBadRequest,
 			err,
 		)
 	}
 
-	credential, err := req.ParseAuthRequestToCredential()
+	if rejected, err := a.rejectWeakPassword(c, req.User, req.Password); rejected {
+		return err
+	}
+
+	credential, err := req.ParseAuthRequestToCredential(a.Hasher)
 	if err != nil {
-		log.Println("failed to parse auth request to credential\n", err)
+		a.Logger.ErrorContext(c.Request().Context(), "failed to parse auth request to credential", "error", err)
 		return c.JSON(
 			http.StatusInternalServerError,
 			map[string]string{
@@ -108,10 +186,46 @@ func (a Auth) CreateCredentials(c echo.Context) error {
 		)
 	}
 
+	if credential.Role == model.RoleAdmin && c.Get("role") != model.RoleAdmin {
+		return c.JSON(
+			http.StatusForbidden,
+			map[string]string{
+				"error": "only admins can create admin credentials",
+			},
+		)
+	}
+
+	// Organization members only find their own organization, and the
+	// repository places their credentials there regardless.
+	if req.Org != "" {
+		organization, err := a.Service.FindOrganization(c.Request().Context(), req.Org)
+		if err != nil {
+			return c.JSON(
+				http.StatusBadRequest,
+				map[string]string{
+					"error": "unknown organization",
+				},
+			)
+		}
+		credential.OrgID = &organization.ID
+	}
+
+	granted, _ := c.Get("scopes").(model.Scopes)
+	if unknown := credential.AllowedScopes().Unknown(granted); len(unknown) > 0 {
+		a.Logger.WarnContext(c.Request().Context(), "cannot grant scopes above own token", "target", credential.User, "scopes", unknown.String())
+		return c.JSON(
+			http.StatusForbidden,
+			map[string]string{
+				"error": "cannot grant scopes not present in your token: " + unknown.String(),
+			},
+		)
+	}
+
 	credential.CreatedAt = time.Now()
 	credential.UpdatedAt = time.Now()
 
 	if err := a.Service.CreateCredentials(c.Request().Context(), credential); err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to create credentials", "target", credential.User, "error", err)
 		return c.JSON(
 			http.StatusInternalServerError,
 			map[string]string{
@@ -120,6 +234,8 @@ func (a Auth) CreateCredentials(c echo.Context) error {
 		)
 	}
 
+	a.Logger.InfoContext(c.Request().Context(), "credentials created", "target", credential.User)
+	recordAudit(c, a.Audit, model.AuditCredentialCreate, credential.User, true, "role="+credential.Role)
 	return c.JSON(
 		http.StatusOK,
 		map[string]string{
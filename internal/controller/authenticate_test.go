@@ -0,0 +1,253 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/mailer"
+	"github.com/fut-app/pkg/sqlite/sqlitetest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testSecretKey = "D41D8CD98F00B204E9800998ECF8427E"
+
+// testHasher uses cheap parameters to keep the tests fast.
+var testHasher = &hasher.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestAuth(t *testing.T) Auth {
+	t.Helper()
+	db := sqlitetest.NewDB(t, model.Models()...)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.AuthConfig{
+		RequireAdmin2FA: true,
+		TOTPIssuer:      "fut-app",
+		ChallengeTTL:    time.Minute,
+		MFAMaxFailures:  3,
+		MFALockout:      time.Minute,
+		ResetTokenTTL:   time.Minute,
+		MaxSessionAge:   time.Hour,
+		IntrospectionClients: []string{
+			"billing:billing-secret",
+		},
+		Password: config.PasswordPolicyConfig{
+			MinLength:    10,
+			RequireUpper: true,
+			RequireLower: true,
+			RequireDigit: true,
+		},
+	}
+	audit := service.NewAuditDatabase(db, log)
+	return NewAuth(service.NewDatabase(db, log), &audit, testSecretKey, cfg, testHasher, &fakeMailer{}, log)
+}
+
+// fakeMailer keeps sent messages in memory.
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// doJSON calls handler with body, as if the authentication middleware had
+// stored values in the context.
+func doJSON(handler echo.HandlerFunc, body string, values map[string]any) (*httptest.ResponseRecorder, error) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	for key, value := range values {
+		c.Set(key, value)
+	}
+	return rec, handler(c)
+}
+
+func withScopes(scopes model.Scopes) map[string]any {
+	return map[string]any{"scopes": scopes}
+}
+
+func TestAuthFlow(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+
+	rec, err := doJSON(auth.CreateCredentials, fmt.Sprintf(`{"user":"admin","password":"Xpto-123456","scope":%q}`, model.KnownScopes), withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.CreateCredentials, `{"user":"reader","password":"Xpto-123456","scope":"competitions:read"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.CreateCredentials, `{"user":"newcomer","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	t.Run("when creator lacks a granted scope, should return forbidden", func(t *testing.T) {
+		rec, err := doJSON(auth.CreateCredentials, `{"user":"other","password":"Xpto-123456"}`, withScopes(model.Scopes{model.ScopeUsersManage}))
+		is.Nil(err)
+		is.Equal(http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("when password violates the policy, should return every violation", func(t *testing.T) {
+		rec, err := doJSON(auth.CreateCredentials, `{"user":"weak","password":"xpto123"}`, withScopes(model.KnownScopes))
+		is.Nil(err)
+		is.Equal(http.StatusBadRequest, rec.Code)
+
+		var body struct {
+			Violations []string `json:"violations"`
+		}
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &body))
+		is.Equal([]string{"must have at least 10 characters", "must contain an uppercase letter"}, body.Violations)
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedCode   int
+		expectedUser   string
+		expectedScopes string
+	}{
+		{
+			name:           "when credentials are valid, should return token with every allowed scope",
+			body:           `{"user":"admin","password":"Xpto-123456"}`,
+			expectedCode:   http.StatusOK,
+			expectedUser:   "admin",
+			expectedScopes: model.KnownScopes.String(),
+		},
+		{
+			name:           "when a subset of scopes is requested, should return token restricted to it",
+			body:           `{"user":"admin","password":"Xpto-123456","scope":"users:manage"}`,
+			expectedCode:   http.StatusOK,
+			expectedUser:   "admin",
+			expectedScopes: model.ScopeUsersManage,
+		},
+		{
+			name:           "when the user was created without a scope, should return token with the default scopes",
+			body:           `{"user":"newcomer","password":"Xpto-123456"}`,
+			expectedCode:   http.StatusOK,
+			expectedUser:   "newcomer",
+			expectedScopes: model.DefaultScopes.String(),
+		},
+		{
+			name:         "when requested scope is not allowed for the user, should return bad request",
+			body:         `{"user":"reader","password":"Xpto-123456","scope":"users:manage"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when password is wrong, should return unauthorized",
+			body:         `{"user":"admin","password":"wrong"}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "when user doesn't exist, should return unauthorized",
+			body:         `{"user":"ghost","password":"Xpto-123456"}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "when body is invalid, should return bad request",
+			body:         `{"user":`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec, err := doJSON(auth.Authenticate, test.body, nil)
+			is.Nil(err)
+			is.Equal(test.expectedCode, rec.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+
+			var token string
+			is.Nil(json.Unmarshal(rec.Body.Bytes(), &token))
+			claims, err := middleware.VerifyToken(token, testSecretKey)
+			is.Nil(err)
+			is.Equal(test.expectedUser, claims["username"])
+			is.Equal(test.expectedScopes, claims["scope"])
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	user := map[string]any{"user": "fan"}
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "when current password is wrong, should return unauthorized",
+			body:         `{"current_password":"wrong","new_password":"Gol-de-Placa-1970"}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "when new password violates the policy, should return bad request",
+			body:         `{"current_password":"Xpto-123456","new_password":"fan"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when new password is valid, should change it",
+			body:         `{"current_password":"Xpto-123456","new_password":"Gol-de-Placa-1970"}`,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		rec, err := doJSON(auth.ChangePassword, test.body, user)
+		is.Nil(err, test.name)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+	}
+
+	rec, err = doJSON(auth.Authenticate, `{"user":"fan","password":"Gol-de-Placa-1970"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+}
+
+func TestAuthenticateRehashesLegacyPasswords(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	ctx := context.Background()
+
+	request := model.AuthRequest{User: "legacy", Password: "Xpto-123456"}
+	credential, err := request.ParseAuthRequestToCredential(&hasher.Bcrypt{Cost: bcrypt.MinCost})
+	is.Nil(err)
+	is.Nil(auth.Service.CreateCredentials(ctx, credential))
+
+	rec, err := doJSON(auth.Authenticate, `{"user":"legacy","password":"Xpto-123456"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	stored, err := auth.Service.FindCredentials(ctx, &model.Credential{User: "legacy"})
+	is.Nil(err)
+	is.True(strings.HasPrefix(stored.EncryptedPassword, "$argon2id$"))
+	is.False(testHasher.NeedsRehash(stored.EncryptedPassword))
+	is.True(stored.CheckPassword("Xpto-123456"))
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// calendarContentType is the media type of RFC 5545 calendars.
+	calendarContentType = "text/calendar; charset=utf-8"
+	// timeZoneQueryParam overrides the display time zone of a calendar.
+	timeZoneQueryParam = "tz"
+)
+
+type Calendar struct {
+	Football      service.FootballAPI
+	Tokens        service.CalendarTokensRepository
+	Credentials   service.CredentialsRepository
+	Location      *time.Location
+	MatchDuration time.Duration
+	Refresh       time.Duration
+	Logger        *slog.Logger
+}
+
+func NewCalendar(
+	football service.Football,
+	tokens service.CalendarTokensDatabase,
+	credentials service.CredentialsDatabase,
+	cfg config.CalendarConfig,
+	logger *slog.Logger,
+) Calendar {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		// Unreachable with a validated config.
+		location = time.UTC
+	}
+	return Calendar{
+		Football:      &football,
+		Tokens:        &tokens,
+		Credentials:   &credentials,
+		Location:      location,
+		MatchDuration: cfg.MatchDuration,
+		Refresh:       cfg.Refresh,
+		Logger:        logger,
+	}
+}
+
+// CompetitionCalendar publishes the season fixtures of a competition.
+func (cal Calendar) CompetitionCalendar(c echo.Context) error {
+	competitionID, err := model.ParseCompetitionID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	location, err := cal.location(c)
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	matches, err := cal.Football.CompetitionMatches(ctx, competitionID, model.MatchFilter{})
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "competition not found",
+			},
+		)
+	}
+	if err != nil {
+		cal.Logger.ErrorContext(ctx, "failed to fetch competition matches", "competition", competitionID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	publicID := model.CompetitionID(competitionID)
+	name := publicID
+	if len(matches.Matches) > 0 && matches.Matches[0].Competition.Name != "" {
+		name = matches.Matches[0].Competition.Name
+	}
+	return cal.render(c, publicID, name, matches.Matches, location)
+}
+
+// TeamCalendar publishes the fixtures of a team in every competition.
+func (cal Calendar) TeamCalendar(c echo.Context) error {
+	teamID, err := model.ParseTeamID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	location, err := cal.location(c)
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	team, err := cal.Football.Team(ctx, teamID)
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "team not found",
+			},
+		)
+	}
+	if err != nil {
+		cal.Logger.ErrorContext(ctx, "failed to fetch team", "team", teamID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	matches, err := cal.Football.TeamMatches(ctx, teamID, model.MatchFilter{})
+	if err != nil {
+		cal.Logger.ErrorContext(ctx, "failed to fetch team matches", "team", teamID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	return cal.render(c, model.TeamID(teamID), team.Name, matches.Matches, location)
+}
+
+// CreateCalendarToken issues the token for calendar subscription URLs,
+// revoking the previous one.
+func (cal Calendar) CreateCalendarToken(c echo.Context) error {
+	credential, err := currentCredential(c, cal.Credentials)
+	if err != nil {
+		return err
+	}
+
+	token, raw, err := model.NewCalendarToken(credential.ID)
+	if err != nil {
+		cal.Logger.ErrorContext(c.Request().Context(), "failed to generate calendar token", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": service.ErrCalendarTokenFailed.Error(),
+			},
+		)
+	}
+
+	if err := cal.Tokens.RotateCalendarToken(c.Request().Context(), token); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	cal.Logger.InfoContext(c.Request().Context(), "calendar token created", "prefix", token.Prefix)
+	return c.JSON(
+		http.StatusCreated,
+		model.CreatedCalendarToken{
+			CalendarToken: *token,
+			Token:         raw,
+		},
+	)
+}
+
+func (cal Calendar) DeleteCalendarToken(c echo.Context) error {
+	credential, err := currentCredential(c, cal.Credentials)
+	if err != nil {
+		return err
+	}
+
+	err = cal.Tokens.RevokeCalendarToken(c.Request().Context(), credential.ID)
+	if errors.Is(err, service.ErrCalendarTokenNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	cal.Logger.InfoContext(c.Request().Context(), "calendar token revoked")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// location returns the time zone requested in the tz query parameter, or
+// the configured one.
+func (cal Calendar) location(c echo.Context) (*time.Location, error) {
+	name := c.QueryParam(timeZoneQueryParam)
+	if name == "" {
+		return cal.Location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("tz must be an IANA time zone such as America/Sao_Paulo")
+	}
+	return location, nil
+}
+
+func (cal Calendar) render(c echo.Context, publicID string, name string, matches []model.Match, location *time.Location) error {
+	calendar := model.MatchCalendar(name, matches, location, cal.MatchDuration, cal.Refresh)
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.ics"`, publicID))
+	// Subscription URLs carry the caller's token.
+	header.Set(echo.HeaderCacheControl, "private")
+	return c.Blob(http.StatusOK, calendarContentType, calendar.Encode())
+}
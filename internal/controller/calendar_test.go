@@ -0,0 +1,211 @@
+//go:build unit
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// doCalendar calls handler as fan with the :id path parameter and query set.
+func doCalendar(handler echo.HandlerFunc, id string, query string) (*httptest.ResponseRecorder, error) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), rec)
+	c.Set("user", "fan")
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	return rec, handler(c)
+}
+
+// unfold joins folded content lines so assertions can match whole lines.
+func unfold(body string) string {
+	return strings.ReplaceAll(body, "\r\n ", "")
+}
+
+func TestCalendar(t *testing.T) {
+	is := require.New(t)
+	kickoff := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	two, one, matchday := 2, 1, 8
+	football := &fakeFootball{matches: []model.Match{
+		{
+			ID: 497410, UTCDate: kickoff, Status: model.MatchTimed, Matchday: &matchday, Venue: "Emirates Stadium",
+			LastUpdated: updated, Competition: model.Competition{ID: 2021, Name: "Premier League"},
+			HomeTeam: model.Team{Name: "Arsenal FC"}, AwayTeam: model.Team{Name: "Chelsea FC"},
+		},
+		{
+			ID: 497411, UTCDate: kickoff.Add(-7 * 24 * time.Hour), Status: model.MatchFinished, LastUpdated: updated,
+			HomeTeam: model.Team{Name: "Liverpool FC"}, AwayTeam: model.Team{Name: "Everton FC"},
+			Score: model.Score{FullTime: model.ScoreSet{Home: &two, Away: &one}},
+		},
+		{
+			// 1am in UTC is still the previous day in São Paulo.
+			ID: 497412, UTCDate: time.Date(2026, 10, 26, 1, 0, 0, 0, time.UTC), Status: model.MatchScheduled,
+			HomeTeam: model.Team{Name: "Fulham FC"}, AwayTeam: model.Team{Name: "Brentford FC"},
+		},
+		{
+			ID: 497413, UTCDate: kickoff, Status: model.MatchCancelled,
+			HomeTeam: model.Team{Name: "Everton FC"}, AwayTeam: model.Team{Name: "Burnley FC"},
+		},
+	}}
+
+	favorites := newTestFavorites(t, football)
+	db := favorites.Credentials.(*service.CredentialsDatabase).Gorm
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tokens := service.NewCalendarTokensDatabase(db, log)
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	is.Nil(err)
+	calendar := Calendar{
+		Football:      football,
+		Tokens:        &tokens,
+		Credentials:   favorites.Credentials,
+		Location:      saoPaulo,
+		MatchDuration: 2 * time.Hour,
+		Refresh:       time.Hour,
+		Logger:        log,
+	}
+
+	t.Run("when competition has matches, should publish them as events", func(t *testing.T) {
+		rec, err := doCalendar(calendar.CompetitionCalendar, "campeonato_2021", "")
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+		is.Equal("text/calendar; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		is.Contains(rec.Header().Get(echo.HeaderContentDisposition), `filename="campeonato_2021.ics"`)
+
+		body := unfold(rec.Body.String())
+		is.Contains(body, "X-WR-CALNAME:Premier League\r\n")
+		is.Contains(body, "X-WR-TIMEZONE:America/Sao_Paulo\r\n")
+		is.Contains(body, "UID:partida-497410@fut-app\r\n")
+		is.Contains(body, "DTSTART:20261018T140000Z\r\nDTEND:20261018T160000Z\r\n")
+		is.Contains(body, "SUMMARY:Arsenal FC x Chelsea FC\r\n")
+		is.Contains(body, "DESCRIPTION:Premier League\\nRodada 8\r\n")
+		is.Contains(body, "LOCATION:Emirates Stadium\r\n")
+		is.Contains(body, "SUMMARY:Liverpool FC 2 x 1 Everton FC\r\n")
+		is.Contains(body, "DTSTART;VALUE=DATE:20261025\r\n")
+		is.Contains(body, "STATUS:CANCELLED\r\n")
+		is.Equal(4, strings.Count(body, "BEGIN:VEVENT"))
+	})
+
+	t.Run("when kickoff changes, should keep the uid and raise the sequence", func(t *testing.T) {
+		rec, err := doCalendar(calendar.CompetitionCalendar, "campeonato_2021", "")
+		is.Nil(err)
+		before := unfold(rec.Body.String())
+
+		football.matches[0].UTCDate = kickoff.Add(3 * time.Hour)
+		football.matches[0].LastUpdated = updated.Add(24 * time.Hour)
+		t.Cleanup(func() {
+			football.matches[0].UTCDate = kickoff
+			football.matches[0].LastUpdated = updated
+		})
+		rec, err = doCalendar(calendar.CompetitionCalendar, "campeonato_2021", "")
+		is.Nil(err)
+		after := unfold(rec.Body.String())
+
+		sequence := func(body string) int {
+			event := body[strings.Index(body, "UID:partida-497410@fut-app"):]
+			line := event[strings.Index(event, "SEQUENCE:")+len("SEQUENCE:"):]
+			number, err := strconv.Atoi(line[:strings.Index(line, "\r\n")])
+			is.Nil(err)
+			return number
+		}
+		is.Contains(after, "UID:partida-497410@fut-app\r\n")
+		is.Contains(after, "DTSTART:20261018T170000Z\r\n")
+		is.Greater(sequence(after), sequence(before))
+	})
+
+	t.Run("when time zone is requested, should date unconfirmed matches in it", func(t *testing.T) {
+		rec, err := doCalendar(calendar.CompetitionCalendar, "campeonato_2021", "tz=Europe/London")
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+		body := unfold(rec.Body.String())
+		is.Contains(body, "X-WR-TIMEZONE:Europe/London\r\n")
+		is.Contains(body, "DTSTART;VALUE=DATE:20261026\r\n")
+	})
+
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		id           string
+		query        string
+		expectedCode int
+	}{
+		{
+			name:         "when time zone is unknown, should return bad request",
+			handler:      calendar.CompetitionCalendar,
+			id:           "campeonato_2021",
+			query:        "tz=Mars/Olympus_Mons",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when competition id is malformed, should return bad request",
+			handler:      calendar.CompetitionCalendar,
+			id:           "2021",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when competition fails upstream, should return error",
+			handler:      calendar.CompetitionCalendar,
+			id:           "campeonato_2013",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "when team exists, should publish its matches",
+			handler:      calendar.TeamCalendar,
+			id:           "time_057",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when team is unknown, should return not found",
+			handler:      calendar.TeamCalendar,
+			id:           "time_999",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec, err := doCalendar(test.handler, test.id, test.query)
+			is.Nil(err)
+			is.Equal(test.expectedCode, rec.Code)
+		})
+	}
+
+	t.Run("when token is created, should only keep the latest one", func(t *testing.T) {
+		rec, err := doJSON(calendar.CreateCalendarToken, "", map[string]any{"user": "fan"})
+		is.Nil(err)
+		is.Equal(http.StatusCreated, rec.Code)
+		var first model.CreatedCalendarToken
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &first))
+		is.True(strings.HasPrefix(first.Token, model.CalendarTokenPrefix))
+		is.Equal(first.Token[:len(first.Prefix)], first.Prefix)
+
+		rec, err = doJSON(calendar.CreateCalendarToken, "", map[string]any{"user": "fan"})
+		is.Nil(err)
+		is.Equal(http.StatusCreated, rec.Code)
+
+		_, err = tokens.AuthenticateCalendarToken(t.Context(), first.Token)
+		is.ErrorIs(err, service.ErrCalendarTokenNotFound)
+	})
+
+	t.Run("when token is deleted, should return not found the second time", func(t *testing.T) {
+		rec, err := doID(calendar.DeleteCalendarToken, http.MethodDelete, "")
+		is.Nil(err)
+		is.Equal(http.StatusNoContent, rec.Code)
+
+		rec, err = doID(calendar.DeleteCalendarToken, http.MethodDelete, "")
+		is.Nil(err)
+		is.Equal(http.StatusNotFound, rec.Code)
+	})
+}
@@ -1,7 +1,8 @@
 package controller
 
 import (
-	"fmt"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -12,27 +13,98 @@ import (
 
 type Champion struct {
 	Service service.FootballAPI
+	Logger  *slog.Logger
 }
 
-func NewChampion(footbal service.Football) Champion {
+func NewChampion(footbal service.Football, logger *slog.Logger) Champion {
 	return Champion{
 		Service: &footbal,
+		Logger:  logger,
 	}
 }
 
 func (cham *Champion) Championship(c echo.Context) error {
 	competitionResponse, err := cham.Service.CompetitionList(c.Request().Context())
 	if err != nil {
+		cham.Logger.ErrorContext(c.Request().Context(), "failed to list competitions", "error", err)
 		return c.JSON(
 			http.StatusInternalServerError,
 			err,
 		)
 	}
 	formatedCompetition := FilterCompetitions(*competitionResponse)
-	return c.JSON(
-		http.StatusOK,
-		formatedCompetition,
-	)
+	return respond(c, "campeonatos", formatedCompetition)
+}
+
+// Standings lists the overall table of a competition, one per group in
+// cups.
+func (cham *Champion) Standings(c echo.Context) error {
+	competitionID, err := model.ParseCompetitionID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	standings, err := cham.Service.CompetitionStandings(ctx, competitionID)
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "competition not found",
+			},
+		)
+	}
+	if err != nil {
+		cham.Logger.ErrorContext(ctx, "failed to fetch standings", "competition", competitionID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	return respond(c, model.CompetitionID(competitionID)+"-classificacao", model.FormatStandings(*standings))
+}
+
+// Scorers ranks the top scorers of a competition.
+func (cham *Champion) Scorers(c echo.Context) error {
+	competitionID, err := model.ParseCompetitionID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	scorers, err := cham.Service.CompetitionScorers(ctx, competitionID)
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "competition not found",
+			},
+		)
+	}
+	if err != nil {
+		cham.Logger.ErrorContext(ctx, "failed to fetch scorers", "competition", competitionID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	return respond(c, model.CompetitionID(competitionID)+"-artilharia", model.FormatScorers(*scorers))
 }
 
 func FilterCompetitions(data model.CompetitionResponse) []model.FormattedCompetition {
@@ -40,7 +112,7 @@ func FilterCompetitions(data model.CompetitionResponse) []model.FormattedCompeti
 
 	for _, comp := range data.Competitions {
 		formatted := model.FormattedCompetition{
-			ID:        fmt.Sprintf("campeonato_%03d", comp.ID),
+			ID:        model.CompetitionID(comp.ID),
 			Nome:      comp.Name,
 			Temporada: extractYear(comp.CurrentSeason.StartDate),
 		}
@@ -1,13 +1,68 @@
 package controller
 
+import (
+	"net/http"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
 type Controller struct {
-	Auth     Auth
-	Champion Champion
+	Auth          Auth
+	OIDC          OIDC
+	APIKeys       APIKeys
+	Audit         Audit
+	Organizations Organizations
+	Champion      Champion
+	Favorites     Favorites
+	Calendar      Calendar
+	Webhooks      Webhooks
+	Live          Live
+	Socket        Socket
+	GraphQL       GraphQL
+	Docs          Docs
 }
 
-func NewController(auth Auth, champion Champion) *Controller {
+func NewController(
+	auth Auth,
+	oidc OIDC,
+	apiKeys APIKeys,
+	audit Audit,
+	organizations Organizations,
+	champion Champion,
+	favorites Favorites,
+	calendar Calendar,
+	webhooks Webhooks,
+	live Live,
+	socket Socket,
+	graphQL GraphQL,
+	docs Docs,
+) *Controller {
 	return &Controller{
-		Auth:     auth,
-		Champion: champion,
+		Auth:          auth,
+		OIDC:          oidc,
+		APIKeys:       apiKeys,
+		Audit:         audit,
+		Organizations: organizations,
+		Champion:      champion,
+		Favorites:     favorites,
+		Calendar:      calendar,
+		Webhooks:      webhooks,
+		Live:          live,
+		Socket:        socket,
+		GraphQL:       graphQL,
+		Docs:          docs,
+	}
+}
+
+// currentCredential loads the credential of the user authenticated by the
+// middleware.
+func currentCredential(c echo.Context, credentials service.CredentialsRepository) (*model.Credential, error) {
+	user, _ := c.Get("user").(string)
+	credential, err := credentials.FindCredentials(c.Request().Context(), &model.Credential{User: user})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "unknown user")
 	}
+	return credential, nil
 }
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// UpdateCredentials lets admins managing users change the role, scopes or
+// email of another credential. Changing the role or scopes revokes the
+// sessions of the credential, whose tokens carry the old ones.
+func (a Auth) UpdateCredentials(c echo.Context) error {
+	var req model.CredentialUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	credential, err := a.Service.FindCredentials(ctx, &model.Credential{User: c.Param("user")})
+	if err != nil {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "credentials not found",
+			},
+		)
+	}
+
+	columns := []string{"updated_at"}
+	previousRole := credential.Role
+	if req.Role != nil && *req.Role != credential.Role {
+		credential.Role = *req.Role
+		columns = append(columns, "role")
+	}
+	if req.Scope != nil {
+		credential.Scopes = model.ParseScopes(*req.Scope)
+		granted, _ := c.Get("scopes").(model.Scopes)
+		if unknown := credential.AllowedScopes().Unknown(granted); len(unknown) > 0 {
+			return c.JSON(
+				http.StatusForbidden,
+				map[string]string{
+					"error": "cannot grant scopes not present in your token: " + unknown.String(),
+				},
+			)
+		}
+		columns = append(columns, "scopes")
+	}
+	if req.Email != nil {
+		credential.Email = *req.Email
+		columns = append(columns, "email")
+	}
+
+	credential.UpdatedAt = time.Now()
+	if slices.Contains(columns, "role") || slices.Contains(columns, "scopes") {
+		credential.SessionsRevokedAt = &credential.UpdatedAt
+		columns = append(columns, "sessions_revoked_at")
+	}
+	if err := a.Service.UpdateCredentials(ctx, credential, columns...); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to update credentials",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(ctx, "credentials updated", "target", credential.User, "columns", columns[1:])
+	recordAudit(c, a.Audit, model.AuditCredentialUpdate, credential.User, true, "")
+	if credential.Role != previousRole {
+		recordAudit(c, a.Audit, model.AuditRoleChange, credential.User, true, previousRole+" -> "+credential.Role)
+	}
+	return c.JSON(
+		http.StatusOK,
+		map[string]string{
+			"message": "credentials updated",
+		},
+	)
+}
+
+func (a Auth) DeleteCredentials(c echo.Context) error {
+	ctx := c.Request().Context()
+	target := &model.Credential{User: c.Param("user")}
+	if actor, _ := c.Get("user").(string); actor == target.User {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": "cannot delete your own credentials",
+			},
+		)
+	}
+
+	credential, err := a.Service.FindCredentials(ctx, target)
+	if err != nil {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "credentials not found",
+			},
+		)
+	}
+	if err := a.Service.DeleteCredentials(ctx, credential); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.JSON(
+				http.StatusNotFound,
+				map[string]string{
+					"error": "credentials not found",
+				},
+			)
+		}
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to delete credentials",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(ctx, "credentials deleted", "target", credential.User)
+	recordAudit(c, a.Audit, model.AuditCredentialDelete, credential.User, true, "")
+	return c.NoContent(http.StatusNoContent)
+}
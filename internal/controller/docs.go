@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/fut-app/api"
+	"github.com/labstack/echo/v4"
+)
+
+type Docs struct{}
+
+func NewDocs() Docs {
+	return Docs{}
+}
+
+func (d Docs) OpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, api.OpenAPI)
+}
+
+func (d Docs) SwaggerUI(c echo.Context) error {
+	return c.HTMLBlob(http.StatusOK, api.SwaggerUI)
+}
+
+// SwaggerUIAsset serves the vendored Swagger UI files, so /docs works
+// without reaching a third-party CDN.
+func (d Docs) SwaggerUIAsset(c echo.Context) error {
+	assets, err := fs.Sub(api.SwaggerUIAssets, "swagger-ui")
+	if err != nil {
+		return err
+	}
+	return echo.StaticFileHandler(c.Param("asset"), assets)(c)
+}
@@ -0,0 +1,35 @@
+//go:build unit
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerUI(t *testing.T) {
+	docs := NewDocs()
+
+	t.Run("when the page is served, should load assets from this server", func(t *testing.T) {
+		is := require.New(t)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/docs", nil), rec)
+
+		is.NoError(docs.SwaggerUI(c))
+		is.Contains(rec.Body.String(), `src="/docs/swagger-ui-bundle.js"`)
+		is.NotContains(rec.Body.String(), "https://")
+	})
+
+	t.Run("when the asset is not vendored, should return not found", func(t *testing.T) {
+		is := require.New(t)
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/docs/missing.js", nil), httptest.NewRecorder())
+		c.SetParamNames("asset")
+		c.SetParamValues("missing.js")
+
+		is.ErrorIs(docs.SwaggerUIAsset(c), echo.ErrNotFound)
+	})
+}
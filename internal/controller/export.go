@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fut-app/internal/i18n"
+	"github.com/fut-app/pkg/csvexport"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+
+	mimeTextCSV = "text/csv"
+
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+	// defaultLocale picks the CSV separator when the client sends none.
+	defaultLocale = "pt-BR"
+)
+
+// respond writes rows as JSON or, when the client asks with ?format=csv or
+// Accept: text/csv, as a CSV download named filename. Both are localized
+// after the Accept-Language header. CSV separators follow ?locale= or the
+// same header, and ?bom=true prefixes the UTF-8 byte order mark Excel needs
+// to detect the encoding.
+func respond[T any](c echo.Context, filename string, rows []T) error {
+	format, err := negotiateFormat(c.Request())
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	if format == formatJSON {
+		return localizedJSON(c, http.StatusOK, rows)
+	}
+
+	catalogue := negotiateLanguage(c)
+	opts := csvexport.ForLocale(requestLocale(c.Request()))
+	opts.Rename = catalogue.Field
+	if value := c.QueryParam("bom"); value != "" {
+		opts.BOM, err = strconv.ParseBool(value)
+		if err != nil {
+			return c.JSON(
+				http.StatusBadRequest,
+				map[string]string{
+					"error": "bom must be true or false",
+				},
+			)
+		}
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	c.Response().WriteHeader(http.StatusOK)
+	return csvexport.Write(c.Response(), rows, opts)
+}
+
+// localizedJSON writes v with the field names and labels of the language
+// negotiated by negotiateLanguage. Portuguese keeps the view model keys.
+func localizedJSON(c echo.Context, status int, v any) error {
+	catalogue := negotiateLanguage(c)
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// Match the newline echo's encoder ends c.JSON with.
+	localized, err := catalogue.Localize(append(encoded, '\n'))
+	if err != nil {
+		return err
+	}
+	return c.JSONBlob(status, localized)
+}
+
+// negotiateLanguage picks the catalogue for the Accept-Language header and
+// announces it in Content-Language.
+func negotiateLanguage(c echo.Context) *i18n.Catalogue {
+	catalogue := i18n.Negotiate(c.Request().Header.Get(headerAcceptLanguage))
+	header := c.Response().Header()
+	header.Set(headerContentLanguage, catalogue.Language)
+	header.Add(echo.HeaderVary, headerAcceptLanguage)
+	return catalogue
+}
+
+// negotiateFormat prefers the format query parameter over the Accept
+// header, defaulting to JSON.
+func negotiateFormat(req *http.Request) (string, error) {
+	switch format := req.URL.Query().Get("format"); format {
+	case formatJSON, formatCSV:
+		return format, nil
+	case "":
+	default:
+		return "", errors.New("format must be json or csv")
+	}
+
+	for _, accepted := range strings.Split(req.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		switch strings.TrimSpace(mediaType) {
+		case mimeTextCSV:
+			return formatCSV, nil
+		case echo.MIMEApplicationJSON, "*/*":
+			return formatJSON, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// requestLocale returns ?locale= or the first Accept-Language tag.
+func requestLocale(req *http.Request) string {
+	if locale := req.URL.Query().Get("locale"); locale != "" {
+		return locale
+	}
+	first, _, _ := strings.Cut(req.Header.Get(headerAcceptLanguage), ",")
+	tag, _, _ := strings.Cut(first, ";")
+	if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+		return tag
+	}
+	return defaultLocale
+}
@@ -0,0 +1,125 @@
+//go:build unit
+
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fut-app/pkg/csvexport"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	is := require.New(t)
+	champion := &Champion{
+		Service: &fakeFootball{},
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	tests := []struct {
+		name                string
+		handler             echo.HandlerFunc
+		query               string
+		headers             map[string]string
+		expectedCode        int
+		expectedContentType string
+		expectedLanguage    string
+		expectedBody        string
+	}{
+		{
+			name:                "when nothing is negotiated, should return JSON",
+			handler:             champion.Championship,
+			expectedCode:        http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedBody:        `[{"id":"campeonato_2013","nome":"Campeonato Brasileiro Série A","temporada":""},{"id":"campeonato_2021","nome":"Premier League","temporada":""}]` + "\n",
+		},
+		{
+			name:                "when CSV is accepted, should separate fields for the default locale",
+			handler:             champion.Championship,
+			headers:             map[string]string{echo.HeaderAccept: "text/csv"},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id;nome;temporada\r\ncampeonato_2013;Campeonato Brasileiro Série A;\r\ncampeonato_2021;Premier League;\r\n",
+		},
+		{
+			name:                "when CSV is requested in English with the BOM, should use commas and English columns",
+			handler:             champion.Standings,
+			query:               "format=csv&bom=true",
+			headers:             map[string]string{"Accept-Language": "en-US,en;q=0.9"},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: csvexport.BOM + "group,position,team,points,played,won,draw,lost,goals_for,goals_against,goal_difference\r\n" +
+				",1,Arsenal FC,30,0,0,0,0,0,0,0\r\n,2,Chelsea FC,28,0,0,0,0,0,0,0\r\n",
+		},
+		{
+			name:                "when the query asks for JSON, should ignore the Accept header",
+			handler:             champion.Scorers,
+			query:               "format=json",
+			headers:             map[string]string{echo.HeaderAccept: "text/csv"},
+			expectedCode:        http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSON,
+		},
+		{
+			name:                "when locale is in the query, should prefer it for the separators",
+			handler:             champion.Scorers,
+			query:               "format=csv&locale=es",
+			headers:             map[string]string{"Accept-Language": "en"},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "position;player;team;played;goals;assists;penalties\r\n" +
+				"1;Bukayo Saka;Arsenal FC;0;9;2;0\r\n1;Cole Palmer;Chelsea FC;0;9;0;0\r\n3;Kai Havertz;Arsenal FC;0;6;0;0\r\n",
+		},
+		{
+			name:                "when JSON is requested in Spanish, should translate the keys",
+			handler:             champion.Championship,
+			headers:             map[string]string{"Accept-Language": "es-AR,en;q=0.5"},
+			expectedCode:        http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedLanguage:    "es",
+			expectedBody:        `[{"id":"campeonato_2013","nombre":"Campeonato Brasileiro Série A","temporada":""},{"id":"campeonato_2021","nombre":"Premier League","temporada":""}]` + "\n",
+		},
+		{
+			name:         "when format is unknown, should return bad request",
+			handler:      champion.Championship,
+			query:        "format=xlsx",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when bom is not a boolean, should return bad request",
+			handler:      champion.Championship,
+			query:        "format=csv&bom=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?"+test.query, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("campeonato_2021")
+
+			is.Nil(test.handler(c))
+			is.Equal(test.expectedCode, rec.Code)
+			if test.expectedContentType != "" {
+				is.Equal(test.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			}
+			if test.expectedLanguage != "" {
+				is.Equal(test.expectedLanguage, rec.Header().Get("Content-Language"))
+			}
+			if test.expectedBody != "" {
+				is.Equal(test.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// feedHorizon bounds how far ahead the feed looks for matches.
+	feedHorizon = 14 * 24 * time.Hour
+	// feedMatches is the number of upcoming matches listed per item.
+	feedMatches = 5
+	// feedConcurrency bounds the football app calls made at once per feed.
+	feedConcurrency = 4
+)
+
+type Favorites struct {
+	Service     service.FavoritesRepository
+	Credentials service.CredentialsRepository
+	Football    service.FootballAPI
+	Logger      *slog.Logger
+}
+
+// NewFavorites serves the favorites and their feed, which reads the football
+// app through a cache shared by every user.
+func NewFavorites(
+	db service.FavoritesDatabase,
+	credentials service.CredentialsDatabase,
+	football service.Football,
+	cfg config.FavoritesConfig,
+	logger *slog.Logger,
+) Favorites {
+	return Favorites{
+		Service:     &db,
+		Credentials: &credentials,
+		Football:    service.NewFootballCache(&football, cfg.FeedCacheTTL),
+		Logger:      logger,
+	}
+}
+
+func (f Favorites) ListFavorites(c echo.Context) error {
+	credential, err := currentCredential(c, f.Credentials)
+	if err != nil {
+		return err
+	}
+
+	favorites, err := f.Service.ListFavorites(c.Request().Context(), credential.ID)
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	response := model.Favorites{
+		Campeonatos: []model.FormattedFavorite{},
+		Times:       []model.FormattedFavorite{},
+	}
+	for _, favorite := range favorites {
+		formatted := formatFavorite(favorite)
+		if favorite.Kind == model.FavoriteTeam {
+			response.Times = append(response.Times, formatted)
+		} else {
+			response.Campeonatos = append(response.Campeonatos, formatted)
+		}
+	}
+	return localizedJSON(c, http.StatusOK, response)
+}
+
+// FollowCompetition follows a competition listed by the football app.
+func (f Favorites) FollowCompetition(c echo.Context) error {
+	competitionID, err := model.ParseCompetitionID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	competitions, err := f.Football.CompetitionList(ctx)
+	if err != nil {
+		f.Logger.ErrorContext(ctx, "failed to list competitions", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	index := slices.IndexFunc(competitions.Competitions, func(competition model.Competition) bool {
+		return competition.ID == competitionID
+	})
+	if index < 0 {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "competition not found",
+			},
+		)
+	}
+
+	return f.follow(c, model.FavoriteCompetition, competitionID, competitions.Competitions[index].Name)
+}
+
+func (f Favorites) FollowTeam(c echo.Context) error {
+	teamID, err := model.ParseTeamID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	team, err := f.Football.Team(ctx, teamID)
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": "team not found",
+			},
+		)
+	}
+	if err != nil {
+		f.Logger.ErrorContext(ctx, "failed to fetch team", "team", teamID, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	return f.follow(c, model.FavoriteTeam, teamID, team.Name)
+}
+
+func (f Favorites) follow(c echo.Context, kind string, externalID int, name string) error {
+	credential, err := currentCredential(c, f.Credentials)
+	if err != nil {
+		return err
+	}
+
+	favorite := &model.Favorite{
+		CredentialID: credential.ID,
+		Kind:         kind,
+		ExternalID:   externalID,
+		Name:         name,
+		CreatedAt:    time.Now(),
+	}
+	err = f.Service.AddFavorite(c.Request().Context(), favorite)
+	if errors.Is(err, service.ErrTooManyFavorites) {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return localizedJSON(c, http.StatusOK, formatFavorite(*favorite))
+}
+
+func (f Favorites) UnfollowCompetition(c echo.Context) error {
+	competitionID, err := model.ParseCompetitionID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return f.unfollow(c, model.FavoriteCompetition, competitionID)
+}
+
+func (f Favorites) UnfollowTeam(c echo.Context) error {
+	teamID, err := model.ParseTeamID(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return f.unfollow(c, model.FavoriteTeam, teamID)
+}
+
+func (f Favorites) unfollow(c echo.Context, kind string, externalID int) error {
+	credential, err := currentCredential(c, f.Credentials)
+	if err != nil {
+		return err
+	}
+
+	err = f.Service.RemoveFavorite(c.Request().Context(), credential.ID, kind, externalID)
+	if errors.Is(err, service.ErrFavoriteNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Feed aggregates the upcoming matches of every followed item and the
+// standings of followed competitions. An item failing upstream reports its
+// error without failing the whole feed.
+func (f Favorites) Feed(c echo.Context) error {
+	credential, err := currentCredential(c, f.Credentials)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	favorites, err := f.Service.ListFavorites(ctx, credential.ID)
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	now := time.Now().UTC()
+	filter := model.MatchFilter{
+		DateFrom: now.Format(time.DateOnly),
+		DateTo:   now.Add(feedHorizon).Format(time.DateOnly),
+	}
+
+	feed := model.Feed{
+		Campeonatos: []model.FeedCompetition{},
+		Times:       []model.FeedTeam{},
+	}
+	for _, favorite := range favorites {
+		if favorite.Kind == model.FavoriteTeam {
+			feed.Times = append(feed.Times, model.FeedTeam{
+				ID:               favorite.PublicID(),
+				Nome:             favorite.Name,
+				ProximasPartidas: []model.FormattedMatch{},
+			})
+		} else {
+			feed.Campeonatos = append(feed.Campeonatos, model.FeedCompetition{
+				ID:               favorite.PublicID(),
+				Nome:             favorite.Name,
+				ProximasPartidas: []model.FormattedMatch{},
+				Classificacao:    []model.FormattedStanding{},
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, feedConcurrency)
+	run := func(fetch func()) {
+		wg.Go(func() {
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			fetch()
+		})
+	}
+
+	competitions, teams := 0, 0
+	for _, favorite := range favorites {
+		if favorite.Kind == model.FavoriteTeam {
+			item := &feed.Times[teams]
+			teams++
+			run(func() {
+				matches, err := f.Football.TeamMatches(ctx, favorite.ExternalID, filter)
+				if err != nil {
+					f.Logger.WarnContext(ctx, "failed to fetch team matches", "team", favorite.ExternalID, "error", err)
+					item.Erro = err.Error()
+					return
+				}
+				item.ProximasPartidas = upcomingMatches(matches.Matches, now)
+			})
+			continue
+		}
+
+		item := &feed.Campeonatos[competitions]
+		competitions++
+		var mu sync.Mutex
+		run(func() {
+			matches, err := f.Football.CompetitionMatches(ctx, favorite.ExternalID, filter)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				f.Logger.WarnContext(ctx, "failed to fetch competition matches", "competition", favorite.ExternalID, "error", err)
+				item.Erro = err.Error()
+				return
+			}
+			item.ProximasPartidas = upcomingMatches(matches.Matches, now)
+		})
+		run(func() {
+			standings, err := f.Football.CompetitionStandings(ctx, favorite.ExternalID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				f.Logger.WarnContext(ctx, "failed to fetch standings", "competition", favorite.ExternalID, "error", err)
+				item.Erro = err.Error()
+				return
+			}
+			item.Classificacao = model.FormatStandings(*standings)
+		})
+	}
+	wg.Wait()
+
+	return localizedJSON(c, http.StatusOK, feed)
+}
+
+func formatFavorite(favorite model.Favorite) model.FormattedFavorite {
+	return model.FormattedFavorite{
+		ID:       favorite.PublicID(),
+		Nome:     favorite.Name,
+		CriadoEm: favorite.CreatedAt,
+	}
+}
+
+// upcomingMatches keeps the next matches yet to start, soonest first.
+func upcomingMatches(matches []model.Match, now time.Time) []model.FormattedMatch {
+	upcoming := slices.DeleteFunc(slices.Clone(matches), func(match model.Match) bool {
+		scheduled := match.Status == model.MatchScheduled || match.Status == model.MatchTimed
+		return !scheduled || match.UTCDate.Before(now)
+	})
+	slices.SortStableFunc(upcoming, func(a, b model.Match) int {
+		return a.UTCDate.Compare(b.UTCDate)
+	})
+	return model.FormatMatches(upcoming[:min(len(upcoming), feedMatches)])
+}
@@ -0,0 +1,257 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFootball serves canned football app data. Competition 2013 fails to
+// load its matches.
+type fakeFootball struct {
+	matches []model.Match
+}
+
+func (f *fakeFootball) CompetitionList(context.Context) (*model.CompetitionResponse, error) {
+	return &model.CompetitionResponse{Competitions: []model.Competition{
+		{ID: 2013, Name: "Campeonato Brasileiro Série A"},
+		{ID: 2021, Name: "Premier League"},
+	}}, nil
+}
+
+func (f *fakeFootball) CompetitionMatches(_ context.Context, competitionID int, _ model.MatchFilter) (*model.MatchResponse, error) {
+	if competitionID == 2013 {
+		return nil, service.ErrToComunicateFootbalAPP
+	}
+	return &model.MatchResponse{Matches: f.matches}, nil
+}
+
+func (f *fakeFootball) CompetitionStandings(context.Context, int) (*model.StandingsResponse, error) {
+	return &model.StandingsResponse{Standings: []model.Standing{
+		{Type: "HOME", Table: []model.TableRow{{Position: 1, Team: model.Team{Name: "Arsenal FC"}}}},
+		{Type: "TOTAL", Table: []model.TableRow{
+			{Position: 1, Team: model.Team{Name: "Arsenal FC"}, Points: 30},
+			{Position: 2, Team: model.Team{Name: "Chelsea FC"}, Points: 28},
+		}},
+	}}, nil
+}
+
+func (f *fakeFootball) CompetitionScorers(context.Context, int) (*model.ScorersResponse, error) {
+	two := 2
+	return &model.ScorersResponse{Scorers: []model.Scorer{
+		{Player: model.Player{Name: "Bukayo Saka"}, Team: model.Team{Name: "Arsenal FC"}, Goals: 9, Assists: &two},
+		{Player: model.Player{Name: "Cole Palmer"}, Team: model.Team{Name: "Chelsea FC"}, Goals: 9},
+		{Player: model.Player{Name: "Kai Havertz"}, Team: model.Team{Name: "Arsenal FC"}, Goals: 6},
+	}}, nil
+}
+
+func (f *fakeFootball) Team(_ context.Context, teamID int) (*model.Team, error) {
+	if teamID != 57 {
+		return nil, service.ErrFootballNotFound
+	}
+	return &model.Team{ID: 57, Name: "Arsenal FC"}, nil
+}
+
+func (f *fakeFootball) TeamMatches(context.Context, int, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{Matches: f.matches[:1]}, nil
+}
+
+func (f *fakeFootball) Matches(context.Context, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{Matches: f.matches}, nil
+}
+
+func newTestFavorites(t *testing.T, football service.FootballAPI) Favorites {
+	t.Helper()
+	auth := newTestAuth(t)
+	db := auth.Service.(*service.CredentialsDatabase).Gorm
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	require.Nil(t, err)
+
+	favorites := service.NewFavoritesDatabase(db, 50, log)
+	return Favorites{Service: &favorites, Credentials: auth.Service, Football: football, Logger: log}
+}
+
+// doID calls handler as fan with the :id path parameter set.
+func doID(handler echo.HandlerFunc, method string, id string) (*httptest.ResponseRecorder, error) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(method, "/", nil), rec)
+	c.Set("user", "fan")
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	return rec, handler(c)
+}
+
+func TestFavorites(t *testing.T) {
+	is := require.New(t)
+	now := time.Now().UTC()
+	football := &fakeFootball{matches: []model.Match{
+		{ID: 3, UTCDate: now.Add(48 * time.Hour), Status: model.MatchTimed, HomeTeam: model.Team{Name: "Chelsea FC"}, AwayTeam: model.Team{Name: "Arsenal FC"}},
+		{ID: 1, UTCDate: now.Add(-time.Hour), Status: model.MatchFinished},
+		{ID: 2, UTCDate: now.Add(24 * time.Hour), Status: model.MatchScheduled, HomeTeam: model.Team{Name: "Arsenal FC"}, AwayTeam: model.Team{Name: "Liverpool FC"}, Competition: model.Competition{ID: 2021}},
+	}}
+	favorites := newTestFavorites(t, football)
+
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		method       string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "when competition is listed, should follow it",
+			handler:      favorites.FollowCompetition,
+			method:       http.MethodPut,
+			id:           "campeonato_2021",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when following again, should stay idempotent",
+			handler:      favorites.FollowCompetition,
+			method:       http.MethodPut,
+			id:           "campeonato_2021",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when competition fails upstream, should still follow it",
+			handler:      favorites.FollowCompetition,
+			method:       http.MethodPut,
+			id:           "campeonato_2013",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when competition is unknown, should return not found",
+			handler:      favorites.FollowCompetition,
+			method:       http.MethodPut,
+			id:           "campeonato_999",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "when id is malformed, should return bad request",
+			handler:      favorites.FollowCompetition,
+			method:       http.MethodPut,
+			id:           "2021",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when team exists, should follow it",
+			handler:      favorites.FollowTeam,
+			method:       http.MethodPut,
+			id:           "time_057",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when team is unknown, should return not found",
+			handler:      favorites.FollowTeam,
+			method:       http.MethodPut,
+			id:           "time_058",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "when team is not followed, should return not found on unfollow",
+			handler:      favorites.UnfollowTeam,
+			method:       http.MethodDelete,
+			id:           "time_058",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		rec, err := doID(test.handler, test.method, test.id)
+		is.Nil(err, test.name)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+	}
+
+	t.Run("when listing favorites, should group them by kind", func(t *testing.T) {
+		rec, err := doID(favorites.ListFavorites, http.MethodGet, "")
+		is.Nil(err)
+
+		var listed model.Favorites
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &listed))
+		is.Len(listed.Campeonatos, 2)
+		is.Equal("campeonato_2013", listed.Campeonatos[0].ID)
+		is.Equal("Premier League", listed.Campeonatos[1].Nome)
+		is.Equal([]string{"time_057"}, []string{listed.Times[0].ID})
+	})
+
+	t.Run("when building the feed, should aggregate upcoming matches and standings", func(t *testing.T) {
+		rec, err := doID(favorites.Feed, http.MethodGet, "")
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		var feed model.Feed
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &feed))
+		is.Len(feed.Campeonatos, 2)
+
+		failing, premier := feed.Campeonatos[0], feed.Campeonatos[1]
+		is.NotEmpty(failing.Erro)
+		is.Empty(failing.ProximasPartidas)
+
+		is.Empty(premier.Erro)
+		is.Len(premier.ProximasPartidas, 2)
+		is.Equal(2, premier.ProximasPartidas[0].ID, "soonest first, finished matches dropped")
+		is.Equal("campeonato_2021", premier.ProximasPartidas[0].Campeonato)
+		is.Len(premier.Classificacao, 2, "only the overall table")
+		is.Equal("Arsenal FC", premier.Classificacao[0].Time)
+
+		is.Len(feed.Times, 1)
+		is.Equal("Arsenal FC", feed.Times[0].Nome)
+		is.Len(feed.Times[0].ProximasPartidas, 1)
+	})
+
+	t.Run("when the feed is asked in English, should translate keys and status", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "en-GB")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", "fan")
+		is.Nil(favorites.Feed(c))
+		is.Equal("en", rec.Header().Get("Content-Language"))
+
+		var feed struct {
+			Competitions []struct {
+				Name            string `json:"name"`
+				UpcomingMatches []struct {
+					HomeTeam    string `json:"home_team"`
+					Status      string `json:"status"`
+					StatusLabel string `json:"status_label"`
+				} `json:"upcoming_matches"`
+			} `json:"competitions"`
+		}
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &feed))
+		is.Len(feed.Competitions, 2)
+		premier := feed.Competitions[1]
+		is.Equal("Premier League", premier.Name)
+		is.Equal("Arsenal FC", premier.UpcomingMatches[0].HomeTeam)
+		is.Equal(model.MatchScheduled, premier.UpcomingMatches[0].Status)
+		is.Equal("Scheduled", premier.UpcomingMatches[0].StatusLabel)
+	})
+
+	t.Run("when unfollowing, should drop it from the feed", func(t *testing.T) {
+		rec, err := doID(favorites.UnfollowCompetition, http.MethodDelete, "campeonato_2013")
+		is.Nil(err)
+		is.Equal(http.StatusNoContent, rec.Code)
+
+		rec, err = doID(favorites.Feed, http.MethodGet, "")
+		is.Nil(err)
+		var feed model.Feed
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &feed))
+		is.Len(feed.Campeonatos, 1)
+	})
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/fut-app/internal/graph"
+	"github.com/labstack/echo/v4"
+)
+
+type GraphQL struct {
+	Schema *graph.Schema
+	Logger *slog.Logger
+}
+
+func NewGraphQL(schema *graph.Schema, logger *slog.Logger) GraphQL {
+	return GraphQL{
+		Schema: schema,
+		Logger: logger,
+	}
+}
+
+// Query runs a GraphQL operation sent as a JSON body, or as the query,
+// operationName and variables parameters of a GET. Errors of the operation
+// itself are part of the 200 response, as GraphQL clients expect.
+func (g GraphQL) Query(c echo.Context) error {
+	var req graph.Request
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return c.JSON(
+					http.StatusBadRequest,
+					map[string]string{
+						"error": "variables must be a JSON object",
+					},
+				)
+			}
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "invalid request body",
+			},
+		)
+	}
+
+	if req.Query == "" {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "query is required",
+			},
+		)
+	}
+
+	return c.JSON(http.StatusOK, g.Schema.Execute(c.Request().Context(), req))
+}
@@ -0,0 +1,92 @@
+//go:build unit
+
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/graph"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL(t *testing.T) {
+	is := require.New(t)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	schema, err := graph.NewSchema(&fakeFootball{}, config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 100}, log)
+	is.Nil(err)
+	control := NewGraphQL(&schema, log)
+
+	tests := []struct {
+		name         string
+		method       string
+		query        url.Values
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "when posting a query, should return its data",
+			method:       http.MethodPost,
+			body:         `{"query":"{ competitions { id name } }"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"competitions":[{"id":"campeonato_2013","name":"Campeonato Brasileiro Série A"},{"id":"campeonato_2021","name":"Premier League"}]}}`,
+		},
+		{
+			name:   "when getting a query with variables, should apply them",
+			method: http.MethodGet,
+			query: url.Values{
+				"query":     {`query Team($id: ID!) { team(id: $id) { name } }`},
+				"variables": {`{"id":"time_057"}`},
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"team":{"name":"Arsenal FC"}}}`,
+		},
+		{
+			name:         "when the query is too deep, should return the limit error",
+			method:       http.MethodPost,
+			body:         `{"query":"{ competition(id: \"campeonato_2021\") { standings { table { team { name } } } } }"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":null,"errors":[{"message":"query depth 5 exceeds the limit of 3","locations":[],"extensions":{"code":"QUERY_TOO_DEEP"}}]}`,
+		},
+		{
+			name:         "when the body is not JSON, should return bad request",
+			method:       http.MethodPost,
+			body:         `query { competitions { id } }`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when variables are not JSON, should return bad request",
+			method:       http.MethodGet,
+			query:        url.Values{"query": {"{ competitions { id } }"}, "variables": {"id=1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when the query is missing, should return bad request",
+			method:       http.MethodPost,
+			body:         `{"operationName":"Competitions"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/graphql?"+test.query.Encode(), strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			is.Nil(control.Query(echo.New().NewContext(req, rec)))
+			is.Equal(test.expectedCode, rec.Code)
+			if test.expectedBody != "" {
+				is.JSONEq(test.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
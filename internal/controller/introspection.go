@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// Introspect implements RFC 7662 for fut-app access tokens. Callers
+// authenticate as one of the configured introspection clients, with HTTP
+// Basic or client_id/client_secret form fields.
+func (a Auth) Introspect(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if !a.authenticateClient(c) {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="fut-app"`)
+		return c.JSON(
+			http.StatusUnauthorized,
+			map[string]string{
+				"error": "invalid_client",
+			},
+		)
+	}
+
+	token := c.FormValue("token")
+	if token == "" {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error":             "invalid_request",
+				"error_description": "token is required",
+			},
+		)
+	}
+
+	return c.JSON(http.StatusOK, a.introspect(c, token))
+}
+
+func (a Auth) introspect(c echo.Context, token string) model.IntrospectionResponse {
+	inactive := model.IntrospectionResponse{Active: false}
+
+	claims, err := middleware.VerifyToken(token, a.SecretKey)
+	if err != nil {
+		return inactive
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != "" {
+		return inactive
+	}
+	user, _ := claims["username"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if user == "" || err != nil || issuedAt == nil {
+		return inactive
+	}
+
+	credential, err := a.Service.FindCredentials(c.Request().Context(), &model.Credential{User: user})
+	if err != nil || credential.SessionRevoked(issuedAt.Time) {
+		return inactive
+	}
+
+	expiresAt, _ := claims.GetExpirationTime()
+	subject, _ := claims.GetSubject()
+	scope, _ := claims["scope"].(string)
+	role, _ := claims["role"].(string)
+	orgID, _ := claims["org_id"].(float64)
+
+	response := model.IntrospectionResponse{
+		Active:    true,
+		Scope:     scope,
+		Username:  user,
+		Role:      role,
+		OrgID:     int(orgID),
+		TokenType: middleware.BearerPrefix,
+		Iat:       issuedAt.Unix(),
+		Sub:       subject,
+	}
+	if expiresAt != nil {
+		response.Exp = expiresAt.Unix()
+	}
+	return response
+}
+
+func (a Auth) authenticateClient(c echo.Context) bool {
+	id, secret, ok := c.Request().BasicAuth()
+	if !ok {
+		id, secret = c.FormValue("client_id"), c.FormValue("client_secret")
+	}
+	return model.IntrospectionClientAllowed(a.Config.IntrospectionClients, id, secret)
+}
+
+// UserInfo returns the profile of the Bearer token owner.
+func (a Auth) UserInfo(c echo.Context) error {
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+	scopes, _ := c.Get("scopes").(model.Scopes)
+	return c.JSON(http.StatusOK, credential.UserInfo(scopes))
+}
@@ -0,0 +1,113 @@
+//go:build unit
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospect(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456","email":"fan@example.com"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.Authenticate, `{"user":"fan","password":"Xpto-123456","scope":"competitions:read"}`, nil)
+	is.Nil(err)
+	var token string
+	is.Nil(json.Unmarshal(rec.Body.Bytes(), &token))
+
+	credential := model.Credential{User: "fan"}
+	challenge, err := credential.GenerateChallengeToken(testSecretKey, nil, time.Minute)
+	is.Nil(err)
+
+	tests := []struct {
+		name           string
+		clientID       string
+		clientSecret   string
+		token          string
+		expectedCode   int
+		expectedActive bool
+	}{
+		{
+			name:         "when client secret is wrong, should return unauthorized",
+			clientID:     "billing",
+			clientSecret: "wrong",
+			token:        token,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "when token is valid, should return it as active",
+			clientID:       "billing",
+			clientSecret:   "billing-secret",
+			token:          token,
+			expectedCode:   http.StatusOK,
+			expectedActive: true,
+		},
+		{
+			name:         "when token is garbage, should return it as inactive",
+			clientID:     "billing",
+			clientSecret: "billing-secret",
+			token:        "xpto",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when token is a two-factor challenge, should return it as inactive",
+			clientID:     "billing",
+			clientSecret: "billing-secret",
+			token:        challenge,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := url.Values{"token": {test.token}}
+			req := httptest.NewRequest(http.MethodPost, "/auth/introspect", strings.NewReader(form.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			req.SetBasicAuth(test.clientID, test.clientSecret)
+			rec := httptest.NewRecorder()
+
+			is.Nil(auth.Introspect(echo.New().NewContext(req, rec)))
+			is.Equal(test.expectedCode, rec.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response model.IntrospectionResponse
+			is.Nil(json.Unmarshal(rec.Body.Bytes(), &response))
+			is.Equal(test.expectedActive, response.Active)
+			if test.expectedActive {
+				is.Equal("fan", response.Username)
+				is.Equal(model.ScopeCompetitionsRead, response.Scope)
+				is.NotZero(response.Exp)
+			} else {
+				is.Empty(response.Username)
+			}
+		})
+	}
+
+	t.Run("when asking for userinfo, should return the token owner profile", func(t *testing.T) {
+		rec, err := doJSON(auth.UserInfo, ``, map[string]any{"user": "fan", "scopes": model.Scopes{model.ScopeCompetitionsRead}})
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		var info model.UserInfo
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &info))
+		is.Equal("fan", info.Sub)
+		is.Equal("fan@example.com", info.Email)
+		is.Equal(model.ScopeCompetitionsRead, info.Scope)
+	})
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type Live struct {
+	Scores    service.LiveSubscriber
+	Heartbeat time.Duration
+	Logger    *slog.Logger
+}
+
+func NewLive(scores *service.LiveScores, heartbeat time.Duration, logger *slog.Logger) Live {
+	return Live{
+		Scores:    scores,
+		Heartbeat: heartbeat,
+		Logger:    logger,
+	}
+}
+
+// Stream serves live matches as Server-Sent Events: a snapshot on connect,
+// then one event per score or status change. A closed stream means the
+// client fell behind; EventSource reconnects and receives a new snapshot.
+func (l Live) Stream(c echo.Context) error {
+	snapshot, events, unsubscribe := l.Scores.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(res, model.LiveSnapshot, snapshot); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(l.Heartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(res, event.Type, event.Partida); err != nil {
+				l.Logger.DebugContext(ctx, "live stream closed", "error", err)
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+func writeEvent(res *echo.Response, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
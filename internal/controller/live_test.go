@@ -0,0 +1,82 @@
+//go:build unit
+
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next Server-Sent Event, skipping heartbeats.
+func readEvent(t *testing.T, stream *bufio.Reader) (string, string) {
+	t.Helper()
+	var event, data string
+	for {
+		line, err := stream.ReadString('\n')
+		require.Nil(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event != "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestLiveStream(t *testing.T) {
+	is := require.New(t)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	football := &fakeFootball{matches: []model.Match{
+		{ID: 7, Status: model.MatchInPlay, HomeTeam: model.Team{Name: "Arsenal FC"}, Competition: model.Competition{ID: 2021}},
+	}}
+
+	scores := &service.LiveScores{Football: football, Interval: time.Hour, Logger: log}
+	t.Cleanup(scores.Close)
+	live := Live{Scores: scores, Heartbeat: 10 * time.Millisecond, Logger: log}
+
+	e := echo.New()
+	e.GET("/partidas/ao-vivo/stream", live.Stream)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/partidas/ao-vivo/stream")
+	is.Nil(err)
+	defer resp.Body.Close()
+	is.Equal(http.StatusOK, resp.StatusCode)
+	is.Equal("text/event-stream", resp.Header.Get(echo.HeaderContentType))
+	stream := bufio.NewReader(resp.Body)
+
+	t.Run("when connecting, should send the snapshot then live updates", func(t *testing.T) {
+		event, data := readEvent(t, stream)
+		is.Equal(model.LiveSnapshot, event)
+		is.JSONEq("[]", data)
+
+		event, data = readEvent(t, stream)
+		is.Equal(model.LiveMatchUpdate, event)
+		var match model.FormattedMatch
+		is.Nil(json.Unmarshal([]byte(data), &match))
+		is.Equal(7, match.ID)
+		is.Equal("campeonato_2021", match.Campeonato)
+	})
+
+	t.Run("when the hub closes, should end the stream", func(t *testing.T) {
+		scores.Close()
+		_, err := io.ReadAll(stream)
+		is.Nil(err)
+	})
+}
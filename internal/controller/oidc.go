@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcStateCookie = "fut_oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+var errNoLinkedCredential = errors.New("no local credentials linked to this account")
+
+// OIDC signs users in through an external provider. Once the provider
+// vouches for the user, logins go on as password logins do in Auth,
+// including the two-factor challenge and the admin enrollment policy.
+type OIDC struct {
+	Auth     Auth
+	Service  service.CredentialsRepository
+	Audit    service.AuditRepository
+	Config   config.OIDCConfig
+	Hasher   hasher.Hasher
+	Provider *oidc.Provider
+	OAuth2   oauth2.Config
+	Logger   *slog.Logger
+}
+
+// NewOIDC discovers the configured issuer. Without an issuer the login
+// endpoints answer 404.
+func NewOIDC(ctx context.Context, auth Auth, cfg config.OIDCConfig) (OIDC, error) {
+	o := OIDC{
+		Auth:    auth,
+		Service: auth.Service,
+		Audit:   auth.Audit,
+		Config:  cfg,
+		Hasher:  auth.Hasher,
+		Logger:  auth.Logger,
+	}
+	if cfg.Issuer == "" {
+		return o, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return o, err
+	}
+	o.Provider = provider
+	o.OAuth2 = oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       cfg.Scopes,
+	}
+	return o, nil
+}
+
+type oidcStateClaims struct {
+	Purpose  string `json:"purpose"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+// Login redirects to the provider with a fresh state, nonce and PKCE
+// verifier kept in a signed, HTTP-only cookie.
+func (o OIDC) Login(c echo.Context) error {
+	if o.Provider == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "oidc login is not configured")
+	}
+
+	state, nonce := rand.Text(), rand.Text()
+	verifier := oauth2.GenerateVerifier()
+	now := time.Now()
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, oidcStateClaims{
+		Purpose:  model.PurposeOIDC,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcStateTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}).SignedString([]byte(o.Auth.SecretKey))
+	if err != nil {
+		o.Logger.ErrorContext(c.Request().Context(), "failed to sign oidc state", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to start oidc login",
+			},
+		)
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    cookie,
+		Path:     "/auth/oidc",
+		MaxAge:   int(oidcStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(o.Config.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	url := o.OAuth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	return c.Redirect(http.StatusFound, url)
+}
+
+// Callback exchanges the authorization code, verifies the ID token and
+// answers as a password login of the linked or provisioned credential: a
+// JWT, a two-factor challenge or an enrollment token.
+func (o OIDC) Callback(c echo.Context) error {
+	if o.Provider == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "oidc login is not configured")
+	}
+	ctx := c.Request().Context()
+
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		o.Logger.WarnContext(ctx, "oidc provider returned an error", "error", providerErr, "description", c.QueryParam("error_description"))
+		return o.reject(c, "provider error: "+providerErr)
+	}
+
+	state, err := o.readState(c)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state.State), []byte(c.QueryParam("state"))) != 1 {
+		return o.reject(c, "invalid or expired login state")
+	}
+	c.SetCookie(&http.Cookie{Name: oidcStateCookie, Path: "/auth/oidc", MaxAge: -1, HttpOnly: true})
+
+	token, err := o.OAuth2.Exchange(ctx, c.QueryParam("code"), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		o.Logger.WarnContext(ctx, "failed to exchange oidc code", "error", err)
+		return o.reject(c, "failed to exchange authorization code")
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return o.reject(c, "provider did not return an id token")
+	}
+
+	idToken, err := o.Provider.Verifier(&oidc.Config{ClientID: o.Config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		o.Logger.WarnContext(ctx, "invalid oidc id token", "error", err)
+		return o.reject(c, "invalid id token")
+	}
+	var claims model.OIDCClaims
+	if err := idToken.Claims(&claims); err != nil || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(state.Nonce)) != 1 {
+		return o.reject(c, "invalid id token")
+	}
+
+	credential, err := o.resolveCredential(c, idToken.Issuer, claims)
+	if errors.Is(err, errNoLinkedCredential) {
+		return o.reject(c, err.Error())
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to create credentials",
+			},
+		)
+	}
+
+	return o.Auth.completeLogin(c, credential, credential.AllowedScopes())
+}
+
+func (o OIDC) readState(c echo.Context) (*oidcStateClaims, error) {
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil {
+		return nil, err
+	}
+	var claims oidcStateClaims
+	_, err = jwt.ParseWithClaims(cookie.Value, &claims, func(*jwt.Token) (any, error) {
+		return []byte(o.Auth.SecretKey), nil
+	}, jwt.WithValidMethods([]string{middleware.MethodHS256}))
+	if err != nil || claims.Purpose != model.PurposeOIDC {
+		return nil, echo.ErrUnauthorized
+	}
+	return &claims, nil
+}
+
+// resolveCredential returns the credential linked to the subject,
+// provisioning one with the default role when allowed. Local credentials
+// are never linked by name, so an external user cannot take them over.
+func (o OIDC) resolveCredential(c echo.Context, issuer string, claims model.OIDCClaims) (*model.Credential, error) {
+	ctx := c.Request().Context()
+	credential, err := o.Service.FindExternalIdentity(ctx, issuer, claims.Subject)
+	if err == nil {
+		return credential, nil
+	}
+	if !o.Config.AutoProvision {
+		return nil, errNoLinkedCredential
+	}
+
+	user := claims.Username()
+	if _, err := o.Service.FindCredentials(ctx, &model.Credential{User: user}); err == nil {
+		user = claims.DisambiguatedUsername(issuer)
+	}
+
+	// The password is random and never revealed: provisioned users can
+	// only sign in through the provider until they reset it.
+	encryptedPassword, err := o.Hasher.Hash(rand.Text())
+	if err != nil {
+		o.Logger.ErrorContext(ctx, "failed to hash password", "error", err)
+		return nil, err
+	}
+	credential = &model.Credential{
+		User:              user,
+		EncryptedPassword: encryptedPassword,
+		Scopes:            model.ParseScopes(o.Config.DefaultScopes),
+		Role:              o.Config.DefaultRole,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	if len(credential.Scopes) == 0 {
+		credential.Scopes = model.DefaultScopes
+	}
+	if claims.EmailVerified {
+		credential.Email = claims.Email
+	}
+	identity := &model.ExternalIdentity{
+		Issuer:    issuer,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now(),
+	}
+	if err := o.Service.ProvisionExternalCredential(ctx, credential, identity); err != nil {
+		return nil, err
+	}
+
+	o.Logger.InfoContext(ctx, "credentials provisioned from oidc", "target", credential.User, "issuer", issuer)
+	recordAudit(c, o.Audit, model.AuditCredentialCreate, credential.User, true, "oidc provisioned from "+issuer)
+	return credential, nil
+}
+
+func (o OIDC) reject(c echo.Context, reason string) error {
+	recordAudit(c, o.Audit, model.AuditLoginFailure, "", false, "oidc: "+reason)
+	return c.JSON(
+		http.StatusUnauthorized,
+		map[string]string{
+			"error": reason,
+		},
+	)
+}
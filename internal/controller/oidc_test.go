@@ -0,0 +1,270 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testOIDCClientID = "fut-app"
+
+// fakeIssuer is a minimal OpenID provider: discovery, JWKS and a token
+// endpoint checking PKCE. Tests play the authorization step by calling
+// authorize with the parameters of the login redirect.
+type fakeIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthorization
+}
+
+type fakeAuthorization struct {
+	challenge string
+	nonce     string
+	subject   string
+	username  string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	issuer := &fakeIssuer{key: key, codes: map[string]fakeAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func (f *fakeIssuer) authorize(code string, redirect string, subject string, username string) {
+	query := mustParseURL(redirect).Query()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.codes[code] = fakeAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		subject:   subject,
+		username:  username,
+	}
+}
+
+func (f *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	authorization, ok := f.codes[r.FormValue("code")]
+	delete(f.codes, r.FormValue("code"))
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                f.URL,
+		"sub":                authorization.subject,
+		"aud":                testOIDCClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Minute).Unix(),
+		"nonce":              authorization.nonce,
+		"preferred_username": authorization.username,
+		"email":              authorization.username + "@example.com",
+		"email_verified":     true,
+	})
+	idToken.Header["kid"] = "test"
+	signed, _ := idToken.SignedString(f.key)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "opaque",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     signed,
+	})
+}
+
+func mustParseURL(raw string) *url.URL {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestOIDCLogin(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	issuer := newFakeIssuer(t)
+
+	o, err := NewOIDC(context.Background(), auth, config.OIDCConfig{
+		Issuer:        issuer.URL,
+		ClientID:      testOIDCClientID,
+		RedirectURL:   "http://localhost:8000/auth/oidc/callback",
+		Scopes:        []string{"openid", "profile", "email"},
+		AutoProvision: true,
+		DefaultRole:   model.RoleUser,
+		DefaultScopes: model.ScopeCompetitionsRead,
+	})
+	is.Nil(err)
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"local","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	// login runs the whole flow and returns the callback response.
+	login := func(subject string, username string, tamperState bool) *httptest.ResponseRecorder {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		is.Nil(o.Login(e.NewContext(httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil), rec)))
+		is.Equal(http.StatusFound, rec.Code)
+		redirect := rec.Header().Get(echo.HeaderLocation)
+		is.Equal("S256", mustParseURL(redirect).Query().Get("code_challenge_method"))
+		issuer.authorize("code-"+subject, redirect, subject, username)
+
+		state := mustParseURL(redirect).Query().Get("state")
+		if tamperState {
+			state = "forged"
+		}
+		callback := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?"+url.Values{"code": {"code-" + subject}, "state": {state}}.Encode(), nil)
+		for _, cookie := range rec.Result().Cookies() {
+			callback.AddCookie(cookie)
+		}
+		rec = httptest.NewRecorder()
+		is.Nil(o.Callback(e.NewContext(callback, rec)))
+		return rec
+	}
+
+	tests := []struct {
+		name           string
+		subject        string
+		username       string
+		tamperState    bool
+		expectedCode   int
+		expectedUser   string
+		expectedScopes string
+	}{
+		{
+			name:           "when subject is new, should provision a credential with the default role",
+			subject:        "sub-1",
+			username:       "torcedor",
+			expectedCode:   http.StatusOK,
+			expectedUser:   "torcedor",
+			expectedScopes: model.ScopeCompetitionsRead,
+		},
+		{
+			name:           "when subject is already linked, should reuse its credential",
+			subject:        "sub-1",
+			username:       "renamed",
+			expectedCode:   http.StatusOK,
+			expectedUser:   "torcedor",
+			expectedScopes: model.ScopeCompetitionsRead,
+		},
+		{
+			name:           "when username belongs to a local credential, should not take it over",
+			subject:        "sub-2",
+			username:       "local",
+			expectedCode:   http.StatusOK,
+			expectedUser:   model.OIDCClaims{Subject: "sub-2", PreferredUsername: "local"}.DisambiguatedUsername(issuer.URL),
+			expectedScopes: model.ScopeCompetitionsRead,
+		},
+		{
+			name:         "when state doesn't match, should return unauthorized",
+			subject:      "sub-3",
+			username:     "forger",
+			tamperState:  true,
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		rec := login(test.subject, test.username, test.tamperState)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+		if test.expectedCode != http.StatusOK {
+			continue
+		}
+
+		var token string
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &token), test.name)
+		claims, err := middleware.VerifyToken(token, testSecretKey)
+		is.Nil(err, test.name)
+		is.Equal(test.expectedUser, claims["username"], test.name)
+		is.Equal(test.expectedScopes, claims["scope"], test.name)
+		is.Equal(model.RoleUser, claims["role"], test.name)
+	}
+
+	t.Run("when linked credential is an admin without two-factor, should return enrollment token", func(t *testing.T) {
+		is := require.New(t)
+		credential, err := auth.Service.FindCredentials(context.Background(), &model.Credential{User: "torcedor"})
+		is.Nil(err)
+		credential.Role = model.RoleAdmin
+		is.Nil(auth.Service.UpdateCredentials(context.Background(), credential, "role"))
+
+		rec := login("sub-1", "torcedor", false)
+		is.Equal(http.StatusOK, rec.Code)
+		var challenge model.LoginChallenge
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &challenge))
+		is.True(challenge.EnrollmentRequired)
+		is.NotEmpty(challenge.EnrollmentToken)
+	})
+
+	t.Run("when linked credential has two-factor enabled, should return a challenge", func(t *testing.T) {
+		is := require.New(t)
+		credential, err := auth.Service.FindCredentials(context.Background(), &model.Credential{User: "torcedor"})
+		is.Nil(err)
+		credential.TOTPEnabled = true
+		is.Nil(auth.Service.UpdateCredentials(context.Background(), credential, "totp_enabled"))
+
+		rec := login("sub-1", "torcedor", false)
+		is.Equal(http.StatusOK, rec.Code)
+		var challenge model.LoginChallenge
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &challenge))
+		is.True(challenge.MFARequired)
+		is.NotEmpty(challenge.ChallengeToken)
+	})
+}
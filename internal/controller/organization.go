@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// Organizations lets platform admins register the tenants owning
+// credentials.
+type Organizations struct {
+	Service service.CredentialsRepository
+	Audit   service.AuditRepository
+	Logger  *slog.Logger
+}
+
+func NewOrganizations(db service.CredentialsDatabase, audit service.AuditRepository, logger *slog.Logger) Organizations {
+	return Organizations{
+		Service: &db,
+		Audit:   audit,
+		Logger:  logger,
+	}
+}
+
+func (o Organizations) CreateOrganization(c echo.Context) error {
+	var req model.OrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		o.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	if _, err := o.Service.FindOrganization(ctx, req.Slug); err == nil {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": "organization already exists",
+			},
+		)
+	}
+
+	organization := &model.Organization{
+		Slug:      req.Slug,
+		Name:      req.Name,
+		CreatedAt: time.Now(),
+	}
+	if err := o.Service.CreateOrganization(ctx, organization); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to create organization",
+			},
+		)
+	}
+
+	o.Logger.InfoContext(ctx, "organization created", "slug", organization.Slug)
+	recordAudit(c, o.Audit, model.AuditOrganizationCreate, organization.Slug, true, "")
+	return c.JSON(http.StatusCreated, organization)
+}
+
+func (o Organizations) ListOrganizations(c echo.Context) error {
+	organizations, err := o.Service.ListOrganizations(c.Request().Context())
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to list organizations",
+			},
+		)
+	}
+	return c.JSON(http.StatusOK, organizations)
+}
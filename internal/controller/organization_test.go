@@ -0,0 +1,145 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// inOrganization runs handler as a member of the organization, as the
+// authentication middleware does for tokens carrying org_id.
+func inOrganization(handler echo.HandlerFunc, orgID int) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		middleware.SetOrganization(c, orgID)
+		return handler(c)
+	}
+}
+
+func TestOrganizations(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	organizations := Organizations{Service: auth.Service, Audit: auth.Audit, Logger: auth.Logger}
+	admin := map[string]any{"scopes": model.KnownScopes, "role": model.RoleAdmin}
+
+	orgIDs := map[string]int{}
+	for _, body := range []string{`{"slug":"tricolor","name":"Torcida Tricolor"}`, `{"slug":"alviverde","name":"Torcida Alviverde"}`} {
+		rec, err := doJSON(organizations.CreateOrganization, body, admin)
+		is.Nil(err)
+		is.Equal(http.StatusCreated, rec.Code)
+
+		var organization model.Organization
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &organization))
+		orgIDs[organization.Slug] = organization.ID
+	}
+	tricolor := orgIDs["tricolor"]
+
+	rec, err := doJSON(organizations.CreateOrganization, `{"slug":"tricolor","name":"Again"}`, admin)
+	is.Nil(err)
+	is.Equal(http.StatusConflict, rec.Code)
+
+	for _, body := range []string{
+		`{"user":"tricolor-admin","password":"Xpto-123456","role":"admin","org":"tricolor"}`,
+		`{"user":"verde-fan","password":"Xpto-123456","org":"alviverde"}`,
+	} {
+		rec, err := doJSON(auth.CreateCredentials, body, admin)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+	}
+
+	t.Run("when an org member logs in, should carry org_id in the token", func(t *testing.T) {
+		auth.SignIn.Config.RequireAdmin2FA = false
+		defer func() { auth.SignIn.Config.RequireAdmin2FA = true }()
+
+		rec, err := doJSON(auth.Authenticate, `{"user":"tricolor-admin","password":"Xpto-123456"}`, nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		var token string
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &token))
+		claims, err := middleware.VerifyToken(token, testSecretKey)
+		is.Nil(err)
+		is.Equal(float64(tricolor), claims["org_id"])
+	})
+
+	t.Run("when an org admin creates a credential, should place it in their organization", func(t *testing.T) {
+		rec, err := doJSON(inOrganization(auth.CreateCredentials, tricolor), `{"user":"tricolor-fan","password":"Xpto-123456"}`, admin)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		credential, err := auth.Service.FindCredentials(context.Background(), &model.Credential{User: "tricolor-fan"})
+		is.Nil(err)
+		is.Equal(tricolor, credential.OrganizationID())
+
+		rec, err = doJSON(inOrganization(auth.CreateCredentials, tricolor), `{"user":"intruder","password":"Xpto-123456","org":"alviverde"}`, admin)
+		is.Nil(err)
+		is.Equal(http.StatusBadRequest, rec.Code)
+	})
+
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		user         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "when an org admin updates their own user, should return ok",
+			handler:      auth.UpdateCredentials,
+			user:         "tricolor-fan",
+			body:         `{"email":"fan@example.com"}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when an org admin updates another organization's user, should return not found",
+			handler:      auth.UpdateCredentials,
+			user:         "verde-fan",
+			body:         `{"email":"fan@example.com"}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "when an org admin deletes another organization's user, should return not found",
+			handler:      auth.DeleteCredentials,
+			user:         "verde-fan",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "when an org admin deletes their own user, should return no content",
+			handler:      auth.DeleteCredentials,
+			user:         "tricolor-fan",
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		rec, err := doParam(inOrganization(test.handler, tricolor), test.user, test.body, map[string]any{
+			"user":   "tricolor-admin",
+			"role":   model.RoleAdmin,
+			"scopes": model.KnownScopes,
+		})
+		is.Nil(err, test.name)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+	}
+
+	t.Run("when an org member lists organizations, should only see their own", func(t *testing.T) {
+		rec, err := doJSON(inOrganization(organizations.ListOrganizations, tricolor), "", admin)
+		is.Nil(err)
+
+		var listed []model.Organization
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &listed))
+		is.Len(listed, 1)
+		is.Equal("tricolor", listed[0].Slug)
+
+		rec, err = doJSON(organizations.ListOrganizations, "", admin)
+		is.Nil(err)
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &listed))
+		is.Len(listed, 2)
+	})
+}
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/mailer"
+	"github.com/labstack/echo/v4"
+)
+
+const forgotPasswordMessage = "if the user exists and has an email, a reset link was sent"
+
+func (a Auth) passwordPolicy() model.PasswordPolicy {
+	return service.NewPasswordPolicy(a.Config.Password)
+}
+
+// rejectWeakPassword writes the 400 listing every policy violation. It
+// returns false when the password is acceptable and nothing was written.
+func (a Auth) rejectWeakPassword(c echo.Context, user string, password string) (bool, error) {
+	var policyErr *model.PasswordPolicyError
+	if !errors.As(a.passwordPolicy().Check(user, password), &policyErr) {
+		return false, nil
+	}
+
+	a.Logger.WarnContext(c.Request().Context(), "password rejected by policy", "target", user, "violations", policyErr.Violations)
+	return true, c.JSON(
+		http.StatusBadRequest,
+		map[string]any{
+			"error":      "password does not meet the policy",
+			"violations": policyErr.Violations,
+		},
+	)
+}
+
+func (a Auth) ChangePassword(c echo.Context) error {
+	var req model.ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+	if !credential.CheckPassword(req.CurrentPassword) {
+		a.Logger.WarnContext(c.Request().Context(), "invalid current password", "target", credential.User)
+		recordAudit(c, a.Audit, model.AuditPasswordChange, credential.User, false, "invalid current password")
+		return c.JSON(
+			http.StatusUnauthorized,
+			map[string]string{
+				"error": "invalid credentials",
+			},
+		)
+	}
+
+	if rejected, err := a.rejectWeakPassword(c, credential.User, req.NewPassword); rejected {
+		return err
+	}
+
+	return a.setPassword(c, credential, req.NewPassword)
+}
+
+func (a Auth) setPassword(c echo.Context, credential *model.Credential, password string) error {
+	encryptedPassword, err := a.Hasher.Hash(password)
+	if err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to hash password", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to update password",
+			},
+		)
+	}
+
+	credential.EncryptedPassword = encryptedPassword
+	credential.UpdatedAt = time.Now()
+	if err := a.Service.UpdateCredentials(c.Request().Context(), credential, "encrypted_password", "updated_at"); err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to update password", "target", credential.User, "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to update password",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(c.Request().Context(), "password changed", "target", credential.User)
+	recordAudit(c, a.Audit, model.AuditPasswordChange, credential.User, true, "")
+	return c.JSON(
+		http.StatusOK,
+		map[string]string{
+			"message": "password changed",
+		},
+	)
+}
+
+// ForgotPassword mails a single-use reset token. It answers the same way,
+// and before looking the user up, whether or not the user exists so
+// neither the body nor the response time can be used to enumerate users.
+func (a Auth) ForgotPassword(c echo.Context) error {
+	var req model.ForgotPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	// The request context ends with the response, the mail must not.
+	ctx := context.WithoutCancel(c.Request().Context())
+	a.mails.Go(func() {
+		a.sendResetToken(ctx, req.User)
+	})
+	return c.JSON(
+		http.StatusAccepted,
+		map[string]string{
+			"message": forgotPasswordMessage,
+		},
+	)
+}
+
+// sendResetToken only logs failures: the caller answered the same way in
+// every case before it ran.
+func (a Auth) sendResetToken(ctx context.Context, user string) {
+	credential, err := a.Service.FindCredentials(ctx, &model.Credential{User: user})
+	if err != nil {
+		return
+	}
+	if credential.Email == "" {
+		a.Logger.WarnContext(ctx, "password reset requested for user without email", "target", credential.User)
+		return
+	}
+
+	token, raw, err := model.NewPasswordResetToken(credential.ID, a.Config.ResetTokenTTL)
+	if err != nil {
+		a.Logger.ErrorContext(ctx, "failed to generate password reset token", "error", err)
+		return
+	}
+	if err := a.Service.CreatePasswordResetToken(ctx, token); err != nil {
+		return
+	}
+
+	if err := a.Mailer.Send(ctx, a.resetMessage(credential, raw)); err != nil {
+		a.Logger.ErrorContext(ctx, "failed to send password reset mail", "target", credential.User, "error", err)
+		return
+	}
+	a.Logger.InfoContext(ctx, "password reset requested", "target", credential.User)
+}
+
+// WaitMails blocks until the reset mails being sent in the background are
+// done.
+func (a Auth) WaitMails() {
+	a.mails.Wait()
+}
+
+func (a Auth) resetMessage(credential *model.Credential, raw string) mailer.Message {
+	instructions := "Use this token on POST /auth/password/reset: " + raw
+	if a.Config.ResetURL != "" {
+		instructions = "Open the link below to choose a new password:\n" + a.Config.ResetURL + "?token=" + url.QueryEscape(raw)
+	}
+
+	return mailer.Message{
+		To:      credential.Email,
+		Subject: "fut-app password reset",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\n%s\n\nIt expires in %s and can be used once. If you did not ask for it, ignore this email.\n",
+			credential.User,
+			instructions,
+			a.Config.ResetTokenTTL,
+		),
+	}
+}
+
+// ResetPassword sets a new password from a reset token and revokes every
+// session issued before it.
+func (a Auth) ResetPassword(c echo.Context) error {
+	var req model.ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	ctx := c.Request().Context()
+	token, err := a.Service.FindPasswordResetToken(ctx, req.Token)
+	if err != nil {
+		a.Logger.WarnContext(ctx, "invalid password reset token", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	if rejected, err := a.rejectWeakPassword(c, token.Credential.User, req.NewPassword); rejected {
+		return err
+	}
+
+	encryptedPassword, err := a.Hasher.Hash(req.NewPassword)
+	if err != nil {
+		a.Logger.ErrorContext(ctx, "failed to hash password", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to update password",
+			},
+		)
+	}
+
+	if err := a.Service.ResetPassword(ctx, token, encryptedPassword); err != nil {
+		if errors.Is(err, service.ErrResetTokenInvalid) {
+			return c.JSON(
+				http.StatusBadRequest,
+				map[string]string{
+					"error": err.Error(),
+				},
+			)
+		}
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to update password",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(ctx, "password reset", "target", token.Credential.User)
+	recordAudit(c, a.Audit, model.AuditPasswordReset, token.Credential.User, true, "sessions revoked")
+	return c.JSON(
+		http.StatusOK,
+		map[string]string{
+			"message": "password reset, sign in again",
+		},
+	)
+}
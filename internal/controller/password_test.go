@@ -0,0 +1,128 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/mailer"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var resetTokenPattern = regexp.MustCompile(model.PasswordResetTokenPrefix + `[0-9a-f]+`)
+
+func TestPasswordReset(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	mail := auth.Mailer.(*fakeMailer)
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456","email":"fan@example.com"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.Authenticate, `{"user":"fan","password":"Xpto-123456"}`, nil)
+	is.Nil(err)
+	var oldToken string
+	is.Nil(json.Unmarshal(rec.Body.Bytes(), &oldToken))
+
+	t.Run("when user doesn't exist, should answer the same without sending mail", func(t *testing.T) {
+		rec, err := doJSON(auth.ForgotPassword, `{"user":"ghost"}`, nil)
+		is.Nil(err)
+		is.Equal(http.StatusAccepted, rec.Code)
+		auth.WaitMails()
+		is.Empty(mail.sent)
+	})
+
+	rec, err = doJSON(auth.ForgotPassword, `{"user":"fan"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusAccepted, rec.Code)
+	auth.WaitMails()
+	is.Len(mail.sent, 1)
+	is.Equal("fan@example.com", mail.sent[0].To)
+	resetToken := resetTokenPattern.FindString(mail.sent[0].Body)
+	is.NotEmpty(resetToken)
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "when token is unknown, should return bad request",
+			body:         `{"token":"fut_rst_00","new_password":"Gol-de-Placa-1970"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when new password violates the policy, should return bad request",
+			body:         `{"token":"` + resetToken + `","new_password":"short"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when token is valid, should reset the password",
+			body:         `{"token":"` + resetToken + `","new_password":"Gol-de-Placa-1970"}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when token was already used, should return bad request",
+			body:         `{"token":"` + resetToken + `","new_password":"Outro-Gol-1994"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		rec, err := doJSON(auth.ResetPassword, test.body, nil)
+		is.Nil(err, test.name)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+	}
+
+	rec, err = doJSON(auth.Authenticate, `{"user":"fan","password":"Gol-de-Placa-1970"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	t.Run("when token was issued before the reset, should reject it", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+oldToken)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		sessions := auth.Service.(middleware.SessionValidator)
+		err := middleware.JWTMiddleware(testSecretKey, sessions)(func(c echo.Context) error { return nil })(c)
+		is.NotNil(err)
+	})
+}
+
+// blockingMailer holds every message until release is closed.
+type blockingMailer struct {
+	release chan struct{}
+}
+
+func (m blockingMailer) Send(ctx context.Context, _ mailer.Message) error {
+	<-m.release
+	return ctx.Err()
+}
+
+func TestForgotPasswordAnswersBeforeSending(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	release := make(chan struct{})
+	auth.Mailer = blockingMailer{release: release}
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456","email":"fan@example.com"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	rec, err = doJSON(auth.ForgotPassword, `{"user":"fan"}`, nil)
+	is.Nil(err)
+	is.Equal(http.StatusAccepted, rec.Code)
+
+	close(release)
+	auth.WaitMails()
+}
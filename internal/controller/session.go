@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+var errSessionExpired = errors.New("session expired, sign in again")
+
+// RefreshToken exchanges a valid access token for a new one with the same
+// scopes, as long as the credential still allows them. The new token keeps
+// the sign-in time of the old one, so sessions end after
+// Config.MaxSessionAge however often they are refreshed.
+func (a Auth) RefreshToken(c echo.Context) error {
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+
+	current, _ := c.Get("scopes").(model.Scopes)
+	if len(current) == 0 {
+		return c.JSON(
+			http.StatusForbidden,
+			map[string]string{
+				"error": "token cannot be refreshed",
+			},
+		)
+	}
+	authTime, ok := c.Get("auth_time").(time.Time)
+	if !ok || time.Since(authTime) > a.Config.MaxSessionAge {
+		recordAudit(c, a.Audit, model.AuditTokenRefresh, credential.User, false, errSessionExpired.Error())
+		return c.JSON(
+			http.StatusUnauthorized,
+			map[string]string{
+				"error": errSessionExpired.Error(),
+			},
+		)
+	}
+	scopes, err := credential.GrantScopes(current)
+	if err != nil {
+		recordAudit(c, a.Audit, model.AuditTokenRefresh, credential.User, false, err.Error())
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error":             "invalid_scope",
+				"error_description": err.Error(),
+			},
+		)
+	}
+
+	token, err := credential.GenerateRefreshedToken(a.SecretKey, scopes, authTime)
+	if err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to generate token", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "something is wrong with provided credentials",
+			},
+		)
+	}
+
+	recordAudit(c, a.Audit, model.AuditTokenRefresh, credential.User, true, "scope="+scopes.String())
+	return c.JSON(
+		http.StatusOK,
+		token,
+	)
+}
+
+// Logout revokes every token issued to the user so far. Tokens are not
+// tracked individually, so this signs the user out of every device.
+func (a Auth) Logout(c echo.Context) error {
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	credential.SessionsRevokedAt = &now
+	if err := a.Service.UpdateCredentials(c.Request().Context(), credential, "sessions_revoked_at"); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to logout",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(c.Request().Context(), "logged out", "target", credential.User)
+	recordAudit(c, a.Audit, model.AuditLogout, credential.User, true, "")
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+//go:build unit
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshToken(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	signedIn := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	tests := []struct {
+		name         string
+		values       map[string]any
+		expectedCode int
+	}{
+		{
+			name:         "when the session is within the maximum age, should keep its sign-in time",
+			values:       map[string]any{"user": "fan", "scopes": model.DefaultScopes, "auth_time": signedIn},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "when the session is older than the maximum age, should return unauthorized",
+			values:       map[string]any{"user": "fan", "scopes": model.DefaultScopes, "auth_time": time.Now().Add(-2 * time.Hour)},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "when the token has no scopes, should return forbidden",
+			values:       map[string]any{"user": "fan", "auth_time": signedIn},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec, err := doJSON(auth.RefreshToken, ``, test.values)
+			is.Nil(err)
+			is.Equal(test.expectedCode, rec.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+
+			var token string
+			is.Nil(json.Unmarshal(rec.Body.Bytes(), &token))
+			claims, err := middleware.VerifyToken(token, testSecretKey)
+			is.Nil(err)
+			authTime, ok := middleware.AuthTime(claims)
+			is.True(ok)
+			is.Equal(signedIn.Unix(), authTime.Unix())
+		})
+	}
+}
+
+func TestUpdateCredentialsRevokesSessions(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	admin := map[string]any{"user": "root", "role": model.RoleAdmin, "scopes": model.KnownScopes}
+	sessions := auth.Service.(middleware.SessionValidator)
+
+	tests := []struct {
+		name          string
+		body          string
+		expectRevoked bool
+	}{
+		{
+			name:          "when the role changes, should revoke the sessions",
+			body:          `{"role":"admin"}`,
+			expectRevoked: true,
+		},
+		{
+			name:          "when the scopes change, should revoke the sessions",
+			body:          `{"scope":"competitions:read"}`,
+			expectRevoked: true,
+		},
+		{
+			name: "when only the email changes, should keep the sessions",
+			body: `{"email":"fan@example.com"}`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := fmt.Sprintf("fan-%d", i)
+			rec, err := doJSON(auth.CreateCredentials, `{"user":"`+user+`","password":"Xpto-123456"}`, admin)
+			is.Nil(err)
+			is.Equal(http.StatusOK, rec.Code)
+			issuedAt := time.Now().Truncate(time.Second)
+
+			rec, err = doParam(auth.UpdateCredentials, user, test.body, admin)
+			is.Nil(err)
+			is.Equal(http.StatusOK, rec.Code)
+
+			err = sessions.ValidateSession(context.Background(), user, issuedAt)
+			is.Equal(test.expectRevoked, err != nil)
+		})
+	}
+}
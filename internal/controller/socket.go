@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// socketWriteWait bounds each write to the client.
+	socketWriteWait = 10 * time.Second
+	// socketReadLimit bounds the size of client messages.
+	socketReadLimit = 4096
+)
+
+// Socket serves the WebSocket API described in api/websocket.md.
+type Socket struct {
+	Hub          service.SocketHub
+	Upgrader     websocket.Upgrader
+	PingInterval time.Duration
+	Logger       *slog.Logger
+}
+
+func NewSocket(hub *service.Hub, pingInterval time.Duration, logger *slog.Logger) Socket {
+	return Socket{
+		Hub:          hub,
+		PingInterval: pingInterval,
+		Logger:       logger,
+	}
+}
+
+// Connect upgrades the authenticated request and serves the socket until
+// either side closes it. The connection is closed when the token expires.
+func (s Socket) Connect(c echo.Context) error {
+	conn, err := s.Upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader already replied with the error status.
+		s.Logger.WarnContext(c.Request().Context(), "failed to upgrade socket", "error", err)
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	client := s.Hub.Register()
+	expiresAt, _ := c.Get("expires_at").(time.Time)
+	go s.write(ctx, conn, client, expiresAt)
+
+	s.read(ctx, conn, client)
+	s.Hub.Unregister(client)
+	return nil
+}
+
+// read dispatches client messages until the connection fails or goes
+// silent for two ping intervals.
+func (s Socket) read(ctx context.Context, conn *websocket.Conn, client *service.HubClient) {
+	conn.SetReadLimit(socketReadLimit)
+	extend := func() error {
+		return conn.SetReadDeadline(time.Now().Add(2 * s.PingInterval))
+	}
+	extend()
+	conn.SetPongHandler(func(string) error { return extend() })
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.Logger.DebugContext(ctx, "socket closed", "error", err)
+			}
+			return
+		}
+		extend()
+
+		var req model.SocketRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			s.Hub.Reply(client, model.SocketMessage{Type: model.SocketError, Erro: "invalid JSON"})
+			continue
+		}
+		competitionID, err := req.Validate()
+		if err != nil {
+			s.Hub.Reply(client, model.SocketMessage{Type: model.SocketError, Campeonato: req.Campeonato, Erro: err.Error()})
+			continue
+		}
+
+		switch req.Type {
+		case model.SocketPing:
+			s.Hub.Reply(client, model.SocketMessage{Type: model.SocketPong})
+		case model.SocketSubscribe:
+			if err := s.Hub.Subscribe(client, competitionID); errors.Is(err, service.ErrTooManySubscriptions) {
+				s.Hub.Reply(client, model.SocketMessage{Type: model.SocketError, Campeonato: req.Campeonato, Erro: err.Error()})
+			}
+		case model.SocketUnsubscribe:
+			s.Hub.Unsubscribe(client, competitionID)
+		}
+	}
+}
+
+// write is the only writer of the connection. It sends queued messages and
+// pings, and closes the connection once the hub drops the client or the
+// token expires.
+func (s Socket) write(ctx context.Context, conn *websocket.Conn, client *service.HubClient, expiresAt time.Time) {
+	defer conn.Close()
+	ping := time.NewTicker(s.PingInterval)
+	defer ping.Stop()
+
+	var expired <-chan time.Time
+	if !expiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(expiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	closeWith := func(code int, reason string) {
+		message := websocket.FormatCloseMessage(code, reason)
+		conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(socketWriteWait))
+	}
+
+	for {
+		select {
+		case message, ok := <-client.Send:
+			if !ok {
+				// Dropped for falling behind or the server is shutting down.
+				closeWith(websocket.CloseTryAgainLater, "reconnect")
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				s.Logger.DebugContext(ctx, "failed to write socket message", "error", err)
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); err != nil {
+				return
+			}
+		case <-expired:
+			closeWith(websocket.ClosePolicyViolation, "token expired")
+			return
+		}
+	}
+}
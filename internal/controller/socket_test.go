@@ -0,0 +1,119 @@
+//go:build unit
+
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLive never reports live matches.
+type fakeLive struct{}
+
+func (fakeLive) Subscribe() ([]model.FormattedMatch, <-chan model.LiveEvent, func()) {
+	return nil, make(chan model.LiveEvent), func() {}
+}
+
+// dialSocket serves Connect with a token expiring after ttl and dials it.
+func dialSocket(t *testing.T, hub *service.Hub, ttl time.Duration) *websocket.Conn {
+	t.Helper()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	socket := Socket{Hub: hub, PingInterval: time.Minute, Logger: log}
+
+	e := echo.New()
+	e.GET("/ws", socket.Connect, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("expires_at", time.Now().Add(ttl))
+			return next(c)
+		}
+	})
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) model.SocketMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var message model.SocketMessage
+	require.Nil(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestSocket(t *testing.T) {
+	is := require.New(t)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	hub := &service.Hub{Live: fakeLive{}, Football: &fakeFootball{}, Interval: time.Hour, MaxSubscriptions: 1, Logger: log}
+	t.Cleanup(hub.Close)
+
+	conn := dialSocket(t, hub, time.Hour)
+
+	tests := []struct {
+		name     string
+		message  string
+		expected model.SocketMessage
+	}{
+		{
+			name:     "when message is not JSON, should reply an error",
+			message:  `subscribe`,
+			expected: model.SocketMessage{Type: model.SocketError, Erro: "invalid JSON"},
+		},
+		{
+			name:     "when type is unknown, should reply an error",
+			message:  `{"tipo":"shout"}`,
+			expected: model.SocketMessage{Type: model.SocketError, Erro: "unknown message type"},
+		},
+		{
+			name:     "when pinged, should reply pong",
+			message:  `{"tipo":"ping"}`,
+			expected: model.SocketMessage{Type: model.SocketPong},
+		},
+		{
+			name:     "when subscribing, should acknowledge",
+			message:  `{"tipo":"subscribe","campeonato":"campeonato_2021"}`,
+			expected: model.SocketMessage{Type: model.SocketSubscribed, Campeonato: "campeonato_2021"},
+		},
+	}
+	for _, test := range tests {
+		is.Nil(conn.WriteMessage(websocket.TextMessage, []byte(test.message)), test.name)
+		is.Equal(test.expected, readMessage(t, conn), test.name)
+	}
+
+	t.Run("when subscribed, should push standings and scorers", func(t *testing.T) {
+		received := map[string]model.SocketMessage{}
+		for range 2 {
+			message := readMessage(t, conn)
+			received[message.Type] = message
+		}
+		is.Len(received[model.SocketStandings].Classificacao, 2)
+		is.Equal(1, received[model.SocketScorers].Artilharia[1].Posicao, "tied scorers share the position")
+	})
+
+	t.Run("when over the subscription limit, should reply an error", func(t *testing.T) {
+		is.Nil(conn.WriteJSON(model.SocketRequest{Type: model.SocketSubscribe, Campeonato: "campeonato_2014"}))
+		message := readMessage(t, conn)
+		is.Equal(model.SocketError, message.Type)
+		is.Equal(service.ErrTooManySubscriptions.Error(), message.Erro)
+	})
+
+	t.Run("when the token expires, should close the socket", func(t *testing.T) {
+		expiring := dialSocket(t, hub, 50*time.Millisecond)
+		expiring.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := expiring.ReadMessage()
+		is.True(websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+	})
+}
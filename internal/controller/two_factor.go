@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/totp"
+	"github.com/labstack/echo/v4"
+)
+
+func (a Auth) AuthenticateTwoFactor(c echo.Context) error {
+	var req model.TwoFactorLoginRequest
+	if err := c.Bind(&req); err != nil {
+		a.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	result, err := a.SignIn.TwoFactor(c.Request().Context(), req)
+	return a.respondLogin(c, "", result, err)
+}
+
+func (a Auth) EnrollTwoFactor(c echo.Context) error {
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+	if credential.TOTPEnabled {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": "two-factor authentication is already enabled",
+			},
+		)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to generate totp secret", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to start enrollment",
+			},
+		)
+	}
+
+	credential.TOTPSecret = secret
+	credential.UpdatedAt = time.Now()
+	if err := a.Service.UpdateCredentials(c.Request().Context(), credential, "totp_secret", "updated_at"); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to start enrollment",
+			},
+		)
+	}
+
+	return c.JSON(
+		http.StatusOK,
+		model.TwoFactorEnrollment{
+			Secret:          secret,
+			ProvisioningURI: totp.ProvisioningURI(a.Config.TOTPIssuer, credential.User, secret),
+		},
+	)
+}
+
+func (a Auth) ActivateTwoFactor(c echo.Context) error {
+	var req model.TwoFactorRequest
+	if err := c.Bind(&req); err != nil || req.Validate() != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "code is required",
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+	if credential.TOTPEnabled || credential.TOTPSecret == "" {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": "no pending two-factor enrollment",
+			},
+		)
+	}
+	if err := a.SignIn.CheckCode(c.Request().Context(), credential, req.Code); err != nil {
+		return respondTwoFactorCode(c, err)
+	}
+
+	codes, hashes, err := model.GenerateRecoveryCodes()
+	if err == nil {
+		err = a.Service.ReplaceRecoveryCodes(c.Request().Context(), credential.ID, hashes)
+	}
+	if err == nil {
+		credential.TOTPEnabled = true
+		credential.UpdatedAt = time.Now()
+		err = a.Service.UpdateCredentials(c.Request().Context(), credential, "totp_enabled", "updated_at")
+	}
+	if err != nil {
+		a.Logger.ErrorContext(c.Request().Context(), "failed to activate two-factor", "error", err)
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to activate two-factor authentication",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(c.Request().Context(), "two-factor enabled", "target", credential.User)
+	return c.JSON(
+		http.StatusOK,
+		model.RecoveryCodes{
+			RecoveryCodes: codes,
+		},
+	)
+}
+
+func (a Auth) DisableTwoFactor(c echo.Context) error {
+	var req model.TwoFactorRequest
+	if err := c.Bind(&req); err != nil || req.Validate() != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "code is required",
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, a.Service)
+	if err != nil {
+		return err
+	}
+	if a.Config.RequireAdmin2FA && credential.Role == model.RoleAdmin {
+		return c.JSON(
+			http.StatusForbidden,
+			map[string]string{
+				"error": "two-factor authentication is mandatory for admins",
+			},
+		)
+	}
+	if !credential.TOTPEnabled {
+		return c.JSON(
+			http.StatusConflict,
+			map[string]string{
+				"error": "two-factor authentication is not enabled",
+			},
+		)
+	}
+	if err := a.SignIn.CheckCode(c.Request().Context(), credential, req.Code); err != nil {
+		return respondTwoFactorCode(c, err)
+	}
+
+	credential.TOTPEnabled = false
+	credential.TOTPSecret = ""
+	credential.UpdatedAt = time.Now()
+	err = a.Service.UpdateCredentials(c.Request().Context(), credential, "totp_enabled", "totp_secret", "updated_at")
+	if err == nil {
+		err = a.Service.ReplaceRecoveryCodes(c.Request().Context(), credential.ID, nil)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": "failed to disable two-factor authentication",
+			},
+		)
+	}
+
+	a.Logger.InfoContext(c.Request().Context(), "two-factor disabled", "target", credential.User)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// respondTwoFactorCode answers a wrong code sent to manage two-factor.
+func respondTwoFactorCode(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrTwoFactorLocked) {
+		return c.JSON(
+			http.StatusTooManyRequests,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return c.JSON(
+		http.StatusBadRequest,
+		map[string]string{
+			"error": service.ErrInvalidTwoFactorCode.Error(),
+		},
+	)
+}
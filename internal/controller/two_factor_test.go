@@ -0,0 +1,176 @@
+//go:build unit
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorFlow(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	admin := map[string]any{"user": "boss", "role": model.RoleAdmin, "scopes": model.KnownScopes}
+
+	rec, err := doJSON(auth.CreateCredentials, fmt.Sprintf(`{"user":"boss","password":"Xpto-123456","role":"admin","scope":%q}`, model.KnownScopes), admin)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+
+	t.Run("when admin is not enrolled and policy forces 2FA, should return enrollment token", func(t *testing.T) {
+		rec, err := doJSON(auth.Authenticate, `{"user":"boss","password":"Xpto-123456"}`, nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		var challenge model.LoginChallenge
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &challenge))
+		is.True(challenge.EnrollmentRequired)
+		is.NotEmpty(challenge.EnrollmentToken)
+	})
+
+	rec, err = doJSON(auth.EnrollTwoFactor, ``, admin)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+	var enrollment model.TwoFactorEnrollment
+	is.Nil(json.Unmarshal(rec.Body.Bytes(), &enrollment))
+	is.Contains(enrollment.ProvisioningURI, "otpauth://totp/")
+
+	now := time.Now()
+	code, err := totp.Code(enrollment.Secret, totp.Step(now))
+	is.Nil(err)
+	rec, err = doJSON(auth.ActivateTwoFactor, fmt.Sprintf(`{"code":%q}`, code), admin)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+	var recovery model.RecoveryCodes
+	is.Nil(json.Unmarshal(rec.Body.Bytes(), &recovery))
+	is.Len(recovery.RecoveryCodes, model.RecoveryCodesCount)
+
+	login := func() string {
+		rec, err := doJSON(auth.Authenticate, `{"user":"boss","password":"Xpto-123456","scope":"users:manage"}`, nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+		var challenge model.LoginChallenge
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &challenge))
+		is.True(challenge.MFARequired)
+		return challenge.ChallengeToken
+	}
+
+	t.Run("when code was already used, should reject replay", func(t *testing.T) {
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"code":%q}`, login(), code), nil)
+		is.Nil(err)
+		is.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("when code is valid, should return token with challenged scopes", func(t *testing.T) {
+		next, err := totp.Code(enrollment.Secret, totp.Step(now)+1)
+		is.Nil(err)
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"code":%q}`, login(), next), nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		var token string
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &token))
+		is.NotEmpty(token)
+	})
+
+	t.Run("when recovery code is used twice, should accept only the first time", func(t *testing.T) {
+		body := fmt.Sprintf(`{"challenge_token":%q,"recovery_code":%q}`, login(), recovery.RecoveryCodes[0])
+		rec, err := doJSON(auth.AuthenticateTwoFactor, body, nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		rec, err = doJSON(auth.AuthenticateTwoFactor, body, nil)
+		is.Nil(err)
+		is.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("when the challenge was already redeemed, should return unauthorized", func(t *testing.T) {
+		challenge := login()
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"recovery_code":%q}`, challenge, recovery.RecoveryCodes[1]), nil)
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+
+		rec, err = doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"recovery_code":%q}`, challenge, recovery.RecoveryCodes[2]), nil)
+		is.Nil(err)
+		is.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("when a newer login replaced the challenge, should return unauthorized", func(t *testing.T) {
+		replaced := login()
+		login()
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"recovery_code":%q}`, replaced, recovery.RecoveryCodes[2]), nil)
+		is.Nil(err)
+		is.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("when access token is used as challenge, should return unauthorized", func(t *testing.T) {
+		credential := model.Credential{User: "boss"}
+		token, err := credential.GenerateToken(testSecretKey, model.KnownScopes)
+		is.Nil(err)
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"code":"000000"}`, token), nil)
+		is.Nil(err)
+		is.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("when admin tries to disable mandatory 2FA, should return forbidden", func(t *testing.T) {
+		rec, err := doJSON(auth.DisableTwoFactor, `{"code":"000000"}`, admin)
+		is.Nil(err)
+		is.Equal(http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("when too many codes are wrong, should lock the second login step", func(t *testing.T) {
+		challenge := login()
+		for range auth.Config.MFAMaxFailures - 1 {
+			rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"code":"000000"}`, challenge), nil)
+			is.Nil(err)
+			is.Equal(http.StatusUnauthorized, rec.Code)
+		}
+		rec, err := doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"code":"000000"}`, challenge), nil)
+		is.Nil(err)
+		is.Equal(http.StatusTooManyRequests, rec.Code)
+
+		rec, err = doJSON(auth.AuthenticateTwoFactor, fmt.Sprintf(`{"challenge_token":%q,"recovery_code":%q}`, login(), recovery.RecoveryCodes[3]), nil)
+		is.Nil(err)
+		is.Equal(http.StatusTooManyRequests, rec.Code)
+	})
+}
+
+func TestTwoFactorManagementLockout(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	fan := map[string]any{"user": "fan", "scopes": model.KnownScopes}
+
+	rec, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+	rec, err = doJSON(auth.EnrollTwoFactor, ``, fan)
+	is.Nil(err)
+	is.Equal(http.StatusOK, rec.Code)
+	var enrollment model.TwoFactorEnrollment
+	is.Nil(json.Unmarshal(rec.Body.Bytes(), &enrollment))
+
+	t.Run("when activation codes are wrong, should count towards the login lockout", func(t *testing.T) {
+		for range auth.Config.MFAMaxFailures - 1 {
+			rec, err := doJSON(auth.ActivateTwoFactor, `{"code":"000000"}`, fan)
+			is.Nil(err)
+			is.Equal(http.StatusBadRequest, rec.Code)
+		}
+		rec, err := doJSON(auth.ActivateTwoFactor, `{"code":"000000"}`, fan)
+		is.Nil(err)
+		is.Equal(http.StatusTooManyRequests, rec.Code)
+	})
+
+	t.Run("when locked, should refuse even a valid code", func(t *testing.T) {
+		code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+		is.Nil(err)
+		rec, err := doJSON(auth.ActivateTwoFactor, fmt.Sprintf(`{"code":%q}`, code), fan)
+		is.Nil(err)
+		is.Equal(http.StatusTooManyRequests, rec.Code)
+	})
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// deliveriesLimit is the number of deliveries listed per webhook.
+const deliveriesLimit = 50
+
+type Webhooks struct {
+	Service     service.WebhooksRepository
+	Credentials service.CredentialsRepository
+	Logger      *slog.Logger
+}
+
+func NewWebhooks(db service.WebhooksDatabase, credentials service.CredentialsDatabase, logger *slog.Logger) Webhooks {
+	return Webhooks{
+		Service:     &db,
+		Credentials: &credentials,
+		Logger:      logger,
+	}
+}
+
+// CreateWebhook registers a webhook for the followed competitions. The
+// signing secret is only returned here.
+func (w Webhooks) CreateWebhook(c echo.Context) error {
+	var req model.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		w.Logger.WarnContext(c.Request().Context(), "failed to bind request", "error", err)
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "Invalid JSON",
+			},
+		)
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, w.Credentials)
+	if err != nil {
+		return err
+	}
+
+	webhook := req.NewWebhook(credential.ID)
+	if err := w.Service.CreateWebhook(c.Request().Context(), webhook); err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	w.Logger.InfoContext(c.Request().Context(), "webhook created", "webhook", webhook.ID)
+	return c.JSON(
+		http.StatusCreated,
+		model.CreatedWebhook{
+			Webhook: *webhook,
+			Secret:  webhook.Secret,
+		},
+	)
+}
+
+func (w Webhooks) ListWebhooks(c echo.Context) error {
+	credential, err := currentCredential(c, w.Credentials)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := w.Service.ListWebhooks(c.Request().Context(), credential.ID)
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+func (w Webhooks) DeleteWebhook(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "invalid webhook id",
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, w.Credentials)
+	if err != nil {
+		return err
+	}
+
+	err = w.Service.DeleteWebhook(c.Request().Context(), credential.ID, id)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	w.Logger.InfoContext(c.Request().Context(), "webhook deleted", "webhook", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func (w Webhooks) ListDeliveries(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(
+			http.StatusBadRequest,
+			map[string]string{
+				"error": "invalid webhook id",
+			},
+		)
+	}
+
+	credential, err := currentCredential(c, w.Credentials)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := w.Service.ListDeliveries(c.Request().Context(), credential.ID, id, deliveriesLimit)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return c.JSON(
+			http.StatusNotFound,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err != nil {
+		return c.JSON(
+			http.StatusInternalServerError,
+			map[string]string{
+				"error": err.Error(),
+			},
+		)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	is := require.New(t)
+	auth := newTestAuth(t)
+	db := auth.Service.(*service.CredentialsDatabase).Gorm
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := doJSON(auth.CreateCredentials, `{"user":"fan","password":"Xpto-123456"}`, withScopes(model.KnownScopes))
+	is.Nil(err)
+
+	repository := service.NewWebhooksDatabase(db, log)
+	webhooks := Webhooks{Service: &repository, Credentials: auth.Service, Logger: log}
+	fan := map[string]any{"user": "fan"}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "when url is not http, should return bad request",
+			body:         `{"url":"ftp://example.com","events":["goal"]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when event is unknown, should return bad request",
+			body:         `{"url":"https://example.com/hook","events":["goal","corner"]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "when no event is given, should return bad request",
+			body:         `{"url":"https://example.com/hook","events":[]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		rec, err := doJSON(webhooks.CreateWebhook, test.body, fan)
+		is.Nil(err, test.name)
+		is.Equal(test.expectedCode, rec.Code, test.name)
+	}
+
+	var created model.CreatedWebhook
+	t.Run("when request is valid, should return the secret once", func(t *testing.T) {
+		rec, err := doJSON(webhooks.CreateWebhook, `{"url":"https://example.com/hook","events":["goal","match.finished"]}`, fan)
+		is.Nil(err)
+		is.Equal(http.StatusCreated, rec.Code)
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &created))
+		is.True(strings.HasPrefix(created.Secret, model.WebhookSecretPrefix))
+
+		rec, err = doID(webhooks.ListWebhooks, http.MethodGet, "")
+		is.Nil(err)
+		is.NotContains(rec.Body.String(), created.Secret)
+
+		var listed []model.Webhook
+		is.Nil(json.Unmarshal(rec.Body.Bytes(), &listed))
+		is.Len(listed, 1)
+		is.Equal(model.WebhookEvents{"goal", "match.finished"}, listed[0].Events)
+	})
+
+	t.Run("when listing deliveries of a new webhook, should return an empty log", func(t *testing.T) {
+		rec, err := doID(webhooks.ListDeliveries, http.MethodGet, strconv.Itoa(created.ID))
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+		is.JSONEq("[]", rec.Body.String())
+	})
+
+	t.Run("when deleting, should remove the webhook once", func(t *testing.T) {
+		rec, err := doID(webhooks.DeleteWebhook, http.MethodDelete, strconv.Itoa(created.ID))
+		is.Nil(err)
+		is.Equal(http.StatusNoContent, rec.Code)
+
+		rec, err = doID(webhooks.DeleteWebhook, http.MethodDelete, strconv.Itoa(created.ID))
+		is.Nil(err)
+		is.Equal(http.StatusNotFound, rec.Code)
+
+		rec, err = doID(webhooks.ListDeliveries, http.MethodGet, strconv.Itoa(created.ID))
+		is.Nil(err)
+		is.Equal(http.StatusNotFound, rec.Code)
+	})
+}
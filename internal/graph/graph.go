@@ -0,0 +1,80 @@
+// Package graph serves the football domain over GraphQL. Fields resolve
+// through service.FootballAPI with per-request loaders that batch and
+// cache the calls, and operations are measured before they run so one
+// query cannot exhaust the football app quota.
+package graph
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL over HTTP request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Schema struct {
+	Schema        graphql.Schema
+	Football      service.FootballAPI
+	MaxDepth      int
+	MaxComplexity int
+	Logger        *slog.Logger
+}
+
+func NewSchema(football service.FootballAPI, cfg config.GraphQLConfig, logger *slog.Logger) (Schema, error) {
+	schema, err := newSchema()
+	if err != nil {
+		return Schema{}, err
+	}
+	return Schema{
+		Schema:        schema,
+		Football:      football,
+		MaxDepth:      cfg.MaxDepth,
+		MaxComplexity: cfg.MaxComplexity,
+		Logger:        logger,
+	}, nil
+}
+
+// Execute parses, validates, measures and runs request.
+func (s *Schema) Execute(ctx context.Context, request Request) *graphql.Result {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(request.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.Schema, document, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+	if errs := checkLimits(&s.Schema, document, request.OperationName, s.MaxDepth, s.MaxComplexity); len(errs) > 0 {
+		s.Logger.WarnContext(ctx, "graphql query rejected", "operation", request.OperationName, "error", errs[0].Message)
+		return &graphql.Result{Errors: errs}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.Schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       withLoaders(ctx, newLoaders(ctx, s.Football)),
+	})
+	if result.HasErrors() {
+		s.Logger.DebugContext(ctx, "graphql query failed", "operation", request.OperationName, "errors", len(result.Errors))
+	}
+	return result
+}
@@ -0,0 +1,297 @@
+//go:build unit
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFootball serves one Premier League season and counts the calls
+// each method receives.
+type countingFootball struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *countingFootball) count(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+}
+
+func (f *countingFootball) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+var (
+	arsenal = model.Team{ID: 57, Name: "Arsenal FC", TLA: "ARS"}
+	chelsea = model.Team{ID: 61, Name: "Chelsea FC", TLA: "CHE"}
+	premier = model.Competition{
+		ID:            2021,
+		Name:          "Premier League",
+		Code:          "PL",
+		CurrentSeason: model.Season{ID: 2403, StartDate: "2025-08-15", EndDate: "2026-05-24"},
+	}
+)
+
+func (f *countingFootball) CompetitionList(context.Context) (*model.CompetitionResponse, error) {
+	f.count("CompetitionList")
+	return &model.CompetitionResponse{Competitions: []model.Competition{premier}}, nil
+}
+
+func (f *countingFootball) CompetitionMatches(_ context.Context, _ int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	f.count("CompetitionMatches")
+	two, one := 2, 1
+	matches := []model.Match{
+		{ID: 1, UTCDate: time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC), Status: model.MatchFinished, Competition: model.Competition{ID: 2021, Name: "Premier League"}, HomeTeam: arsenal, AwayTeam: chelsea, Score: model.Score{FullTime: model.ScoreSet{Home: &two, Away: &one}}},
+		{ID: 2, UTCDate: time.Date(2026, 10, 25, 14, 0, 0, 0, time.UTC), Status: model.MatchScheduled, Competition: model.Competition{ID: 2021, Name: "Premier League"}, HomeTeam: chelsea, AwayTeam: arsenal},
+	}
+	if filter.Status != "" {
+		var filtered []model.Match
+		for _, match := range matches {
+			if match.Status == filter.Status {
+				filtered = append(filtered, match)
+			}
+		}
+		matches = filtered
+	}
+	return &model.MatchResponse{Matches: matches}, nil
+}
+
+func (f *countingFootball) CompetitionStandings(context.Context, int) (*model.StandingsResponse, error) {
+	f.count("CompetitionStandings")
+	return &model.StandingsResponse{Standings: []model.Standing{
+		{Type: "TOTAL", Table: []model.TableRow{
+			{Position: 1, Team: arsenal, Points: 30},
+			{Position: 2, Team: chelsea, Points: 28},
+		}},
+		{Type: "HOME", Table: []model.TableRow{{Position: 1, Team: chelsea}}},
+	}}, nil
+}
+
+func (f *countingFootball) CompetitionScorers(context.Context, int) (*model.ScorersResponse, error) {
+	f.count("CompetitionScorers")
+	return &model.ScorersResponse{Scorers: []model.Scorer{
+		{Player: model.Player{ID: 7, Name: "Bukayo Saka"}, Team: arsenal, Goals: 9},
+	}}, nil
+}
+
+func (f *countingFootball) Team(_ context.Context, teamID int) (*model.Team, error) {
+	f.count("Team")
+	for _, team := range []model.Team{arsenal, chelsea} {
+		if team.ID == teamID {
+			team.Venue = team.Name + " Stadium"
+			team.LastUpdated = "2026-10-01T00:00:00Z"
+			return &team, nil
+		}
+	}
+	return nil, service.ErrFootballNotFound
+}
+
+func (f *countingFootball) TeamMatches(context.Context, int, model.MatchFilter) (*model.MatchResponse, error) {
+	f.count("TeamMatches")
+	return &model.MatchResponse{Matches: []model.Match{}}, nil
+}
+
+func (f *countingFootball) Matches(context.Context, model.MatchFilter) (*model.MatchResponse, error) {
+	f.count("Matches")
+	return &model.MatchResponse{Matches: []model.Match{}}, nil
+}
+
+func newTestSchema(t *testing.T, cfg config.GraphQLConfig) (*Schema, *countingFootball) {
+	t.Helper()
+	football := &countingFootball{calls: map[string]int{}}
+	schema, err := NewSchema(football, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.Nil(t, err)
+	return &schema, football
+}
+
+// execute runs query and returns its data re-encoded as JSON.
+func execute(t *testing.T, schema *Schema, query string, variables map[string]any) (string, []string) {
+	t.Helper()
+	result := schema.Execute(context.Background(), Request{Query: query, Variables: variables})
+	var messages []string
+	for _, err := range result.Errors {
+		messages = append(messages, err.Message)
+	}
+	data, err := json.Marshal(result.Data)
+	require.Nil(t, err)
+	return string(data), messages
+}
+
+func TestExecute(t *testing.T) {
+	is := require.New(t)
+	limits := config.GraphQLConfig{MaxDepth: 10, MaxComplexity: 1000}
+
+	t.Run("when walking competition, season, standings and teams, should batch team loads", func(t *testing.T) {
+		schema, football := newTestSchema(t, limits)
+		data, errs := execute(t, schema, `{
+			competition(id: "campeonato_2021") {
+				id
+				name
+				seasons {
+					id
+					standings { table { position team { id name venue } } }
+				}
+				standings(type: HOME) { table { team { venue } } }
+			}
+		}`, nil)
+
+		is.Empty(errs)
+		is.JSONEq(`{"competition":{
+			"id":"campeonato_2021",
+			"name":"Premier League",
+			"seasons":[{"id":2403,"standings":[{"table":[
+				{"position":1,"team":{"id":"time_057","name":"Arsenal FC","venue":"Arsenal FC Stadium"}},
+				{"position":2,"team":{"id":"time_061","name":"Chelsea FC","venue":"Chelsea FC Stadium"}}
+			]}]}],
+			"standings":[{"table":[{"team":{"venue":"Chelsea FC Stadium"}}]}]
+		}}`, data)
+		is.Equal(1, football.Calls("CompetitionList"))
+		is.Equal(1, football.Calls("CompetitionStandings"), "the season and the competition share the cached standings")
+		is.Equal(2, football.Calls("Team"), "one call per distinct team")
+	})
+
+	t.Run("when matches are selected, should resolve teams, score and competition", func(t *testing.T) {
+		schema, football := newTestSchema(t, limits)
+		data, errs := execute(t, schema, `query Matches($status: String) {
+			competition(id: "campeonato_2021") {
+				matches(status: $status) {
+					id
+					utcDate
+					status
+					homeTeam { tla }
+					awayTeam { tla }
+					score { fullTime { home away } }
+					competition { id currentSeason { id } }
+				}
+			}
+		}`, map[string]any{"status": model.MatchFinished})
+
+		is.Empty(errs)
+		is.JSONEq(`{"competition":{"matches":[{
+			"id":1,
+			"utcDate":"2026-10-18T14:00:00Z",
+			"status":"FINISHED",
+			"homeTeam":{"tla":"ARS"},
+			"awayTeam":{"tla":"CHE"},
+			"score":{"fullTime":{"home":2,"away":1}},
+			"competition":{"id":"campeonato_2021","currentSeason":{"id":2403}}
+		}]}}`, data)
+		is.Equal(1, football.Calls("CompetitionList"), "the match competition is completed from the cached list")
+	})
+
+	t.Run("when the same fields repeat under aliases, should fetch once", func(t *testing.T) {
+		schema, football := newTestSchema(t, limits)
+		_, errs := execute(t, schema, `{
+			a: team(id: "time_057") { name }
+			b: team(id: "time_057") { venue }
+			c: team(id: "time_061") { name }
+		}`, nil)
+
+		is.Empty(errs)
+		is.Equal(2, football.Calls("Team"))
+	})
+
+	t.Run("when the team is unknown, should return null", func(t *testing.T) {
+		schema, _ := newTestSchema(t, limits)
+		data, errs := execute(t, schema, `{ team(id: "time_099") { name } }`, nil)
+
+		is.Empty(errs)
+		is.JSONEq(`{"team":null}`, data)
+	})
+
+	t.Run("when an id is malformed, should report it", func(t *testing.T) {
+		schema, _ := newTestSchema(t, limits)
+		_, errs := execute(t, schema, `{ competition(id: "2021") { name } }`, nil)
+
+		is.Equal([]string{"id must look like campeonato_XXX"}, errs)
+	})
+
+	t.Run("when a date is malformed, should report it", func(t *testing.T) {
+		schema, football := newTestSchema(t, limits)
+		_, errs := execute(t, schema, `{ matches(dateFrom: "18/10/2026") { id } }`, nil)
+
+		is.Equal([]string{"dateFrom must be a date as YYYY-MM-DD"}, errs)
+		is.Zero(football.Calls("Matches"))
+	})
+
+	t.Run("when the query is invalid, should not call the football app", func(t *testing.T) {
+		schema, football := newTestSchema(t, limits)
+		_, errs := execute(t, schema, `{ competitions { nome } }`, nil)
+
+		is.Len(errs, 1)
+		is.Contains(errs[0], `Cannot query field "nome"`)
+		is.Zero(football.Calls("CompetitionList"))
+	})
+}
+
+func TestLimits(t *testing.T) {
+	is := require.New(t)
+
+	tests := []struct {
+		name          string
+		limits        config.GraphQLConfig
+		query         string
+		expectedCodes []string
+	}{
+		{
+			name:   "when the query is within the limits, should run it",
+			limits: config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 300},
+			query:  `{ competition(id: "campeonato_2021") { standings { table { position } } } }`,
+		},
+		{
+			name:          "when the query nests too deep, should reject it",
+			limits:        config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 10000},
+			query:         `{ competition(id: "campeonato_2021") { standings { table { team { name } } } } }`,
+			expectedCodes: []string{"QUERY_TOO_DEEP"},
+		},
+		{
+			name:          "when a fragment hides the depth, should still reject it",
+			limits:        config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 10000},
+			query:         `{ competition(id: "campeonato_2021") { ...Table } } fragment Table on Competition { standings { table { team { name } } } }`,
+			expectedCodes: []string{"QUERY_TOO_DEEP"},
+		},
+		{
+			name:          "when lists multiply the football app calls, should reject it",
+			limits:        config.GraphQLConfig{MaxDepth: 10, MaxComplexity: 1000},
+			query:         `{ competitions { standings { table { team { venue } } } } }`,
+			expectedCodes: []string{"QUERY_TOO_COMPLEX"},
+		},
+		{
+			name:   "when tools introspect the schema, should not count it",
+			limits: config.GraphQLConfig{MaxDepth: 2, MaxComplexity: 5},
+			query:  `{ __schema { types { name fields { name type { ofType { ofType { name } } } } } } }`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, football := newTestSchema(t, test.limits)
+			result := schema.Execute(context.Background(), Request{Query: test.query})
+
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Extensions["code"].(string))
+			}
+			is.Equal(test.expectedCodes, codes)
+			if test.expectedCodes != nil {
+				is.Nil(result.Data)
+				is.Zero(football.Calls("CompetitionList"))
+			}
+		})
+	}
+}
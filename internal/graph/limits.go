@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// fieldCost is what resolving a field from data at hand costs.
+	fieldCost = 1
+	// fetchCost is what a field that may call the football app costs.
+	fetchCost = 10
+	// listSize is the number of items a list field is assumed to return
+	// unless listSizes knows better. It multiplies the cost of the fields
+	// selected in the list.
+	listSize = 10
+)
+
+// listSizes estimates the lists whose length is known in advance.
+var listSizes = map[string]int{
+	"Query.competitions":    15,
+	"Competition.seasons":   1,
+	"Competition.standings": 1,
+	"Season.standings":      1,
+	"Standing.table":        20,
+}
+
+// fetches lists the fields resolved through the football app.
+var fetches = map[string]bool{
+	"Query.competitions":    true,
+	"Query.competition":     true,
+	"Query.team":            true,
+	"Query.matches":         true,
+	"Competition.standings": true,
+	"Competition.scorers":   true,
+	"Competition.matches":   true,
+	"Season.standings":      true,
+	"Season.matches":        true,
+	"Team.venue":            true,
+	"Team.founded":          true,
+	"Team.clubColors":       true,
+	"Team.website":          true,
+	"Team.address":          true,
+	"Team.matches":          true,
+}
+
+// cost measures one operation before it runs. Introspection fields are
+// free, so tools may load the schema however deep their query is.
+type cost struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// checkLimits rejects the operation of document when it nests fields
+// deeper than maxDepth or its complexity exceeds maxComplexity.
+func checkLimits(schema *graphql.Schema, document *ast.Document, operationName string, maxDepth int, maxComplexity int) []gqlerrors.FormattedError {
+	c := cost{schema: schema, fragments: map[string]*ast.FragmentDefinition{}}
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	// Execution reports the missing operation.
+	if operation == nil {
+		return nil
+	}
+
+	// The schema only has queries, which validation already enforced.
+	depth, complexity := c.measure(schema.QueryType(), operation.SelectionSet, map[string]bool{})
+
+	var errs []gqlerrors.FormattedError
+	if depth > maxDepth {
+		errs = append(errs, limitError(
+			"QUERY_TOO_DEEP",
+			fmt.Sprintf("query depth %d exceeds the limit of %d", depth, maxDepth),
+		))
+	}
+	if complexity > maxComplexity {
+		errs = append(errs, limitError(
+			"QUERY_TOO_COMPLEX",
+			fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, maxComplexity),
+		))
+	}
+	return errs
+}
+
+// measure returns how deep set nests fields and what resolving it costs.
+// Fields selected twice are counted twice, which only overestimates.
+func (c cost) measure(parent graphql.Type, set *ast.SelectionSet, spread map[string]bool) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+	var depth, complexity int
+	add := func(d int, cx int) {
+		depth = max(depth, d)
+		complexity += cx
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			object, ok := parent.(*graphql.Object)
+			if !ok || strings.HasPrefix(name, "__") {
+				continue
+			}
+			definition, ok := object.Fields()[name]
+			if !ok {
+				continue
+			}
+			of, list := unwrap(definition.Type)
+			childDepth, childComplexity := c.measure(of, selection.SelectionSet, spread)
+			key := object.Name() + "." + name
+			if list {
+				size, ok := listSizes[key]
+				if !ok {
+					size = listSize
+				}
+				childComplexity *= size
+			}
+			own := fieldCost
+			if fetches[key] {
+				own = fetchCost
+			}
+			add(childDepth+1, own+childComplexity)
+		case *ast.InlineFragment:
+			add(c.measure(c.condition(selection.TypeCondition, parent), selection.SelectionSet, spread))
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := c.fragments[name]
+			if !ok || spread[name] {
+				continue
+			}
+			spread[name] = true
+			add(c.measure(c.condition(fragment.TypeCondition, parent), fragment.SelectionSet, spread))
+			delete(spread, name)
+		}
+	}
+	return depth, complexity
+}
+
+func (c cost) condition(named *ast.Named, parent graphql.Type) graphql.Type {
+	if named == nil {
+		return parent
+	}
+	if of := c.schema.Type(named.Name.Value); of != nil {
+		return of
+	}
+	return parent
+}
+
+// unwrap returns the named type under list and non-null wrappers, and
+// whether there was a list.
+func unwrap(of graphql.Type) (graphql.Type, bool) {
+	list := false
+	for {
+		switch wrapper := of.(type) {
+		case *graphql.NonNull:
+			of = wrapper.OfType
+		case *graphql.List:
+			list = true
+			of = wrapper.OfType
+		default:
+			return of, list
+		}
+	}
+}
+
+func limitError(code string, message string) gqlerrors.FormattedError {
+	err := gqlerrors.NewFormattedError(message)
+	err.Extensions = map[string]any{"code": code}
+	return err
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+const (
+	// batchWait lets sibling resolvers queue their keys before a batch runs.
+	batchWait = 2 * time.Millisecond
+	// maxFetches bounds the football app calls one batch makes at once.
+	maxFetches = 4
+)
+
+// matchesKey identifies the matches of one competition or team.
+type matchesKey struct {
+	ID     int
+	Filter model.MatchFilter
+}
+
+// loaders batch and cache the football app calls of one request, so a
+// team in every row of a table is fetched once.
+type loaders struct {
+	competitions       func() (*model.CompetitionResponse, error)
+	standings          *dataloader.Loader[int, *model.StandingsResponse]
+	scorers            *dataloader.Loader[int, *model.ScorersResponse]
+	competitionMatches *dataloader.Loader[matchesKey, *model.MatchResponse]
+	teams              *dataloader.Loader[int, *model.Team]
+	teamMatches        *dataloader.Loader[matchesKey, *model.MatchResponse]
+	matches            *dataloader.Loader[model.MatchFilter, *model.MatchResponse]
+}
+
+func newLoaders(ctx context.Context, football service.FootballAPI) *loaders {
+	return &loaders{
+		competitions: sync.OnceValues(func() (*model.CompetitionResponse, error) {
+			return football.CompetitionList(ctx)
+		}),
+		standings: newLoader(football.CompetitionStandings),
+		scorers:   newLoader(football.CompetitionScorers),
+		competitionMatches: newLoader(func(ctx context.Context, key matchesKey) (*model.MatchResponse, error) {
+			return football.CompetitionMatches(ctx, key.ID, key.Filter)
+		}),
+		teams: newLoader(football.Team),
+		teamMatches: newLoader(func(ctx context.Context, key matchesKey) (*model.MatchResponse, error) {
+			return football.TeamMatches(ctx, key.ID, key.Filter)
+		}),
+		matches: newLoader(football.Matches),
+	}
+}
+
+// newLoader batches calls to fetch. The football app has no batch
+// endpoints, so a batch fetches its distinct keys concurrently.
+func newLoader[K comparable, V any](fetch func(context.Context, K) (V, error)) *dataloader.Loader[K, V] {
+	batch := func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		results := make([]*dataloader.Result[V], len(keys))
+		limit := make(chan struct{}, maxFetches)
+		var wg sync.WaitGroup
+		for i, key := range keys {
+			wg.Go(func() {
+				limit <- struct{}{}
+				defer func() { <-limit }()
+				value, err := fetch(ctx, key)
+				results[i] = &dataloader.Result[V]{Data: value, Error: err}
+			})
+		}
+		wg.Wait()
+		return results
+	}
+	return dataloader.NewBatchedLoader(batch, dataloader.WithWait[K, V](batchWait))
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,457 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/graphql-go/graphql"
+)
+
+// season carries the competition a season belongs to, which the football
+// app leaves out of the season itself.
+type season struct {
+	model.Season
+	competitionID int
+}
+
+// newSchema describes the football domain: competitions, their seasons and
+// standings, the teams in them and their matches.
+func newSchema() (graphql.Schema, error) {
+	var competitionType, teamType, matchType *graphql.Object
+
+	matchArgs := graphql.FieldConfigArgument{
+		"status": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Match status, e.g. SCHEDULED, FINISHED or LIVE",
+		},
+		"dateFrom": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "First day, YYYY-MM-DD",
+		},
+		"dateTo": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Last day, YYYY-MM-DD",
+		},
+	}
+	standingsArgs := graphql.FieldConfigArgument{
+		"type": &graphql.ArgumentConfig{
+			Type: graphql.NewEnum(graphql.EnumConfig{
+				Name: "StandingType",
+				Values: graphql.EnumValueConfigMap{
+					"TOTAL": &graphql.EnumValueConfig{Value: "TOTAL"},
+					"HOME":  &graphql.EnumValueConfig{Value: "HOME"},
+					"AWAY":  &graphql.EnumValueConfig{Value: "AWAY"},
+				},
+			}),
+			DefaultValue: "TOTAL",
+		},
+	}
+
+	scoreSetType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ScoreSet",
+		Fields: graphql.Fields{
+			"home": &graphql.Field{Type: graphql.Int},
+			"away": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	scoreType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Score",
+		Fields: graphql.Fields{
+			"winner":   &graphql.Field{Type: graphql.String, Description: "HOME_TEAM, AWAY_TEAM or DRAW"},
+			"fullTime": &graphql.Field{Type: graphql.NewNonNull(scoreSetType)},
+			"halfTime": &graphql.Field{Type: graphql.NewNonNull(scoreSetType)},
+		},
+	})
+	playerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Player",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"nationality": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	teamType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Team",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.ID),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return model.TeamID(p.Source.(model.Team).ID), nil
+					},
+				},
+				"name":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"shortName":  &graphql.Field{Type: graphql.String},
+				"tla":        &graphql.Field{Type: graphql.String},
+				"crest":      &graphql.Field{Type: graphql.String},
+				"venue":      teamDetail(graphql.String, func(team *model.Team) any { return team.Venue }),
+				"founded":    teamDetail(graphql.Int, func(team *model.Team) any { return team.Founded }),
+				"clubColors": teamDetail(graphql.String, func(team *model.Team) any { return team.ClubColors }),
+				"website":    teamDetail(graphql.String, func(team *model.Team) any { return team.Website }),
+				"address":    teamDetail(graphql.String, func(team *model.Team) any { return team.Address }),
+				"matches": &graphql.Field{
+					Type: listOf(matchType),
+					Args: matchArgs,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						filter, err := matchFilter(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						key := matchesKey{ID: p.Source.(model.Team).ID, Filter: filter}
+						thunk := loadersFrom(p.Context).teamMatches.Load(p.Context, key)
+						return later(thunk, func(response *model.MatchResponse) any {
+							return response.Matches
+						}), nil
+					},
+				},
+			}
+		}),
+	})
+
+	tableRowType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TableRow",
+		Fields: graphql.Fields{
+			"position":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"team":           &graphql.Field{Type: graphql.NewNonNull(teamType)},
+			"playedGames":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"won":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"draw":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"lost":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"points":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"goalsFor":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"goalsAgainst":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"goalDifference": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+	standingType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Standing",
+		Fields: graphql.Fields{
+			"stage": &graphql.Field{Type: graphql.String},
+			"type":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"group": &graphql.Field{Type: graphql.String},
+			"table": &graphql.Field{Type: listOf(tableRowType)},
+		},
+	})
+	scorerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Scorer",
+		Fields: graphql.Fields{
+			"player":        &graphql.Field{Type: graphql.NewNonNull(playerType)},
+			"team":          &graphql.Field{Type: graphql.NewNonNull(teamType)},
+			"playedMatches": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"goals":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"assists":       &graphql.Field{Type: graphql.Int},
+			"penalties":     &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	standings := func(competitionID func(graphql.ResolveParams) int) *graphql.Field {
+		return &graphql.Field{
+			Type: listOf(standingType),
+			Args: standingsArgs,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				kind, _ := p.Args["type"].(string)
+				thunk := loadersFrom(p.Context).standings.Load(p.Context, competitionID(p))
+				return later(thunk, func(response *model.StandingsResponse) any {
+					result := []model.Standing{}
+					for _, standing := range response.Standings {
+						if standing.Type == kind {
+							result = append(result, standing)
+						}
+					}
+					return result
+				}), nil
+			},
+		}
+	}
+	competitionMatches := func(competitionID func(graphql.ResolveParams) int) *graphql.Field {
+		return &graphql.Field{
+			Type: listOf(matchType),
+			Args: matchArgs,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				filter, err := matchFilter(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				key := matchesKey{ID: competitionID(p), Filter: filter}
+				thunk := loadersFrom(p.Context).competitionMatches.Load(p.Context, key)
+				return later(thunk, func(response *model.MatchResponse) any {
+					return response.Matches
+				}), nil
+			},
+		}
+	}
+	fromCompetition := func(p graphql.ResolveParams) int {
+		return p.Source.(model.Competition).ID
+	}
+	fromSeason := func(p graphql.ResolveParams) int {
+		return p.Source.(season).competitionID
+	}
+
+	seasonType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Season",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(season).ID, nil
+					},
+				},
+				"startDate": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(season).StartDate, nil
+					},
+				},
+				"endDate": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(season).EndDate, nil
+					},
+				},
+				"currentMatchday": &graphql.Field{
+					Type: graphql.Int,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(season).CurrentMatchday, nil
+					},
+				},
+				"winner": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						if winner := p.Source.(season).Winner; winner != nil {
+							return *winner, nil
+						}
+						return nil, nil
+					},
+				},
+				"standings": standings(fromSeason),
+				"matches":   competitionMatches(fromSeason),
+			}
+		}),
+	})
+
+	competitionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Competition",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.ID),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return model.CompetitionID(p.Source.(model.Competition).ID), nil
+					},
+				},
+				"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"code": &graphql.Field{Type: graphql.String},
+				"currentSeason": &graphql.Field{
+					Type: seasonType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						seasons, err := competitionSeasons(p)
+						if err != nil || len(seasons) == 0 {
+							return nil, err
+						}
+						return seasons[0], nil
+					},
+				},
+				"seasons": &graphql.Field{
+					Type:        listOf(seasonType),
+					Description: "Seasons the football app serves, currently the running one",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return competitionSeasons(p)
+					},
+				},
+				"standings": standings(fromCompetition),
+				"scorers": &graphql.Field{
+					Type: listOf(scorerType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						thunk := loadersFrom(p.Context).scorers.Load(p.Context, fromCompetition(p))
+						return later(thunk, func(response *model.ScorersResponse) any {
+							return response.Scorers
+						}), nil
+					},
+				},
+				"matches": competitionMatches(fromCompetition),
+			}
+		}),
+	})
+
+	matchType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Match",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"utcDate":     &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"status":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"matchday":    &graphql.Field{Type: graphql.Int},
+			"stage":       &graphql.Field{Type: graphql.String},
+			"group":       &graphql.Field{Type: graphql.String},
+			"venue":       &graphql.Field{Type: graphql.String},
+			"lastUpdated": &graphql.Field{Type: graphql.DateTime},
+			"competition": &graphql.Field{Type: graphql.NewNonNull(competitionType)},
+			"homeTeam":    &graphql.Field{Type: graphql.NewNonNull(teamType)},
+			"awayTeam":    &graphql.Field{Type: graphql.NewNonNull(teamType)},
+			"score":       &graphql.Field{Type: graphql.NewNonNull(scoreType)},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"competitions": &graphql.Field{
+				Type: listOf(competitionType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					competitions, err := loadersFrom(p.Context).competitions()
+					if err != nil {
+						return nil, err
+					}
+					return competitions.Competitions, nil
+				},
+			},
+			"competition": &graphql.Field{
+				Type: competitionType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := model.ParseCompetitionID(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					competition, err := findCompetition(p, id)
+					if err != nil || competition == nil {
+						return nil, err
+					}
+					return *competition, nil
+				},
+			},
+			"team": &graphql.Field{
+				Type: teamType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := model.ParseTeamID(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					thunk := loadersFrom(p.Context).teams.Load(p.Context, id)
+					return func() (any, error) {
+						team, err := thunk()
+						if errors.Is(err, service.ErrFootballNotFound) {
+							return nil, nil
+						}
+						if err != nil {
+							return nil, err
+						}
+						return *team, nil
+					}, nil
+				},
+			},
+			"matches": &graphql.Field{
+				Type: listOf(matchType),
+				Args: matchArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					filter, err := matchFilter(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					thunk := loadersFrom(p.Context).matches.Load(p.Context, filter)
+					return later(thunk, func(response *model.MatchResponse) any {
+						return response.Matches
+					}), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func listOf(of graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(of)))
+}
+
+// later resolves a loader thunk after the executor has run the sibling
+// resolvers, so their keys share one batch.
+func later[V any](thunk dataloader.Thunk[V], then func(V) any) func() (any, error) {
+	return func() (any, error) {
+		value, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		return then(value), nil
+	}
+}
+
+// teamDetail resolves a field only the team endpoint returns. Teams nested
+// in tables and matches are summaries without lastUpdated, so those are
+// loaded by id.
+func teamDetail(of graphql.Output, field func(*model.Team) any) *graphql.Field {
+	return &graphql.Field{
+		Type: of,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			team := p.Source.(model.Team)
+			if team.LastUpdated != "" {
+				return field(&team), nil
+			}
+			thunk := loadersFrom(p.Context).teams.Load(p.Context, team.ID)
+			return func() (any, error) {
+				full, err := thunk()
+				if errors.Is(err, service.ErrFootballNotFound) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return field(full), nil
+			}, nil
+		},
+	}
+}
+
+// competitionSeasons lists the seasons of the source competition. Matches
+// only carry a summary of their competition, so it is looked up in the
+// competition list.
+func competitionSeasons(p graphql.ResolveParams) ([]season, error) {
+	competition := p.Source.(model.Competition)
+	if competition.CurrentSeason.ID == 0 {
+		found, err := findCompetition(p, competition.ID)
+		if err != nil || found == nil {
+			return []season{}, err
+		}
+		competition = *found
+	}
+	if competition.CurrentSeason.ID == 0 {
+		return []season{}, nil
+	}
+	return []season{{Season: competition.CurrentSeason, competitionID: competition.ID}}, nil
+}
+
+func findCompetition(p graphql.ResolveParams, id int) (*model.Competition, error) {
+	competitions, err := loadersFrom(p.Context).competitions()
+	if err != nil {
+		return nil, err
+	}
+	for _, competition := range competitions.Competitions {
+		if competition.ID == id {
+			return &competition, nil
+		}
+	}
+	return nil, nil
+}
+
+func matchFilter(args map[string]any) (model.MatchFilter, error) {
+	var filter model.MatchFilter
+	filter.Status, _ = args["status"].(string)
+	filter.DateFrom, _ = args["dateFrom"].(string)
+	filter.DateTo, _ = args["dateTo"].(string)
+	for name, date := range map[string]string{"dateFrom": filter.DateFrom, "dateTo": filter.DateTo} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			return filter, fmt.Errorf("%s must be a date as YYYY-MM-DD", name)
+		}
+	}
+	return filter, nil
+}
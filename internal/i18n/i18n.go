@@ -0,0 +1,229 @@
+// Package i18n localizes the field names and labels of the football view
+// models, which are written in Portuguese.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default is the language of the view models. Its catalogue is empty so
+// the Portuguese output stays as it always was.
+const Default = "pt-BR"
+
+// Supported lists the languages with a catalogue, the default first.
+var Supported = []string{Default, "en", "es"}
+
+//go:embed catalogue/*.json
+var files embed.FS
+
+var catalogues = load()
+
+// Catalogue maps the Portuguese keys of the view models to one language.
+type Catalogue struct {
+	Language string `json:"-"`
+	// Fields renames keys. Keys missing from it are kept.
+	Fields map[string]string `json:"fields"`
+	// Labels adds, next to the keys holding codes such as the match status,
+	// a field with the code described in the language.
+	Labels map[string]Label `json:"labels"`
+}
+
+// Label describes the codes one key can hold and the field that carries
+// the description.
+type Label struct {
+	Field  string            `json:"field"`
+	Values map[string]string `json:"values"`
+}
+
+func load() map[string]*Catalogue {
+	result := make(map[string]*Catalogue, len(Supported))
+	for _, language := range Supported {
+		content, err := files.ReadFile("catalogue/" + language + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalogue %s: %v", language, err))
+		}
+		catalogue := Catalogue{Language: language}
+		if err := json.Unmarshal(content, &catalogue); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalogue %s: %v", language, err))
+		}
+		result[language] = &catalogue
+	}
+	return result
+}
+
+// For returns the catalogue of a supported language, or the default one.
+func For(language string) *Catalogue {
+	if catalogue, ok := catalogues[language]; ok {
+		return catalogue
+	}
+	return catalogues[Default]
+}
+
+// Negotiate picks the supported language preferred by an Accept-Language
+// header. Regional variants match their language, so en-GB picks en and
+// pt-PT picks pt-BR.
+func Negotiate(acceptLanguage string) *Catalogue {
+	type preference struct {
+		tag    string
+		weight float64
+	}
+	var preferences []preference
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if tag != "" && weight > 0 {
+			preferences = append(preferences, preference{tag: strings.ToLower(tag), weight: weight})
+		}
+	}
+	slices.SortStableFunc(preferences, func(a, b preference) int {
+		switch {
+		case a.weight > b.weight:
+			return -1
+		case a.weight < b.weight:
+			return 1
+		}
+		return 0
+	})
+
+	for _, preferred := range preferences {
+		if preferred.tag == "*" {
+			return catalogues[Default]
+		}
+		base, _, _ := strings.Cut(preferred.tag, "-")
+		for _, language := range Supported {
+			supported := strings.ToLower(language)
+			supportedBase, _, _ := strings.Cut(supported, "-")
+			if preferred.tag == supported || base == supportedBase {
+				return catalogues[language]
+			}
+		}
+	}
+	return catalogues[Default]
+}
+
+// Field returns the localized name of a key.
+func (c *Catalogue) Field(name string) string {
+	if localized, ok := c.Fields[name]; ok {
+		return localized
+	}
+	return name
+}
+
+// Localize rewrites JSON encoded with the Portuguese keys, keeping the
+// order of the keys and every value.
+func (c *Catalogue) Localize(encoded []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var out bytes.Buffer
+	if err := c.value(decoder, &out); err != nil {
+		return nil, err
+	}
+	if bytes.HasSuffix(encoded, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+func (c *Catalogue) value(decoder *json.Decoder, out *bytes.Buffer) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			out.WriteByte('[')
+			for i := 0; decoder.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := c.value(decoder, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte(']')
+		} else {
+			out.WriteByte('{')
+			for i := 0; decoder.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := c.member(decoder, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte('}')
+		}
+		// Consume the closing delimiter.
+		_, err = decoder.Token()
+		return err
+	case string:
+		return writeJSON(out, token)
+	case json.Number:
+		out.WriteString(token.String())
+	case bool:
+		out.WriteString(strconv.FormatBool(token))
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// member writes one key and its value, followed by the label of the value
+// when the key holds a known code.
+func (c *Catalogue) member(decoder *json.Decoder, out *bytes.Buffer) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	key := token.(string)
+	if err := writeJSON(out, c.Field(key)); err != nil {
+		return err
+	}
+	out.WriteByte(':')
+
+	label, ok := c.Labels[key]
+	if !ok {
+		return c.value(decoder, out)
+	}
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	out.Write(raw)
+	var code string
+	if json.Unmarshal(raw, &code) == nil {
+		if description, ok := label.Values[code]; ok {
+			out.WriteByte(',')
+			if err := writeJSON(out, label.Field); err != nil {
+				return err
+			}
+			out.WriteByte(':')
+			return writeJSON(out, description)
+		}
+	}
+	return nil
+}
+
+func writeJSON(out *bytes.Buffer, value string) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	out.Write(encoded)
+	return nil
+}
@@ -0,0 +1,88 @@
+//go:build unit
+
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "when header is empty, should pick the default", expected: Default},
+		{name: "when language is supported, should pick it", acceptLanguage: "en", expected: "en"},
+		{name: "when language is regional, should pick its base", acceptLanguage: "es-AR", expected: "es"},
+		{name: "when Portuguese is not Brazilian, should pick pt-BR", acceptLanguage: "pt-PT", expected: Default},
+		{name: "when weights are given, should prefer the heaviest", acceptLanguage: "en;q=0.4, es;q=0.8", expected: "es"},
+		{name: "when the first language is unsupported, should try the next", acceptLanguage: "fr-FR, fr;q=0.9, en;q=0.8", expected: "en"},
+		{name: "when a language is refused, should skip it", acceptLanguage: "en;q=0, es;q=0.1", expected: "es"},
+		{name: "when any language is accepted, should pick the default", acceptLanguage: "*", expected: Default},
+		{name: "when nothing is supported, should pick the default", acceptLanguage: "de, ja", expected: Default},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			is.Equal(test.expected, Negotiate(test.acceptLanguage).Language)
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	encoded := []byte(`{"campeonatos":[{"id":"campeonato_2021","nome":"Premier League","proximas_partidas":[{"id":1,"status":"FINISHED","mandante":"Arsenal FC","gols_mandante":2,"gols_visitante":null}]}],"times":[]}` + "\n")
+
+	tests := []struct {
+		name     string
+		language string
+		expected string
+	}{
+		{
+			name:     "when language is the default, should keep the output",
+			language: Default,
+			expected: string(encoded),
+		},
+		{
+			name:     "when language is English, should rename keys and describe the status",
+			language: "en",
+			expected: `{"competitions":[{"id":"campeonato_2021","name":"Premier League","upcoming_matches":[{"id":1,"status":"FINISHED","status_label":"Finished","home_team":"Arsenal FC","home_goals":2,"away_goals":null}]}],"teams":[]}` + "\n",
+		},
+		{
+			name:     "when language is Spanish, should rename keys and describe the status",
+			language: "es",
+			expected: `{"competiciones":[{"id":"campeonato_2021","nombre":"Premier League","proximos_partidos":[{"id":1,"estado":"FINISHED","estado_descripcion":"Finalizado","local":"Arsenal FC","goles_local":2,"goles_visitante":null}]}],"equipos":[]}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			localized, err := For(test.language).Localize(encoded)
+			is.Nil(err)
+			is.Equal(test.expected, string(localized))
+		})
+	}
+
+	t.Run("when the status is unknown, should keep it without a label", func(t *testing.T) {
+		t.Parallel()
+		localized, err := For("en").Localize([]byte(`{"status":"ABANDONED"}`))
+		is.Nil(err)
+		is.Equal(`{"status":"ABANDONED"}`, string(localized))
+	})
+
+	t.Run("when the input is not JSON, should fail", func(t *testing.T) {
+		t.Parallel()
+		_, err := For("en").Localize([]byte(`{"nome":`))
+		is.NotNil(err)
+	})
+}
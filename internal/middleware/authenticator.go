@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
@@ -11,8 +15,28 @@ import (
 const (
 	BearerPrefix = "Bearer"
 	MethodHS256  = "HS256"
+	APIKeyHeader = "X-API-Key"
+	// TokenQueryParam carries the access token of clients that cannot set
+	// headers, like the browser EventSource and WebSocket APIs.
+	TokenQueryParam = "token"
 )
 
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, raw string) (*model.APIKey, error)
+}
+
+// CalendarTokenAuthenticator resolves the tokens calendar apps send in the
+// query string.
+type CalendarTokenAuthenticator interface {
+	AuthenticateCalendarToken(ctx context.Context, raw string) (*model.CalendarToken, error)
+}
+
+// SessionValidator rejects tokens revoked after they were issued, e.g. by
+// a password reset.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, user string, issuedAt time.Time) error
+}
+
 func VerifyToken(tokenValue string, secretKey string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenValue, func(token *jwt.Token) (any, error) {
 		switch token.Method.Alg() {
@@ -46,28 +70,202 @@ func RemoveBearerPrefix(authHeader string) (string, error) {
 	return tokenString, nil
 }
 
-func JWTMiddleware(secretKey string) echo.MiddlewareFunc {
+// JWTMiddleware authenticates the Bearer token. sessions may be nil to skip
+// the revocation check.
+func JWTMiddleware(secretKey string, sessions SessionValidator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if err := authenticateJWT(c, secretKey, sessions); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
 
-			authHeader := c.Request().Header.Get("Authorization")
-			token, err := RemoveBearerPrefix(authHeader)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, err)
+// JWTOrQueryTokenMiddleware is JWTMiddleware that also accepts the token in
+// the token query parameter when the Authorization header is absent.
+func JWTOrQueryTokenMiddleware(secretKey string, sessions SessionValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header
+			if token := c.QueryParam(TokenQueryParam); token != "" && header.Get("Authorization") == "" {
+				header.Set("Authorization", BearerPrefix+" "+token)
+			}
+			if err := authenticateJWT(c, secretKey, sessions); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// APIKeyOrJWTMiddleware accepts an X-API-Key header and falls back to the
+// Bearer JWT when it is absent. An API key only grants the scopes its owner
+// still holds.
+func APIKeyOrJWTMiddleware(secretKey string, apiKeys APIKeyAuthenticator, sessions SessionValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			raw := c.Request().Header.Get(APIKeyHeader)
+			if raw == "" {
+				if err := authenticateJWT(c, secretKey, sessions); err != nil {
+					return err
+				}
+				return next(c)
 			}
 
-			claims, err := VerifyToken(token, secretKey)
+			key, err := apiKeys.AuthenticateAPIKey(c.Request().Context(), raw)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "first verify: invalid token")
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid api key")
 			}
 
-			user, ok := claims["username"].(string)
-			if !ok {
-				return echo.NewHTTPError(http.StatusUnauthorized, "final verify: invalid token")
+			SetUser(c, key.Credential.User)
+			c.Set("scopes", key.Scopes.Intersect(key.Credential.AllowedScopes()))
+			c.Set("role", key.Credential.Role)
+			SetOrganization(c, key.Credential.OrganizationID())
+			return next(c)
+		}
+	}
+}
+
+// CalendarTokenMiddleware authenticates calendar subscriptions by the
+// calendar token in the token query parameter. Without it, requests are
+// authenticated as in APIKeyOrJWTMiddleware. A calendar token only grants
+// competitions:read, and only if its owner still holds that scope.
+func CalendarTokenMiddleware(
+	secretKey string,
+	apiKeys APIKeyAuthenticator,
+	sessions SessionValidator,
+	calendarTokens CalendarTokenAuthenticator,
+) echo.MiddlewareFunc {
+	fallback := APIKeyOrJWTMiddleware(secretKey, apiKeys, sessions)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authenticated := fallback(next)
+		return func(c echo.Context) error {
+			raw := c.QueryParam(TokenQueryParam)
+			if raw == "" {
+				return authenticated(c)
+			}
+
+			token, err := calendarTokens.AuthenticateCalendarToken(c.Request().Context(), raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid calendar token")
 			}
 
-			c.Set("user", user)
+			scopes := model.Scopes{}
+			if token.Credential.AllowedScopes().Has(model.ScopeCompetitionsRead) {
+				scopes = model.Scopes{model.ScopeCompetitionsRead}
+			}
+			SetUser(c, token.Credential.User)
+			c.Set("scopes", scopes)
+			c.Set("role", token.Credential.Role)
+			SetOrganization(c, token.Credential.OrganizationID())
 			return next(c)
 		}
 	}
 }
+
+// Claims describe the caller of a verified access token.
+type Claims struct {
+	User   string
+	Scopes model.Scopes
+	Role   string
+	OrgID  int
+	// ExpiresAt and AuthTime are zero when the token lacks them.
+	ExpiresAt time.Time
+	AuthTime  time.Time
+}
+
+// ParseAccessToken verifies the Bearer authorization value of an HTTP
+// request or gRPC call. Challenge and other purpose tokens are rejected, and
+// so are tokens revoked after they were issued unless sessions is nil. Every
+// error is a JWTError whose message can be sent to the client.
+func ParseAccessToken(ctx context.Context, authorization string, secretKey string, sessions SessionValidator) (Claims, error) {
+	token, err := RemoveBearerPrefix(authorization)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	claims, err := VerifyToken(token, secretKey)
+	if err != nil {
+		return Claims{}, NewJWTErr(nil, "first verify: invalid token")
+	}
+
+	user, ok := claims["username"].(string)
+	if !ok {
+		return Claims{}, NewJWTErr(nil, "final verify: invalid token")
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != "" {
+		return Claims{}, NewJWTErr(nil, "final verify: not an access token")
+	}
+
+	if sessions != nil {
+		issuedAt, err := claims.GetIssuedAt()
+		if err != nil || issuedAt == nil {
+			return Claims{}, NewJWTErr(nil, "final verify: missing issued at")
+		}
+		if err := sessions.ValidateSession(ctx, user, issuedAt.Time); err != nil {
+			return Claims{}, NewJWTErr(nil, "session revoked")
+		}
+	}
+
+	scope, _ := claims["scope"].(string)
+	role, _ := claims["role"].(string)
+	orgID, _ := claims["org_id"].(float64)
+	parsed := Claims{
+		User:   user,
+		Scopes: model.ParseScopes(scope),
+		Role:   role,
+		OrgID:  int(orgID),
+	}
+	if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+		parsed.ExpiresAt = expiresAt.Time
+	}
+	parsed.AuthTime, _ = AuthTime(claims)
+	return parsed, nil
+}
+
+func authenticateJWT(c echo.Context, secretKey string, sessions SessionValidator) error {
+	authorization := c.Request().Header.Get("Authorization")
+	claims, err := ParseAccessToken(c.Request().Context(), authorization, secretKey, sessions)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+
+	SetUser(c, claims.User)
+	c.Set("scopes", claims.Scopes)
+	c.Set("role", claims.Role)
+	if !claims.ExpiresAt.IsZero() {
+		c.Set("expires_at", claims.ExpiresAt)
+	}
+	if !claims.AuthTime.IsZero() {
+		c.Set("auth_time", claims.AuthTime)
+	}
+	SetOrganization(c, claims.OrgID)
+	return nil
+}
+
+// AuthTime returns when the session of the token started. Tokens issued
+// before the auth_time claim existed fall back to their issue time.
+func AuthTime(claims jwt.MapClaims) (time.Time, bool) {
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		return time.Unix(int64(authTime), 0), true
+	}
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return time.Time{}, false
+	}
+	return issuedAt.Time, true
+}
+
+// SetOrganization scopes the request to the caller's organization, so
+// repositories only see its records. Zero leaves platform callers unscoped.
+func SetOrganization(c echo.Context, orgID int) {
+	if orgID == 0 {
+		return
+	}
+	c.Set("org_id", orgID)
+	ctx := tenant.WithOrganization(c.Request().Context(), orgID)
+	c.SetRequest(c.Request().WithContext(ctx))
+}
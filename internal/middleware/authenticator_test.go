@@ -3,8 +3,10 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +14,7 @@ import (
 	"time"
 
 	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
@@ -72,11 +75,10 @@ func TestVerifyToken(t *testing.T) {
 	t.Parallel()
 	is := require.New(t)
 
-	aReq := model.AuthRequest{
-		User:     "test",
-		Password: "abcd",
+	credential := model.Credential{
+		User: "test",
 	}
-	tokenString, err := aReq.GenerateToken("xpto")
+	tokenString, err := credential.GenerateToken("xpto", model.KnownScopes)
 	fmt.Println(tokenString)
 	is.Nil(err)
 
@@ -141,7 +143,9 @@ func TestVerifyToken(t *testing.T) {
 			t.Parallel()
 			claims, err := VerifyToken(test.token, test.secretKey)
 			if test.hasErr {
-				is.Equal(test.ExpectedErr.Error(), err.Error())
+				// VerifyToken wraps the jwt parse error, whose text differs
+				// per case, so only the message shown to clients is stable.
+				is.Equal(test.ExpectedErr.(*JWTError).Message(), err.(*JWTError).Message())
 				return
 			}
 			is.Nil(err)
@@ -150,7 +154,9 @@ func TestVerifyToken(t *testing.T) {
 		})
 	}
 
-	t.Run("when claims not map claims, should return error", func(t *testing.T) {
+	// jwt.Parse always decodes into MapClaims, so custom claims come back as
+	// a map instead of failing.
+	t.Run("when token has custom claims, should return them as MapClaims", func(t *testing.T) {
 		t.Parallel()
 		type CustomClaims struct {
 			UserID int `json:"user_id"`
@@ -166,8 +172,9 @@ func TestVerifyToken(t *testing.T) {
 
 		customToken := jwt.NewWithClaims(jwt.SigningMethodHS256, customClaims)
 		customTokenString, _ := customToken.SignedString([]byte("xpto"))
-		_, err := VerifyToken(customTokenString, "xpto")
-		is.Equal(NewJWTErr(nil, "invalid token: claims are not MapClaims"), err)
+		claims, err := VerifyToken(customTokenString, "xpto")
+		is.Nil(err)
+		is.Equal(float64(789), claims["user_id"])
 	})
 }
 
@@ -176,7 +183,9 @@ func TestJWTMiddleware(t *testing.T) {
 	t.Run("when token and secret key are correct, should apply user into context", func(t *testing.T) {
 		secretKey := "test_secret"
 		var claims model.AuthClaims
+		// The middleware sets the user from the username claim.
 		claims = model.AuthClaims{
+			Username:         "adm",
 			RegisteredClaims: jwt.RegisteredClaims{},
 		}
 
@@ -196,7 +205,7 @@ func TestJWTMiddleware(t *testing.T) {
 
 		req.Header.Set("Authorization", "Bearer "+tokenStr)
 
-		middlewareFunc := JWTMiddleware(secretKey)
+		middlewareFunc := JWTMiddleware(secretKey, nil)
 		err = middlewareFunc(next)(c)
 
 		expectedUser := c.Get("user")
@@ -206,6 +215,31 @@ func TestJWTMiddleware(t *testing.T) {
 		is.Equal(username, expectedUser.(string))
 	})
 
+	t.Run("when token carries org_id, should scope the request to the organization", func(t *testing.T) {
+		secretKey := "test_secret"
+		orgID := 7
+		credential := &model.Credential{User: "fan", Role: model.RoleAdmin, OrgID: &orgID}
+		tokenStr, err := credential.GenerateToken(secretKey, model.KnownScopes)
+		is.Nil(err)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+tokenStr)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err = JWTMiddleware(secretKey, nil)(func(c echo.Context) error {
+			scoped, ok := tenant.Organization(c.Request().Context())
+			is.True(ok)
+			is.Equal(orgID, scoped)
+			is.Equal(orgID, c.Get("org_id"))
+			return RequirePlatform()(func(c echo.Context) error { return nil })(c)
+		})(c)
+
+		var httpErr *echo.HTTPError
+		is.ErrorAs(err, &httpErr)
+		is.Equal(http.StatusForbidden, httpErr.Code)
+	})
+
 	t.Run("when token doesn't have username claim, should return error", func(t *testing.T) {
 		type testModel struct {
 			ID int
@@ -232,13 +266,360 @@ func TestJWTMiddleware(t *testing.T) {
 
 		req.Header.Set("Authorization", "Bearer "+tokenStr)
 
-		middlewareFunc := JWTMiddleware(secretKey)
+		middlewareFunc := JWTMiddleware(secretKey, nil)
 		err = middlewareFunc(next)(c)
 
 		is.NotNil(err)
 		is.Equal("code=401, message=final verify: invalid token", err.Error())
 	})
 
+	t.Run("when token is a two-factor challenge, should return error", func(t *testing.T) {
+		secretKey := "test_secret"
+		credential := model.Credential{User: "adm"}
+		tokenStr, err := credential.GenerateChallengeToken(secretKey, model.KnownScopes, time.Minute)
+		is.Nil(err)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+tokenStr)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err = JWTMiddleware(secretKey, nil)(func(c echo.Context) error { return nil })(c)
+		is.NotNil(err)
+		is.Equal("code=401, message=final verify: not an access token", err.Error())
+	})
+
+	t.Run("when session was revoked after the token was issued, should return error", func(t *testing.T) {
+		secretKey := "test_secret"
+		credential := model.Credential{User: "adm"}
+		tokenStr, err := credential.GenerateToken(secretKey, model.KnownScopes)
+		is.Nil(err)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+tokenStr)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		sessions := fakeSessions{"adm": time.Now().Add(time.Hour)}
+		err = JWTMiddleware(secretKey, sessions)(func(c echo.Context) error { return nil })(c)
+		is.NotNil(err)
+		is.Equal("code=401, message=session revoked", err.Error())
+	})
+
 	// TODO: break in prefix
 	// TODO: break in verify
 }
+
+func TestParseAccessToken(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	secretKey := "test_secret"
+	ctx := context.Background()
+
+	orgID := 7
+	credential := model.Credential{User: "adm", Role: model.RoleAdmin, OrgID: &orgID}
+	access, err := credential.GenerateToken(secretKey, model.KnownScopes)
+	is.Nil(err)
+	challenge, err := credential.GenerateChallengeToken(secretKey, model.KnownScopes, time.Minute)
+	is.Nil(err)
+
+	t.Run("when token is an access token, should return its claims", func(t *testing.T) {
+		claims, err := ParseAccessToken(ctx, "Bearer "+access, secretKey, fakeSessions{})
+		is.Nil(err)
+		is.Equal("adm", claims.User)
+		is.Equal(model.KnownScopes, claims.Scopes)
+		is.Equal(model.RoleAdmin, claims.Role)
+		is.Equal(orgID, claims.OrgID)
+		is.False(claims.ExpiresAt.IsZero())
+		is.False(claims.AuthTime.IsZero())
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		sessions      SessionValidator
+		expectedMsg   string
+	}{
+		{
+			name:          "when the bearer prefix is missing, should return error",
+			authorization: access,
+			expectedMsg:   "invalid authorization type: failed to split bearer from auth header",
+		},
+		{
+			name:          "when token is signed with another key, should return error",
+			authorization: "Bearer " + access + "x",
+			expectedMsg:   "first verify: invalid token",
+		},
+		{
+			name:          "when token is a two-factor challenge, should return error",
+			authorization: "Bearer " + challenge,
+			expectedMsg:   "final verify: not an access token",
+		},
+		{
+			name:          "when session was revoked, should return error",
+			authorization: "Bearer " + access,
+			sessions:      fakeSessions{"adm": time.Now().Add(time.Hour)},
+			expectedMsg:   "session revoked",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseAccessToken(ctx, test.authorization, secretKey, test.sessions)
+			var jwtErr *JWTError
+			is.ErrorAs(err, &jwtErr)
+			is.Equal(test.expectedMsg, err.Error())
+		})
+	}
+}
+
+// fakeSessions maps users to the instant their sessions were revoked.
+type fakeSessions map[string]time.Time
+
+func (f fakeSessions) ValidateSession(_ context.Context, user string, issuedAt time.Time) error {
+	if revokedAt, ok := f[user]; ok && issuedAt.Before(revokedAt) {
+		return errors.New("session revoked")
+	}
+	return nil
+}
+
+type fakeAPIKeys map[string]*model.APIKey
+
+func (f fakeAPIKeys) AuthenticateAPIKey(_ context.Context, raw string) (*model.APIKey, error) {
+	key, ok := f[raw]
+	if !ok {
+		return nil, errors.New("api key not found")
+	}
+	return key, nil
+}
+
+func TestAPIKeyOrJWTMiddleware(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	secretKey := "test_secret"
+
+	credential := model.Credential{User: "jwt-user"}
+	tokenStr, err := credential.GenerateToken(secretKey, model.KnownScopes)
+	is.Nil(err)
+
+	keys := fakeAPIKeys{
+		"fut_valid": {
+			Credential: model.Credential{User: "batch", Scopes: model.Scopes{model.ScopeCompetitionsRead}},
+			Scopes:     model.Scopes{model.ScopeCompetitionsRead},
+		},
+		"fut_narrowed": {
+			Credential: model.Credential{User: "batch", Scopes: model.Scopes{model.ScopeCompetitionsRead}},
+			Scopes:     model.Scopes{model.ScopeCompetitionsRead, model.ScopeUsersManage},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		hasErr         bool
+		expectedUser   string
+		expectedScopes model.Scopes
+	}{
+		{
+			name:           "when api key is valid, should apply key owner into context",
+			headers:        map[string]string{APIKeyHeader: "fut_valid"},
+			expectedUser:   "batch",
+			expectedScopes: model.Scopes{model.ScopeCompetitionsRead},
+		},
+		{
+			name:           "when the owner lost a scope of the key, should not grant it",
+			headers:        map[string]string{APIKeyHeader: "fut_narrowed"},
+			expectedUser:   "batch",
+			expectedScopes: model.Scopes{model.ScopeCompetitionsRead},
+		},
+		{
+			name:    "when api key is unknown, should return error",
+			headers: map[string]string{APIKeyHeader: "fut_unknown"},
+			hasErr:  true,
+		},
+		{
+			name:           "when only bearer token is sent, should apply token user into context",
+			headers:        map[string]string{"Authorization": "Bearer " + tokenStr},
+			expectedUser:   "jwt-user",
+			expectedScopes: model.KnownScopes,
+		},
+		{
+			name:   "when no credentials are sent, should return error",
+			hasErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := APIKeyOrJWTMiddleware(secretKey, keys, nil)(func(c echo.Context) error { return nil })(c)
+			if test.hasErr {
+				var httpErr *echo.HTTPError
+				is.ErrorAs(err, &httpErr)
+				is.Equal(http.StatusUnauthorized, httpErr.Code)
+				return
+			}
+			is.Nil(err)
+			is.Equal(test.expectedUser, c.Get("user"))
+			is.Equal(test.expectedScopes, c.Get("scopes"))
+		})
+	}
+}
+
+func TestJWTOrQueryTokenMiddleware(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	secretKey := "test_secret"
+
+	credential := model.Credential{User: "viewer"}
+	tokenStr, err := credential.GenerateToken(secretKey, model.KnownScopes)
+	is.Nil(err)
+
+	tests := []struct {
+		name         string
+		target       string
+		headers      map[string]string
+		hasErr       bool
+		expectedUser string
+	}{
+		{
+			name:         "when token is sent in the query, should apply token user into context",
+			target:       "/?token=" + tokenStr,
+			expectedUser: "viewer",
+		},
+		{
+			name:         "when bearer token is sent, should apply token user into context",
+			target:       "/",
+			headers:      map[string]string{"Authorization": "Bearer " + tokenStr},
+			expectedUser: "viewer",
+		},
+		{
+			name:    "when header is invalid, should not fall back to the query",
+			target:  "/?token=" + tokenStr,
+			headers: map[string]string{"Authorization": "Bearer xpto"},
+			hasErr:  true,
+		},
+		{
+			name:   "when query token is invalid, should return error",
+			target: "/?token=xpto",
+			hasErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := JWTOrQueryTokenMiddleware(secretKey, nil)(func(c echo.Context) error { return nil })(c)
+			if test.hasErr {
+				var httpErr *echo.HTTPError
+				is.ErrorAs(err, &httpErr)
+				is.Equal(http.StatusUnauthorized, httpErr.Code)
+				return
+			}
+			is.Nil(err)
+			is.Equal(test.expectedUser, c.Get("user"))
+		})
+	}
+}
+
+type fakeCalendarTokens map[string]*model.CalendarToken
+
+func (f fakeCalendarTokens) AuthenticateCalendarToken(_ context.Context, raw string) (*model.CalendarToken, error) {
+	token, ok := f[raw]
+	if !ok {
+		return nil, errors.New("calendar token not found")
+	}
+	return token, nil
+}
+
+func TestCalendarTokenMiddleware(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	secretKey := "test_secret"
+
+	credential := model.Credential{User: "jwt-user"}
+	tokenStr, err := credential.GenerateToken(secretKey, model.KnownScopes)
+	is.Nil(err)
+
+	tokens := fakeCalendarTokens{
+		"cal_fan": {Credential: model.Credential{User: "fan", Scopes: model.KnownScopes}},
+		"cal_ops": {Credential: model.Credential{User: "ops", Scopes: model.Scopes{model.ScopeAuditRead}}},
+	}
+
+	tests := []struct {
+		name           string
+		target         string
+		headers        map[string]string
+		hasErr         bool
+		expectedUser   string
+		expectedScopes model.Scopes
+	}{
+		{
+			name:           "when calendar token is valid, should only grant competitions read",
+			target:         "/?token=cal_fan",
+			expectedUser:   "fan",
+			expectedScopes: model.Scopes{model.ScopeCompetitionsRead},
+		},
+		{
+			name:           "when owner cannot read competitions, should grant no scope",
+			target:         "/?token=cal_ops",
+			expectedUser:   "ops",
+			expectedScopes: model.Scopes{},
+		},
+		{
+			name:   "when calendar token is unknown, should return error",
+			target: "/?token=cal_unknown",
+			hasErr: true,
+		},
+		{
+			name:   "when an access token is sent in the query, should return error",
+			target: "/?token=" + tokenStr,
+			hasErr: true,
+		},
+		{
+			name:           "when bearer token is sent, should apply token user into context",
+			target:         "/",
+			headers:        map[string]string{"Authorization": "Bearer " + tokenStr},
+			expectedUser:   "jwt-user",
+			expectedScopes: model.KnownScopes,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := CalendarTokenMiddleware(secretKey, fakeAPIKeys{}, nil, tokens)(func(c echo.Context) error { return nil })(c)
+			if test.hasErr {
+				var httpErr *echo.HTTPError
+				is.ErrorAs(err, &httpErr)
+				is.Equal(http.StatusUnauthorized, httpErr.Code)
+				return
+			}
+			is.Nil(err)
+			is.Equal(test.expectedUser, c.Get("user"))
+			is.Equal(test.expectedScopes, c.Get("scopes"))
+		})
+	}
+}
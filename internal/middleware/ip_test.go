@@ -0,0 +1,52 @@
+//go:build unit
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPExtractor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		expectedIP     string
+	}{
+		{
+			name:       "when no proxy is trusted, should ignore X-Forwarded-For",
+			remoteAddr: "10.0.0.1:1234",
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:           "when the peer is a trusted proxy, should use X-Forwarded-For",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "when the peer is not a trusted proxy, should use the peer address",
+			trustedProxies: []string{"192.168.0.0/16"},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedIP:     "10.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			is := require.New(t)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+			is.Equal(test.expectedIP, IPExtractor(test.trustedProxies)(req))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/fut-app/pkg/logger"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+const RequestIDHeader = echo.HeaderXRequestID
+
+// RequestID reuses the incoming X-Request-Id (or generates one) and binds it
+// to the request context so log records can be correlated.
+func RequestID() echo.MiddlewareFunc {
+	return echomiddleware.RequestIDWithConfig(echomiddleware.RequestIDConfig{
+		TargetHeader: RequestIDHeader,
+		RequestIDHandler: func(c echo.Context, id string) {
+			ctx := logger.AppendCtx(c.Request().Context(), slog.String("request_id", id))
+			c.SetRequest(c.Request().WithContext(ctx))
+		},
+	})
+}
+
+// RequestLogger writes one structured record per handled request.
+func RequestLogger(log *slog.Logger) echo.MiddlewareFunc {
+	return echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURIPath:   true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogUserAgent: true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("path", v.URIPath),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+				slog.String("user_agent", v.UserAgent),
+			}
+
+			level := slog.LevelInfo
+			if v.Error != nil {
+				level = slog.LevelError
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+			log.LogAttrs(c.Request().Context(), level, "request handled", attrs...)
+			return nil
+		},
+	})
+}
+
+// SetUser stores the authenticated user in the echo context and in the
+// request logging context.
+func SetUser(c echo.Context, user string) {
+	c.Set("user", user)
+	ctx := logger.AppendCtx(c.Request().Context(), slog.String("user", user))
+	c.SetRequest(c.Request().WithContext(ctx))
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// RateLimitStore counts requests per key in fixed windows. The middleware
+// weighs the previous window to approximate a sliding one.
+type RateLimitStore interface {
+	// Hit records one request for key in the window starting at windowStart
+	// and returns the counts of that window and of the one before it.
+	Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (current int, previous int, err error)
+}
+
+type RateLimitPolicy struct {
+	Enabled bool
+	Window  time.Duration
+	Default int
+	Roles   map[string]int
+	// ByIP keys every request by client IP, even authenticated ones.
+	ByIP bool
+}
+
+func NewRateLimitPolicy(cfg config.RateLimitConfig) RateLimitPolicy {
+	policy := RateLimitPolicy{
+		Enabled: cfg.Enabled,
+		Window:  cfg.Window,
+		Default: cfg.Default,
+		Roles:   make(map[string]int, len(cfg.Roles)),
+	}
+	for _, role := range cfg.Roles {
+		name, limit, _ := strings.Cut(role, ":")
+		policy.Roles[name], _ = strconv.Atoi(limit)
+	}
+	return policy
+}
+
+// NewAuthRateLimitPolicy limits the /auth endpoints per client IP, so it can
+// run before authentication and throttle password and code guessing.
+func NewAuthRateLimitPolicy(cfg config.RateLimitConfig) RateLimitPolicy {
+	return RateLimitPolicy{
+		Enabled: cfg.Enabled,
+		Window:  cfg.Window,
+		Default: cfg.Auth,
+		ByIP:    true,
+	}
+}
+
+// Subject keys a request by user, falling back to the client IP, and
+// returns the limit that applies to it.
+func (p RateLimitPolicy) Subject(user string, role string, ip string) (string, int) {
+	if p.ByIP {
+		return "auth-ip:" + ip, p.Default
+	}
+	if user == "" {
+		return "ip:" + ip, p.Default
+	}
+	if limit, ok := p.Roles[role]; ok {
+		return "user:" + user, limit
+	}
+	return "user:" + user, p.Default
+}
+
+// RateLimitQuota is what is left of a key's limit after a request.
+type RateLimitQuota struct {
+	Limit     int
+	Remaining int
+	// Reset is the number of seconds until the current window ends.
+	Reset    int
+	Exceeded bool
+}
+
+// Take counts one request for key against limit.
+func (p RateLimitPolicy) Take(ctx context.Context, store RateLimitStore, key string, limit int) (RateLimitQuota, error) {
+	now := time.Now()
+	windowStart := now.Truncate(p.Window)
+	current, previous, err := store.Hit(ctx, key, windowStart, p.Window)
+	if err != nil {
+		return RateLimitQuota{}, err
+	}
+
+	elapsed := now.Sub(windowStart)
+	weight := 1 - float64(elapsed)/float64(p.Window)
+	used := int(math.Floor(float64(previous)*weight)) + current
+	return RateLimitQuota{
+		Limit:     limit,
+		Remaining: max(limit-used, 0),
+		Reset:     int(math.Ceil((p.Window - elapsed).Seconds())),
+		Exceeded:  used > limit,
+	}, nil
+}
+
+// RateLimit rejects requests over the caller's limit with 429 and reports the
+// quota in RateLimit-* headers. Unless the policy is keyed by IP, it must run
+// after an authentication middleware to key requests by user. Store failures
+// let requests through.
+func RateLimit(policy RateLimitPolicy, store RateLimitStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !policy.Enabled {
+			return next
+		}
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			user, _ := c.Get("user").(string)
+			role, _ := c.Get("role").(string)
+			key, limit := policy.Subject(user, role, c.RealIP())
+
+			quota, err := policy.Take(ctx, store, key, limit)
+			if err != nil {
+				slog.WarnContext(ctx, "rate limit store unavailable", "error", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(RateLimitLimitHeader, strconv.Itoa(quota.Limit))
+			header.Set(RateLimitRemainingHeader, strconv.Itoa(quota.Remaining))
+			header.Set(RateLimitResetHeader, strconv.Itoa(quota.Reset))
+			header.Set(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", quota.Limit, int(policy.Window.Seconds())))
+
+			if quota.Exceeded {
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(quota.Reset))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+			}
+			return next(c)
+		}
+	}
+}
+
+type rateWindow struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+// MemoryRateLimitStore keeps counters in process. Limits are per instance,
+// so use the database store when running several replicas.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{windows: make(map[string]*rateWindow)}
+}
+
+func (s *MemoryRateLimitStore) Hit(_ context.Context, key string, windowStart time.Time, window time.Duration) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !windowStart.Equal(s.lastSweep) {
+		s.sweep(windowStart.Add(-window))
+		s.lastSweep = windowStart
+	}
+
+	counter, ok := s.windows[key]
+	switch {
+	case !ok:
+		counter = &rateWindow{start: windowStart}
+		s.windows[key] = counter
+	case counter.start.Equal(windowStart):
+	case counter.start.Equal(windowStart.Add(-window)):
+		counter.previous, counter.current = counter.current, 0
+		counter.start = windowStart
+	default:
+		counter.previous, counter.current = 0, 0
+		counter.start = windowStart
+	}
+	counter.current++
+	return counter.current, counter.previous, nil
+}
+
+// sweep drops counters that can no longer weigh on any request.
+func (s *MemoryRateLimitStore) sweep(before time.Time) {
+	for key, counter := range s.windows {
+		if counter.start.Before(before) {
+			delete(s.windows, key)
+		}
+	}
+}
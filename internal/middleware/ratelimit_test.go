@@ -0,0 +1,136 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Hit(context.Context, string, time.Time, time.Duration) (int, int, error) {
+	return 0, 0, errors.New("database is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	policy := NewRateLimitPolicy(config.RateLimitConfig{
+		Enabled: true,
+		Window:  time.Hour,
+		Default: 1,
+		Roles:   []string{"user:2"},
+	})
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+	// do runs one request as user with role, or anonymously from ip when
+	// user is empty.
+	do := func(store RateLimitStore, user string, role string, ip string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/campeonatos/", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if user != "" {
+			c.Set("user", user)
+			c.Set("role", role)
+		}
+		return rec, RateLimit(policy, store)(ok)(c)
+	}
+
+	t.Run("when under the role limit, should report the remaining quota", func(t *testing.T) {
+		t.Parallel()
+		rec, err := do(NewMemoryRateLimitStore(), "fan", model.RoleUser, "10.0.0.1")
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+		is.Equal("2", rec.Header().Get(RateLimitLimitHeader))
+		is.Equal("1", rec.Header().Get(RateLimitRemainingHeader))
+		is.Equal("2;w=3600", rec.Header().Get(RateLimitPolicyHeader))
+		is.NotEmpty(rec.Header().Get(RateLimitResetHeader))
+	})
+
+	t.Run("when over the role limit, should return too many requests", func(t *testing.T) {
+		t.Parallel()
+		store := NewMemoryRateLimitStore()
+		for range 2 {
+			_, err := do(store, "fan", model.RoleUser, "10.0.0.1")
+			is.Nil(err)
+		}
+		rec, err := do(store, "fan", model.RoleUser, "10.0.0.1")
+		is.Equal(http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+		is.Equal("0", rec.Header().Get(RateLimitRemainingHeader))
+		is.NotEmpty(rec.Header().Get(echo.HeaderRetryAfter))
+
+		_, err = do(store, "other", model.RoleUser, "10.0.0.1")
+		is.Nil(err, "users sharing an IP have their own quota")
+	})
+
+	t.Run("when anonymous, should use the default limit per IP", func(t *testing.T) {
+		t.Parallel()
+		store := NewMemoryRateLimitStore()
+		_, err := do(store, "", "", "10.0.0.1")
+		is.Nil(err)
+		_, err = do(store, "", "", "10.0.0.1")
+		is.Equal(http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+		_, err = do(store, "", "", "10.0.0.2")
+		is.Nil(err)
+	})
+
+	t.Run("when keyed by IP, should share the quota of authenticated and anonymous clients", func(t *testing.T) {
+		t.Parallel()
+		store := NewMemoryRateLimitStore()
+		policy := NewAuthRateLimitPolicy(config.RateLimitConfig{Enabled: true, Window: time.Hour, Auth: 1})
+		do := func(user string) error {
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			if user != "" {
+				c.Set("user", user)
+				c.Set("role", model.RoleUser)
+			}
+			return RateLimit(policy, store)(ok)(c)
+		}
+		is.Nil(do(""))
+		err := do("fan")
+		is.Equal(http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("when the store fails, should let the request through", func(t *testing.T) {
+		t.Parallel()
+		rec, err := do(failingRateLimitStore{}, "fan", model.RoleUser, "10.0.0.1")
+		is.Nil(err)
+		is.Equal(http.StatusOK, rec.Code)
+	})
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	start := time.Date(2026, 5, 10, 16, 0, 0, 0, time.UTC)
+
+	for range 3 {
+		store.Hit(ctx, "user:fan", start, time.Minute)
+	}
+	current, previous, _ := store.Hit(ctx, "user:fan", start.Add(time.Minute), time.Minute)
+	is.Equal(1, current)
+	is.Equal(3, previous)
+
+	current, previous, _ = store.Hit(ctx, "user:fan", start.Add(3*time.Minute), time.Minute)
+	is.Equal(1, current)
+	is.Equal(0, previous)
+
+	store.Hit(ctx, "ip:10.0.0.1", start.Add(10*time.Minute), time.Minute)
+	is.NotContains(store.windows, "user:fan", "stale counters are swept")
+}
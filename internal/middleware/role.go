@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/fut-app/pkg/tenant"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole rejects requests whose credential has none of the given
+// roles. It must run after an authentication middleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("role").(string)
+			if !slices.Contains(roles, role) {
+				return echo.NewHTTPError(http.StatusForbidden, "insufficient role")
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequirePlatform rejects organization members. It must run after an
+// authentication middleware.
+func RequirePlatform() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := tenant.Organization(c.Request().Context()); ok {
+				return echo.NewHTTPError(http.StatusForbidden, "restricted to platform credentials")
+			}
+			return next(c)
+		}
+	}
+}
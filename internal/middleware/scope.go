@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireScopes rejects requests whose token or API key was not granted
+// every given scope. It must run after an authentication middleware.
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			granted, _ := c.Get("scopes").(model.Scopes)
+			if missing := model.Scopes(scopes).Unknown(granted); len(missing) > 0 {
+				return echo.NewHTTPError(http.StatusForbidden, "insufficient scope: requires "+missing.String())
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireScopes(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	tests := []struct {
+		name     string
+		granted  any
+		required []string
+		hasErr   bool
+	}{
+		{
+			name:     "when every required scope is granted, should call next",
+			granted:  model.Scopes{model.ScopeCompetitionsRead, model.ScopeUsersManage},
+			required: []string{model.ScopeCompetitionsRead},
+		},
+		{
+			name:     "when a required scope is missing, should return forbidden",
+			granted:  model.Scopes{model.ScopeCompetitionsRead},
+			required: []string{model.ScopeUsersManage},
+			hasErr:   true,
+		},
+		{
+			name:     "when no scopes were granted, should return forbidden",
+			required: []string{model.ScopeCompetitionsRead},
+			hasErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			c.Set("scopes", test.granted)
+
+			err := RequireScopes(test.required...)(func(c echo.Context) error { return nil })(c)
+			if test.hasErr {
+				var httpErr *echo.HTTPError
+				is.ErrorAs(err, &httpErr)
+				is.Equal(http.StatusForbidden, httpErr.Code)
+				return
+			}
+			is.Nil(err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	APIKeyPrefix       = "fut_"
+	apiKeyRandomBytes  = 24
+	apiKeyVisibleChars = len(APIKeyPrefix) + 8
+)
+
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    Scopes     `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *APIKeyRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	if unknown := r.Scopes.Unknown(KnownScopes); len(unknown) > 0 {
+		return errors.New("unknown scopes: " + unknown.String())
+	}
+	if r.ExpiresAt != nil && r.ExpiresAt.Before(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+type APIKey struct {
+	ID           int        `gorm:"primaryKey" json:"id"`
+	CredentialID int        `gorm:"index;not null" json:"-"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Name         string     `gorm:"not null" json:"name"`
+	Prefix       string     `gorm:"not null" json:"prefix"`
+	HashedKey    string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes       Scopes     `gorm:"type:text" json:"scopes"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKey is the only response carrying the plain key.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// NewAPIKey generates a random key for the credential. The plain key is
+// returned once and only its SHA-256 hash is kept.
+func (r *APIKeyRequest) NewAPIKey(credentialID int) (*APIKey, string, error) {
+	random := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", err
+	}
+	raw := APIKeyPrefix + hex.EncodeToString(random)
+
+	key := APIKey{
+		CredentialID: credentialID,
+		Name:         strings.TrimSpace(r.Name),
+		Prefix:       raw[:apiKeyVisibleChars],
+		HashedKey:    HashAPIKey(raw),
+		Scopes:       r.Scopes,
+		ExpiresAt:    r.ExpiresAt,
+		CreatedAt:    time.Now(),
+	}
+	return &key, raw, nil
+}
+
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AuditLoginSuccess       = "login.success"
+	AuditLoginFailure       = "login.failure"
+	AuditTokenRefresh       = "token.refresh"
+	AuditLogout             = "logout"
+	AuditCredentialCreate   = "credential.create"
+	AuditCredentialUpdate   = "credential.update"
+	AuditCredentialDelete   = "credential.delete"
+	AuditRoleChange         = "credential.role_change"
+	AuditPasswordChange     = "password.change"
+	AuditPasswordReset      = "password.reset"
+	AuditOrganizationCreate = "organization.create"
+)
+
+const (
+	defaultAuditPageSize   = 50
+	maxAuditPageSize       = 200
+	auditDetailMaxBytes    = 255
+	auditUserAgentMaxBytes = 512
+)
+
+var ErrAuditAppendOnly = errors.New("audit events are append-only")
+
+// AuditEvent is a security relevant action. Rows are never updated or
+// deleted once written.
+type AuditEvent struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	Event     string    `gorm:"index;not null" json:"event"`
+	Actor     string    `gorm:"index" json:"actor,omitempty"`
+	Target    string    `gorm:"index" json:"target,omitempty"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Detail    string    `gorm:"size:255" json:"detail,omitempty"`
+	IP        string    `gorm:"size:64" json:"ip,omitempty"`
+	UserAgent string    `gorm:"size:512" json:"user_agent,omitempty"`
+	CreatedAt time.Time `gorm:"index;not null" json:"created_at"`
+}
+
+func (e *AuditEvent) BeforeCreate(_ *gorm.DB) error {
+	e.Detail = truncate(e.Detail, auditDetailMaxBytes)
+	e.UserAgent = truncate(e.UserAgent, auditUserAgentMaxBytes)
+	return nil
+}
+
+func (*AuditEvent) BeforeUpdate(_ *gorm.DB) error {
+	return ErrAuditAppendOnly
+}
+
+func (*AuditEvent) BeforeDelete(_ *gorm.DB) error {
+	return ErrAuditAppendOnly
+}
+
+// AuditFilter selects audit events. Empty fields match everything.
+type AuditFilter struct {
+	Event   string     `query:"event"`
+	Actor   string     `query:"actor"`
+	Target  string     `query:"target"`
+	From    *time.Time `query:"from"`
+	To      *time.Time `query:"to"`
+	Page    int        `query:"page"`
+	PerPage int        `query:"per_page"`
+}
+
+func (f *AuditFilter) Validate() error {
+	if f.Page < 0 || f.PerPage < 0 {
+		return errors.New("page and per_page must not be negative")
+	}
+	if f.PerPage > maxAuditPageSize {
+		return errors.New("per_page must be at most 200")
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return errors.New("to must not be before from")
+	}
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PerPage == 0 {
+		f.PerPage = defaultAuditPageSize
+	}
+	return nil
+}
+
+type AuditPage struct {
+	Events  []AuditEvent `json:"events"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+	Total   int64        `json:"total"`
+}
+
+// truncate cuts value to at most size bytes without splitting a rune.
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
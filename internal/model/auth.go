@@ -1,20 +1,44 @@
 package model
 
 import (
+	"crypto/rand"
 	"errors"
+	"net/mail"
 	"time"
 
+	"github.com/fut-app/pkg/hasher"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+
+	// PurposeMFA marks challenge tokens that can only be exchanged on the
+	// second login step.
+	PurposeMFA = "mfa"
 )
 
 type AuthRequest struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Role     string `json:"role,omitempty"`
+	// Org is the slug of the organization owning a new credential.
+	Org string `json:"org,omitempty"`
 }
 
 type AuthClaims struct {
 	Username string `json:"username"`
+	Scope    string `json:"scope"`
+	Role     string `json:"role,omitempty"`
+	OrgID    int    `json:"org_id,omitempty"`
+	Purpose  string `json:"purpose,omitempty"`
+	// AuthTime is when the user signed in (OpenID Connect auth_time).
+	// Refreshed tokens keep the original value.
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -25,18 +49,68 @@ func (a *AuthRequest) Validate() error {
 	if a.Password == "" {
 		return errors.New("password is required")
 	}
+	if a.Email != "" {
+		if _, err := mail.ParseAddress(a.Email); err != nil {
+			return errors.New("email is invalid")
+		}
+	}
+	if unknown := ParseScopes(a.Scope).Unknown(KnownScopes); len(unknown) > 0 {
+		return errors.New("unknown scopes: " + unknown.String())
+	}
+	if a.Role != "" && a.Role != RoleAdmin && a.Role != RoleUser {
+		return errors.New("role must be admin or user")
+	}
 	return nil
 }
 
-func (a *AuthRequest) ParseAuthRequestToCredential() (*Credential, error) {
-	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(a.Password), bcrypt.DefaultCost)
+// CredentialUpdateRequest changes the given fields of a credential.
+type CredentialUpdateRequest struct {
+	Role  *string `json:"role,omitempty"`
+	Scope *string `json:"scope,omitempty"`
+	Email *string `json:"email,omitempty"`
+}
+
+func (r *CredentialUpdateRequest) Validate() error {
+	if r.Role == nil && r.Scope == nil && r.Email == nil {
+		return errors.New("at least one of role, scope or email is required")
+	}
+	if r.Role != nil && *r.Role != RoleAdmin && *r.Role != RoleUser {
+		return errors.New("role must be admin or user")
+	}
+	if r.Scope != nil {
+		if len(ParseScopes(*r.Scope)) == 0 {
+			return errors.New("scope cannot be empty")
+		}
+		if unknown := ParseScopes(*r.Scope).Unknown(KnownScopes); len(unknown) > 0 {
+			return errors.New("unknown scopes: " + unknown.String())
+		}
+	}
+	if r.Email != nil && *r.Email != "" {
+		if _, err := mail.ParseAddress(*r.Email); err != nil {
+			return errors.New("email is invalid")
+		}
+	}
+	return nil
+}
+
+func (a *AuthRequest) ParseAuthRequestToCredential(passwords hasher.Hasher) (*Credential, error) {
+	encryptedPassword, err := passwords.Hash(a.Password)
 	if err != nil {
 		return nil, err
 	}
 
 	credential := Credential{
 		User:              a.User,
-		EncryptedPassword: string(encryptedPassword),
+		EncryptedPassword: encryptedPassword,
+		Email:             a.Email,
+		Scopes:            ParseScopes(a.Scope),
+		Role:              a.Role,
+	}
+	if len(credential.Scopes) == 0 {
+		credential.Scopes = DefaultScopes
+	}
+	if credential.Role == "" {
+		credential.Role = RoleUser
 	}
 
 	return &credential, nil
@@ -44,22 +118,104 @@ func (a *AuthRequest) ParseAuthRequestToCredential() (*Credential, error) {
 
 type Credential struct {
 	ID                int       `gorm:"primaryKey"`
-	User              string    `gorm:"user"`
-	EncryptedPassword string    `gorm:"encryptedPassword"`
+	User              string    `gorm:"uniqueIndex;not null"`
+	EncryptedPassword string    `gorm:"not null"`
+	Email             string    `gorm:"size:255"`
+	Scopes            Scopes    `gorm:"type:text"`
+	ScopesRevision    int       `gorm:"not null;default:0"`
+	Role              string    `gorm:"not null;default:user"`
+	TOTPSecret        string    `gorm:"size:64"`
+	TOTPEnabled       bool      `gorm:"not null;default:false"`
+	TOTPLastStep      int64     `gorm:"not null;default:0"`
+	MFAChallengeID    string    `gorm:"size:64"`
+	MFAFailures       int       `gorm:"not null;default:0"`
 	CreatedAt         time.Time `gorm:"createdAt"`
 	UpdatedAt         time.Time `gorm:"updatedAt"`
+	SessionsRevokedAt *time.Time
+	MFALockedUntil    *time.Time
+	OrgID             *int          `gorm:"index"`
+	Organization      *Organization `gorm:"foreignKey:OrgID;constraint:OnDelete:RESTRICT"`
 }
 
-func (a *Credential) GenerateToken(secretKey string) (string, error) {
-	now := time.Now()
-	claims := AuthClaims{
-		Username: a.User,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour * 1)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			Subject:   a.User,
-		},
+// BeforeCreate marks new credentials as past every scope split, so that
+// migrations don't grant them scopes they were not given.
+func (a *Credential) BeforeCreate(_ *gorm.DB) error {
+	if a.ScopesRevision == 0 {
+		a.ScopesRevision = len(ScopeSplits)
+	}
+	return nil
+}
+
+// OrganizationID returns the organization owning the credential, or zero
+// for platform credentials.
+func (a *Credential) OrganizationID() int {
+	if a.OrgID == nil {
+		return 0
+	}
+	return *a.OrgID
+}
+
+// AllowedScopes returns the scopes the credential may request. A
+// credential without scopes may request none.
+func (a *Credential) AllowedScopes() Scopes {
+	return a.Scopes
+}
+
+// GrantScopes narrows the allowed scopes to the requested ones, failing
+// when any of them is not allowed.
+func (a *Credential) GrantScopes(requested Scopes) (Scopes, error) {
+	allowed := a.AllowedScopes()
+	if len(requested) == 0 {
+		return allowed, nil
 	}
+	if unknown := requested.Unknown(allowed); len(unknown) > 0 {
+		return nil, errors.New("scopes not allowed: " + unknown.String())
+	}
+	return requested, nil
+}
+
+func (a *Credential) GenerateToken(secretKey string, scopes Scopes) (string, error) {
+	return a.GenerateRefreshedToken(secretKey, scopes, time.Now())
+}
+
+// GenerateRefreshedToken issues an access token for a session that started
+// at authTime, which refreshes carry over from the previous token.
+func (a *Credential) GenerateRefreshedToken(secretKey string, scopes Scopes, authTime time.Time) (string, error) {
+	return a.signToken(secretKey, AuthClaims{
+		Scope:    scopes.String(),
+		AuthTime: jwt.NewNumericDate(authTime),
+	}, time.Hour*1)
+}
+
+// GenerateChallengeToken issues the short-lived token proving the first
+// login step succeeded. It carries the scopes granted for the final token
+// and a new MFAChallengeID, which must be stored for the token to be
+// redeemed, once.
+func (a *Credential) GenerateChallengeToken(secretKey string, scopes Scopes, ttl time.Duration) (string, error) {
+	a.MFAChallengeID = rand.Text()
+	return a.signToken(secretKey, AuthClaims{
+		Scope:            scopes.String(),
+		Purpose:          PurposeMFA,
+		RegisteredClaims: jwt.RegisteredClaims{ID: a.MFAChallengeID},
+	}, ttl)
+}
+
+// MFALocked reports whether too many wrong two-factor codes locked the
+// second login step at now.
+func (a *Credential) MFALocked(now time.Time) bool {
+	return a.MFALockedUntil != nil && now.Before(*a.MFALockedUntil)
+}
+
+// signToken completes claims with the identity of the credential and a
+// lifetime of ttl.
+func (a *Credential) signToken(secretKey string, claims AuthClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.Username = a.User
+	claims.Role = a.Role
+	claims.OrgID = a.OrganizationID()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.Subject = a.User
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenSttring, err := token.SignedString([]byte(secretKey))
 	if err != nil {
@@ -68,7 +224,19 @@ func (a *Credential) GenerateToken(secretKey string) (string, error) {
 	return tokenSttring, nil
 }
 
+// SessionRevoked reports whether a token issued at issuedAt predates the
+// last revocation, such as a password reset. JWT timestamps have second
+// precision, so tokens issued within the revocation second are revoked too.
+func (a *Credential) SessionRevoked(issuedAt time.Time) bool {
+	if a.SessionsRevokedAt == nil {
+		return false
+	}
+	return !issuedAt.After(a.SessionsRevokedAt.Truncate(time.Second))
+}
+
+// CheckPassword verifies the password against the stored hash, whichever
+// supported algorithm produced it, including legacy bcrypt hashes.
 func (c *Credential) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(c.EncryptedPassword), []byte(password))
-	return err == nil
+	ok, err := hasher.Verify(c.EncryptedPassword, password)
+	return err == nil && ok
 }
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fut-app/pkg/ical"
+)
+
+const (
+	CalendarTokenPrefix       = "cal_"
+	calendarTokenRandomBytes  = 24
+	calendarTokenVisibleChars = len(CalendarTokenPrefix) + 8
+
+	calendarProdID = "-//fut-app//partidas//PT"
+)
+
+// CalendarToken lets calendar apps, which cannot send headers, subscribe to
+// the fixtures of their owner in the token query parameter. It only grants
+// reading calendars and each credential has at most one.
+type CalendarToken struct {
+	ID           int        `gorm:"primaryKey" json:"-"`
+	CredentialID int        `gorm:"uniqueIndex;not null" json:"-"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Prefix       string     `gorm:"not null" json:"prefix"`
+	HashedToken  string     `gorm:"uniqueIndex;not null" json:"-"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreatedCalendarToken is the only response carrying the plain token.
+type CreatedCalendarToken struct {
+	CalendarToken
+	Token string `json:"token"`
+}
+
+// NewCalendarToken generates a random token for the credential. The plain
+// token is returned once and only its SHA-256 hash is kept.
+func NewCalendarToken(credentialID int) (*CalendarToken, string, error) {
+	random := make([]byte, calendarTokenRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", err
+	}
+	raw := CalendarTokenPrefix + hex.EncodeToString(random)
+
+	token := CalendarToken{
+		CredentialID: credentialID,
+		Prefix:       raw[:calendarTokenVisibleChars],
+		HashedToken:  HashCalendarToken(raw),
+		CreatedAt:    time.Now(),
+	}
+	return &token, raw, nil
+}
+
+func HashCalendarToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchCalendar lists the matches as calendar events displayed in location.
+// Matches without a confirmed time become all-day events on their date in
+// location.
+func MatchCalendar(name string, matches []Match, location *time.Location, duration time.Duration, refresh time.Duration) ical.Calendar {
+	calendar := ical.Calendar{
+		ProdID:   calendarProdID,
+		Name:     name,
+		TimeZone: location.String(),
+		Refresh:  refresh,
+		Events:   []ical.Event{},
+	}
+	now := time.Now()
+	for _, match := range matches {
+		calendar.Events = append(calendar.Events, matchEvent(match, location, duration, now))
+	}
+	return calendar
+}
+
+func matchEvent(match Match, location *time.Location, duration time.Duration, now time.Time) ical.Event {
+	event := ical.Event{
+		// The id is stable across reschedules, so clients move the event.
+		UID:          fmt.Sprintf("partida-%d@fut-app", match.ID),
+		Sequence:     matchSequence(match.LastUpdated),
+		Stamp:        now,
+		LastModified: match.LastUpdated,
+		Start:        match.UTCDate,
+		End:          match.UTCDate.Add(duration),
+		Summary:      matchSummary(match),
+		Location:     match.Venue,
+		Status:       ical.StatusConfirmed,
+	}
+	if !match.LastUpdated.IsZero() {
+		event.Stamp = match.LastUpdated
+	}
+
+	var details []string
+	if match.Competition.Name != "" {
+		details = append(details, match.Competition.Name)
+	}
+	if match.Matchday != nil {
+		details = append(details, fmt.Sprintf("Rodada %d", *match.Matchday))
+	}
+
+	switch match.Status {
+	case MatchScheduled:
+		year, month, day := match.UTCDate.In(location).Date()
+		event.AllDay = true
+		event.Start = time.Date(year, month, day, 0, 0, 0, 0, location)
+		event.End = event.Start.AddDate(0, 0, 1)
+		event.Status = ical.StatusTentative
+		details = append(details, "Horário a confirmar")
+	case MatchPostponed:
+		event.Status = ical.StatusTentative
+		details = append(details, "Partida adiada")
+	case MatchCancelled:
+		event.Status = ical.StatusCancelled
+	}
+	event.Description = strings.Join(details, "\n")
+	return event
+}
+
+func matchSummary(match Match) string {
+	home, away := match.HomeTeam.Name, match.AwayTeam.Name
+	score := match.Score.FullTime
+	if match.Status == MatchFinished && score.Home != nil && score.Away != nil {
+		return fmt.Sprintf("%s %d x %d %s", home, *score.Home, *score.Away, away)
+	}
+	return fmt.Sprintf("%s x %s", home, away)
+}
+
+// matchSequence derives the event SEQUENCE from the last update of the
+// match. The football app touches lastUpdated on every change, kickoff
+// included, so counting its minutes grows whenever the match is rescheduled
+// without storing revisions.
+func matchSequence(lastUpdated time.Time) int {
+	if lastUpdated.IsZero() {
+		return 0
+	}
+	return int(lastUpdated.Unix() / 60)
+}
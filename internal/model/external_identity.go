@@ -0,0 +1,51 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// PurposeOIDC marks the short-lived token holding the OIDC login state
+// between the redirect to the provider and the callback.
+const PurposeOIDC = "oidc"
+
+// ExternalIdentity links a subject of an OpenID Connect issuer to a local
+// credential.
+type ExternalIdentity struct {
+	ID           int        `gorm:"primaryKey"`
+	Issuer       string     `gorm:"uniqueIndex:idx_external_identity;not null"`
+	Subject      string     `gorm:"uniqueIndex:idx_external_identity;not null"`
+	CredentialID int        `gorm:"index;not null"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE"`
+	Email        string     `gorm:"size:255"`
+	CreatedAt    time.Time
+}
+
+// OIDCClaims are the ID token claims used to provision credentials.
+type OIDCClaims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+}
+
+// Username picks the local user name for a provisioned credential.
+func (c OIDCClaims) Username() string {
+	switch {
+	case c.PreferredUsername != "":
+		return c.PreferredUsername
+	case c.Email != "" && c.EmailVerified:
+		return c.Email
+	default:
+		return c.Subject
+	}
+}
+
+// DisambiguatedUsername is used when Username is already taken by another
+// credential, so external users never take over local accounts.
+func (c OIDCClaims) DisambiguatedUsername(issuer string) string {
+	sum := sha256.Sum256([]byte(issuer + "|" + c.Subject))
+	return c.Username() + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+const (
+	FavoriteCompetition = "competition"
+	FavoriteTeam        = "team"
+)
+
+// Favorite is a competition or team followed by a credential. ExternalID is
+// the football-data id and Name a snapshot taken when following.
+type Favorite struct {
+	ID           int        `gorm:"primaryKey"`
+	CredentialID int        `gorm:"not null;uniqueIndex:idx_favorite"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE"`
+	Kind         string     `gorm:"size:16;not null;uniqueIndex:idx_favorite"`
+	ExternalID   int        `gorm:"not null;uniqueIndex:idx_favorite"`
+	Name         string     `gorm:"size:255"`
+	CreatedAt    time.Time
+}
+
+// PublicID formats the id clients use, e.g. campeonato_021 or time_086.
+func (f Favorite) PublicID() string {
+	if f.Kind == FavoriteTeam {
+		return TeamID(f.ExternalID)
+	}
+	return CompetitionID(f.ExternalID)
+}
+
+type FormattedFavorite struct {
+	ID       string    `json:"id"`
+	Nome     string    `json:"nome"`
+	CriadoEm time.Time `json:"criado_em"`
+}
+
+// Favorites lists what a credential follows, by kind.
+type Favorites struct {
+	Campeonatos []FormattedFavorite `json:"campeonatos"`
+	Times       []FormattedFavorite `json:"times"`
+}
+
+// Feed aggregates upcoming matches and standings of followed items. Items
+// whose data could not be fetched carry an error instead.
+type Feed struct {
+	Campeonatos []FeedCompetition `json:"campeonatos"`
+	Times       []FeedTeam        `json:"times"`
+}
+
+type FeedCompetition struct {
+	ID               string              `json:"id"`
+	Nome             string              `json:"nome"`
+	ProximasPartidas []FormattedMatch    `json:"proximas_partidas"`
+	Classificacao    []FormattedStanding `json:"classificacao"`
+	Erro             string              `json:"erro,omitempty"`
+}
+
+type FeedTeam struct {
+	ID               string           `json:"id"`
+	Nome             string           `json:"nome"`
+	ProximasPartidas []FormattedMatch `json:"proximas_partidas"`
+	Erro             string           `json:"erro,omitempty"`
+}
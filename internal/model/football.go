@@ -1,5 +1,13 @@
 package model
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type CompetitionResponse struct {
 	Competitions []Competition `json:"competitions"`
 }
@@ -38,3 +46,262 @@ type FormattedCompetition struct {
 	Nome      string `json:"nome"`
 	Temporada string `json:"temporada"`
 }
+
+const (
+	CompetitionIDPrefix = "campeonato_"
+	TeamIDPrefix        = "time_"
+
+	MatchScheduled = "SCHEDULED"
+	MatchTimed     = "TIMED"
+	MatchInPlay    = "IN_PLAY"
+	MatchPaused    = "PAUSED"
+	MatchFinished  = "FINISHED"
+	MatchPostponed = "POSTPONED"
+	MatchCancelled = "CANCELLED"
+	// MatchLive filters matches in play or at half time.
+	MatchLive = "LIVE"
+)
+
+// CompetitionID formats the public id of a competition, e.g. campeonato_021.
+func CompetitionID(id int) string {
+	return fmt.Sprintf("%s%03d", CompetitionIDPrefix, id)
+}
+
+// ParseCompetitionID reads the football-data id out of a public id.
+func ParseCompetitionID(id string) (int, error) {
+	return parsePublicID(id, CompetitionIDPrefix)
+}
+
+// TeamID formats the public id of a team, e.g. time_086.
+func TeamID(id int) string {
+	return fmt.Sprintf("%s%03d", TeamIDPrefix, id)
+}
+
+func ParseTeamID(id string) (int, error) {
+	return parsePublicID(id, TeamIDPrefix)
+}
+
+func parsePublicID(id string, prefix string) (int, error) {
+	number, ok := strings.CutPrefix(id, prefix)
+	if !ok {
+		return 0, fmt.Errorf("id must look like %sXXX", prefix)
+	}
+	parsed, err := strconv.Atoi(number)
+	if err != nil || parsed < 1 {
+		return 0, fmt.Errorf("id must look like %sXXX", prefix)
+	}
+	return parsed, nil
+}
+
+// MatchFilter narrows the matches returned by the football app. Dates use
+// the YYYY-MM-DD format.
+type MatchFilter struct {
+	Status   string
+	DateFrom string
+	DateTo   string
+}
+
+func (f MatchFilter) Query() url.Values {
+	query := url.Values{}
+	if f.Status != "" {
+		query.Set("status", f.Status)
+	}
+	if f.DateFrom != "" {
+		query.Set("dateFrom", f.DateFrom)
+	}
+	if f.DateTo != "" {
+		query.Set("dateTo", f.DateTo)
+	}
+	return query
+}
+
+type MatchResponse struct {
+	Matches []Match `json:"matches"`
+}
+
+type Match struct {
+	ID          int         `json:"id"`
+	UTCDate     time.Time   `json:"utcDate"`
+	Status      string      `json:"status"`
+	Matchday    *int        `json:"matchday"`
+	Stage       string      `json:"stage"`
+	Group       *string     `json:"group"`
+	Venue       string      `json:"venue"`
+	LastUpdated time.Time   `json:"lastUpdated"`
+	Competition Competition `json:"competition"`
+	HomeTeam    Team        `json:"homeTeam"`
+	AwayTeam    Team        `json:"awayTeam"`
+	Score       Score       `json:"score"`
+}
+
+type Score struct {
+	Winner   *string  `json:"winner"`
+	FullTime ScoreSet `json:"fullTime"`
+	HalfTime ScoreSet `json:"halfTime"`
+}
+
+type ScoreSet struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+type StandingsResponse struct {
+	Competition Competition `json:"competition"`
+	Season      Season      `json:"season"`
+	Standings   []Standing  `json:"standings"`
+}
+
+type Standing struct {
+	Stage string     `json:"stage"`
+	Type  string     `json:"type"`
+	Group *string    `json:"group"`
+	Table []TableRow `json:"table"`
+}
+
+type TableRow struct {
+	Position       int  `json:"position"`
+	Team           Team `json:"team"`
+	PlayedGames    int  `json:"playedGames"`
+	Won            int  `json:"won"`
+	Draw           int  `json:"draw"`
+	Lost           int  `json:"lost"`
+	Points         int  `json:"points"`
+	GoalsFor       int  `json:"goalsFor"`
+	GoalsAgainst   int  `json:"goalsAgainst"`
+	GoalDifference int  `json:"goalDifference"`
+}
+
+type ScorersResponse struct {
+	Competition Competition `json:"competition"`
+	Scorers     []Scorer    `json:"scorers"`
+}
+
+type Scorer struct {
+	Player        Player `json:"player"`
+	Team          Team   `json:"team"`
+	PlayedMatches int    `json:"playedMatches"`
+	Goals         int    `json:"goals"`
+	Assists       *int   `json:"assists"`
+	Penalties     *int   `json:"penalties"`
+}
+
+type Player struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Nationality string `json:"nationality"`
+}
+
+type FormattedMatch struct {
+	ID            int       `json:"id"`
+	Campeonato    string    `json:"campeonato,omitempty"`
+	Data          time.Time `json:"data"`
+	Status        string    `json:"status"`
+	Rodada        *int      `json:"rodada,omitempty"`
+	Mandante      string    `json:"mandante"`
+	Visitante     string    `json:"visitante"`
+	GolsMandante  *int      `json:"gols_mandante"`
+	GolsVisitante *int      `json:"gols_visitante"`
+	Local         string    `json:"local,omitempty"`
+}
+
+type FormattedStanding struct {
+	Grupo      string `json:"grupo,omitempty"`
+	Posicao    int    `json:"posicao"`
+	Time       string `json:"time"`
+	Pontos     int    `json:"pontos"`
+	Jogos      int    `json:"jogos"`
+	Vitorias   int    `json:"vitorias"`
+	Empates    int    `json:"empates"`
+	Derrotas   int    `json:"derrotas"`
+	GolsPro    int    `json:"gols_pro"`
+	GolsContra int    `json:"gols_contra"`
+	SaldoGols  int    `json:"saldo_gols"`
+}
+
+type FormattedScorer struct {
+	Posicao      int    `json:"posicao"`
+	Jogador      string `json:"jogador"`
+	Time         string `json:"time"`
+	Jogos        int    `json:"jogos"`
+	Gols         int    `json:"gols"`
+	Assistencias int    `json:"assistencias"`
+	Penaltis     int    `json:"penaltis"`
+}
+
+func FormatMatches(matches []Match) []FormattedMatch {
+	result := []FormattedMatch{}
+	for _, match := range matches {
+		formatted := FormattedMatch{
+			ID:            match.ID,
+			Data:          match.UTCDate,
+			Status:        match.Status,
+			Rodada:        match.Matchday,
+			Mandante:      match.HomeTeam.Name,
+			Visitante:     match.AwayTeam.Name,
+			GolsMandante:  match.Score.FullTime.Home,
+			GolsVisitante: match.Score.FullTime.Away,
+			Local:         match.Venue,
+		}
+		if match.Competition.ID != 0 {
+			formatted.Campeonato = CompetitionID(match.Competition.ID)
+		}
+		result = append(result, formatted)
+	}
+	return result
+}
+
+// FormatStandings flattens the overall tables, one per group in cups.
+func FormatStandings(data StandingsResponse) []FormattedStanding {
+	result := []FormattedStanding{}
+	for _, standing := range data.Standings {
+		if standing.Type != "TOTAL" {
+			continue
+		}
+		var group string
+		if standing.Group != nil {
+			group = *standing.Group
+		}
+		for _, row := range standing.Table {
+			result = append(result, FormattedStanding{
+				Grupo:      group,
+				Posicao:    row.Position,
+				Time:       row.Team.Name,
+				Pontos:     row.Points,
+				Jogos:      row.PlayedGames,
+				Vitorias:   row.Won,
+				Empates:    row.Draw,
+				Derrotas:   row.Lost,
+				GolsPro:    row.GoalsFor,
+				GolsContra: row.GoalsAgainst,
+				SaldoGols:  row.GoalDifference,
+			})
+		}
+	}
+	return result
+}
+
+// FormatScorers ranks the top scorers, tied players sharing a position.
+func FormatScorers(data ScorersResponse) []FormattedScorer {
+	result := []FormattedScorer{}
+	for i, scorer := range data.Scorers {
+		position := i + 1
+		if i > 0 && scorer.Goals == data.Scorers[i-1].Goals {
+			position = result[i-1].Posicao
+		}
+		formatted := FormattedScorer{
+			Posicao: position,
+			Jogador: scorer.Player.Name,
+			Time:    scorer.Team.Name,
+			Jogos:   scorer.PlayedMatches,
+			Gols:    scorer.Goals,
+		}
+		if scorer.Assists != nil {
+			formatted.Assistencias = *scorer.Assists
+		}
+		if scorer.Penalties != nil {
+			formatted.Penaltis = *scorer.Penalties
+		}
+		result = append(result, formatted)
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"crypto/subtle"
+	"strings"
+)
+
+// IntrospectionClientAllowed reports whether id and secret match one of
+// the client_id:client_secret pairs in clients, comparing every pair in
+// constant time.
+func IntrospectionClientAllowed(clients []string, id string, secret string) bool {
+	if id == "" || secret == "" {
+		return false
+	}
+
+	matched := 0
+	for _, client := range clients {
+		clientID, clientSecret, _ := strings.Cut(client, ":")
+		matched |= subtle.ConstantTimeCompare([]byte(id), []byte(clientID)) &
+			subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret))
+	}
+	return matched == 1
+}
+
+// IntrospectionResponse follows RFC 7662. Inactive tokens only carry
+// active=false.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Role      string `json:"role,omitempty"`
+	OrgID     int    `json:"org_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+}
+
+// UserInfo is the OpenID Connect style profile of the token owner.
+type UserInfo struct {
+	Sub               string `json:"sub"`
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email,omitempty"`
+	Role              string `json:"role"`
+	Scope             string `json:"scope"`
+	MFAEnabled        bool   `json:"mfa_enabled"`
+	UpdatedAt         int64  `json:"updated_at"`
+}
+
+func (a *Credential) UserInfo(scopes Scopes) UserInfo {
+	return UserInfo{
+		Sub:               a.User,
+		PreferredUsername: a.User,
+		Email:             a.Email,
+		Role:              a.Role,
+		Scope:             scopes.String(),
+		MFAEnabled:        a.TOTPEnabled,
+		UpdatedAt:         a.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,17 @@
+package model
+
+const (
+	// LiveSnapshot carries every live match known when a client connects.
+	LiveSnapshot = "snapshot"
+	// LiveMatchUpdate is sent when a live match changes score or status.
+	LiveMatchUpdate = "match"
+	// LiveMatchEnded is sent when a match leaves the live list, with its
+	// last known state.
+	LiveMatchEnded = "ended"
+)
+
+// LiveEvent is one change to the live matches.
+type LiveEvent struct {
+	Type    string         `json:"tipo"`
+	Partida FormattedMatch `json:"partida"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// Models lists every table managed by the migrator. Keep column types
+// portable so the same migration runs on Postgres and SQLite.
+func Models() []any {
+	return []any{
+		&Organization{},
+		&Credential{},
+		&APIKey{},
+		&CalendarToken{},
+		&RecoveryCode{},
+		&PasswordResetToken{},
+		&AuditEvent{},
+		&ExternalIdentity{},
+		&Favorite{},
+		&Webhook{},
+		&WebhookDelivery{},
+		&RateLimitCounter{},
+	}
+}
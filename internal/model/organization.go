@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var organizationSlug = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// Organization is a tenant, such as a fan club, owning its credentials.
+// Credentials without one belong to the platform.
+type Organization struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"uniqueIndex;size:63;not null" json:"slug"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func (r *OrganizationRequest) Validate() error {
+	if !organizationSlug.MatchString(r.Slug) {
+		return errors.New("slug must have 2 to 63 lowercase letters, digits or dashes")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = parseCommonPasswords(commonPasswordsFile)
+
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (err *PasswordPolicyError) Error() string {
+	return "password does not meet the policy: " + strings.Join(err.Violations, "; ")
+}
+
+// Check returns a PasswordPolicyError listing every rule the password
+// breaks, or nil.
+func (p PasswordPolicy) Check(user string, password string) error {
+	var violations []string
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must have at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !strings.ContainsFunc(password, unicode.IsUpper) {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !strings.ContainsFunc(password, unicode.IsLower) {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !strings.ContainsFunc(password, isSymbol) {
+		violations = append(violations, "must contain a symbol")
+	}
+	if user != "" && strings.EqualFold(password, user) {
+		violations = append(violations, "must not be equal to the user")
+	}
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		violations = append(violations, "must not be a commonly used password")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	if r.CurrentPassword == "" {
+		return errors.New("current_password is required")
+	}
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+	return nil
+}
+
+func isSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func parseCommonPasswords(content string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	policy := PasswordPolicy{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+	tests := []struct {
+		name               string
+		user               string
+		password           string
+		expectedViolations []string
+	}{
+		{
+			name:     "given a strong password, should be not error",
+			user:     "admin",
+			password: "Gol-de-Placa-1970",
+		},
+		{
+			name:     "given a short password without classes, should return every violation",
+			user:     "admin",
+			password: "xpto123",
+			expectedViolations: []string{
+				"must have at least 10 characters",
+				"must contain an uppercase letter",
+			},
+		},
+		{
+			name:     "given a password equal to the user, should return an error",
+			user:     "Torcedor2024",
+			password: "torcedor2024",
+			expectedViolations: []string{
+				"must contain an uppercase letter",
+				"must not be equal to the user",
+			},
+		},
+		{
+			name:     "given a common password, should return an error",
+			user:     "admin",
+			password: "Password123",
+			expectedViolations: []string{
+				"must not be a commonly used password",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := policy.Check(test.user, test.password)
+			if len(test.expectedViolations) == 0 {
+				is.Nil(err)
+				return
+			}
+			var policyErr *PasswordPolicyError
+			is.True(errors.As(err, &policyErr))
+			is.Equal(test.expectedViolations, policyErr.Violations)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const (
+	PasswordResetTokenPrefix = "fut_rst_"
+	resetTokenRandomBytes    = 32
+)
+
+type PasswordResetToken struct {
+	ID           int        `gorm:"primaryKey"`
+	CredentialID int        `gorm:"index;not null"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE"`
+	HashedToken  string     `gorm:"uniqueIndex;not null"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+type ForgotPasswordRequest struct {
+	User string `json:"user"`
+}
+
+func (r *ForgotPasswordRequest) Validate() error {
+	if r.User == "" {
+		return errors.New("user is required")
+	}
+	return nil
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (r *ResetPasswordRequest) Validate() error {
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+	return nil
+}
+
+// NewPasswordResetToken generates a single-use token for the credential.
+// Only its SHA-256 hash is stored; the plain token is sent by mail.
+func NewPasswordResetToken(credentialID int, ttl time.Duration) (*PasswordResetToken, string, error) {
+	random := make([]byte, resetTokenRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", err
+	}
+	raw := PasswordResetTokenPrefix + hex.EncodeToString(random)
+
+	now := time.Now()
+	token := PasswordResetToken{
+		CredentialID: credentialID,
+		HashedToken:  HashResetToken(raw),
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+	return &token, raw, nil
+}
+
+func HashResetToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *PasswordResetToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// RateLimitCounter counts the requests of one rate limit key in the fixed
+// window starting at WindowStart.
+type RateLimitCounter struct {
+	Key         string    `gorm:"primaryKey;size:255"`
+	WindowStart time.Time `gorm:"primaryKey"`
+	Hits        int       `gorm:"not null;default:0"`
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	ScopeCompetitionsRead = "competitions:read"
+	ScopeUsersManage      = "users:manage"
+	ScopeAPIKeysManage    = "api-keys:manage"
+	ScopeAuditRead        = "audit:read"
+	ScopeFavoritesWrite   = "favorites:write"
+	ScopeWebhooksWrite    = "webhooks:write"
+	ScopeCalendarWrite    = "calendar:write"
+)
+
+// KnownScopes lists every scope the API understands.
+var KnownScopes = Scopes{
+	ScopeCompetitionsRead,
+	ScopeUsersManage,
+	ScopeAPIKeysManage,
+	ScopeAuditRead,
+	ScopeFavoritesWrite,
+	ScopeWebhooksWrite,
+	ScopeCalendarWrite,
+}
+
+// DefaultScopes are granted to credentials created without a scope.
+var DefaultScopes = Scopes{
+	ScopeCompetitionsRead,
+	ScopeFavoritesWrite,
+	ScopeWebhooksWrite,
+	ScopeCalendarWrite,
+}
+
+// ScopeSplit records scopes carved out of an existing one.
+type ScopeSplit struct {
+	From    string
+	Granted Scopes
+}
+
+// ScopeSplits lists every split in order. Credentials created before a split
+// are granted its scopes on migration when they hold From, so they keep
+// what they could do. Append only: Credential.ScopesRevision counts the
+// splits already applied.
+var ScopeSplits = []ScopeSplit{
+	{From: ScopeCompetitionsRead, Granted: Scopes{ScopeFavoritesWrite}},
+	{From: ScopeCompetitionsRead, Granted: Scopes{ScopeWebhooksWrite, ScopeCalendarWrite}},
+}
+
+// Scopes is stored and transported as a space separated string (RFC 6749).
+type Scopes []string
+
+func ParseScopes(value string) Scopes {
+	return Scopes(strings.Fields(value))
+}
+
+func (s Scopes) String() string {
+	return strings.Join(s, " ")
+}
+
+func (s Scopes) Has(scope string) bool {
+	return slices.Contains(s, scope)
+}
+
+// Unknown returns the scopes that are not in allowed.
+func (s Scopes) Unknown(allowed Scopes) Scopes {
+	var unknown Scopes
+	for _, scope := range s {
+		if !allowed.Has(scope) {
+			unknown = append(unknown, scope)
+		}
+	}
+	return unknown
+}
+
+// Intersect returns the scopes that are also in allowed.
+func (s Scopes) Intersect(allowed Scopes) Scopes {
+	intersection := Scopes{}
+	for _, scope := range s {
+		if allowed.Has(scope) {
+			intersection = append(intersection, scope)
+		}
+	}
+	return intersection
+}
+
+// Grant returns the scopes with the missing ones of granted appended.
+func (s Scopes) Grant(granted Scopes) Scopes {
+	result := slices.Clone(s)
+	for _, scope := range granted {
+		if !result.Has(scope) {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+func (s *Scopes) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+	case string:
+		*s = ParseScopes(v)
+	case []byte:
+		*s = ParseScopes(string(v))
+	default:
+		return fmt.Errorf("unsupported scopes type %T", value)
+	}
+	return nil
+}
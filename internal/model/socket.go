@@ -0,0 +1,52 @@
+package model
+
+import "errors"
+
+// Messages a client sends over the socket.
+const (
+	SocketSubscribe   = "subscribe"
+	SocketUnsubscribe = "unsubscribe"
+	SocketPing        = "ping"
+)
+
+// Messages the server sends over the socket.
+const (
+	SocketSubscribed   = "subscribed"
+	SocketUnsubscribed = "unsubscribed"
+	SocketMatch        = "match"
+	SocketMatchEnded   = "match_ended"
+	SocketStandings    = "standings"
+	SocketScorers      = "scorers"
+	SocketPong         = "pong"
+	SocketError        = "error"
+)
+
+// SocketRequest is a client message, e.g.
+// {"tipo": "subscribe", "campeonato": "campeonato_2021"}.
+type SocketRequest struct {
+	Type       string `json:"tipo"`
+	Campeonato string `json:"campeonato,omitempty"`
+}
+
+// Validate checks the message and returns the competition it targets, zero
+// for ping.
+func (r *SocketRequest) Validate() (int, error) {
+	switch r.Type {
+	case SocketPing:
+		return 0, nil
+	case SocketSubscribe, SocketUnsubscribe:
+		return ParseCompetitionID(r.Campeonato)
+	default:
+		return 0, errors.New("unknown message type")
+	}
+}
+
+// SocketMessage is a server message. Only the fields of its type are set.
+type SocketMessage struct {
+	Type          string              `json:"tipo"`
+	Campeonato    string              `json:"campeonato,omitempty"`
+	Partida       *FormattedMatch     `json:"partida,omitempty"`
+	Classificacao []FormattedStanding `json:"classificacao,omitempty"`
+	Artilharia    []FormattedScorer   `json:"artilharia,omitempty"`
+	Erro          string              `json:"erro,omitempty"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const RecoveryCodesCount = 10
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type RecoveryCode struct {
+	ID           int        `gorm:"primaryKey"`
+	CredentialID int        `gorm:"index;not null"`
+	Credential   Credential `gorm:"constraint:OnDelete:CASCADE"`
+	HashedCode   string     `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+type TwoFactorRequest struct {
+	Code string `json:"code"`
+}
+
+func (r *TwoFactorRequest) Validate() error {
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recovery_code,omitempty"`
+}
+
+func (r *TwoFactorLoginRequest) Validate() error {
+	if r.ChallengeToken == "" {
+		return errors.New("challenge_token is required")
+	}
+	if (r.Code == "") == (r.RecoveryCode == "") {
+		return errors.New("exactly one of code or recovery_code is required")
+	}
+	return nil
+}
+
+// LoginChallenge is returned by the first login step when the password is
+// not enough to issue an access token.
+type LoginChallenge struct {
+	MFARequired        bool   `json:"mfa_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	EnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	EnrollmentToken    string `json:"enrollment_token,omitempty"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// GenerateRecoveryCodes returns plain codes to show once and their hashes
+// to store.
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, RecoveryCodesCount)
+	hashes := make([]string, 0, RecoveryCodesCount)
+	for range RecoveryCodesCount {
+		random := make([]byte, 5)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(recoveryEncoding.EncodeToString(random))
+		code := encoded[:4] + "-" + encoded[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	WebhookMatchStarted     = "match.started"
+	WebhookGoal             = "goal"
+	WebhookMatchFinished    = "match.finished"
+	WebhookStandingsChanged = "standings.changed"
+
+	WebhookSecretPrefix = "whsec_"
+
+	WebhookEventHeader     = "X-Fut-Event"
+	WebhookDeliveryHeader  = "X-Fut-Delivery"
+	WebhookTimestampHeader = "X-Fut-Timestamp"
+	WebhookSignatureHeader = "X-Fut-Signature"
+
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// KnownWebhookEvents lists every event a webhook can subscribe to.
+var KnownWebhookEvents = WebhookEvents{
+	WebhookMatchStarted,
+	WebhookGoal,
+	WebhookMatchFinished,
+	WebhookStandingsChanged,
+}
+
+// WebhookEvents is stored as a space separated string, like Scopes.
+type WebhookEvents []string
+
+func (e WebhookEvents) Has(event string) bool {
+	return Scopes(e).Has(event)
+}
+
+func (e WebhookEvents) Value() (driver.Value, error) {
+	return Scopes(e).Value()
+}
+
+func (e *WebhookEvents) Scan(value any) error {
+	var scopes Scopes
+	if err := scopes.Scan(value); err != nil {
+		return err
+	}
+	*e = WebhookEvents(scopes)
+	return nil
+}
+
+type WebhookRequest struct {
+	URL    string        `json:"url"`
+	Events WebhookEvents `json:"events"`
+}
+
+func (r *WebhookRequest) Validate() error {
+	parsed, err := url.ParseRequestURI(r.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	if len(r.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	if unknown := Scopes(r.Events).Unknown(Scopes(KnownWebhookEvents)); len(unknown) > 0 {
+		return errors.New("unknown events: " + unknown.String())
+	}
+	return nil
+}
+
+// Webhook receives the events of the competitions its owner follows. The
+// secret is kept in plain text because it signs every delivery.
+type Webhook struct {
+	ID           int           `gorm:"primaryKey" json:"id"`
+	CredentialID int           `gorm:"index;not null" json:"-"`
+	Credential   Credential    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	URL          string        `gorm:"size:2048;not null" json:"url"`
+	Events       WebhookEvents `gorm:"type:text" json:"events"`
+	Secret       string        `gorm:"size:128;not null" json:"-"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// CreatedWebhook is the only response carrying the signing secret.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+func (r *WebhookRequest) NewWebhook(credentialID int) *Webhook {
+	return &Webhook{
+		CredentialID: credentialID,
+		URL:          r.URL,
+		Events:       r.Events,
+		Secret:       WebhookSecretPrefix + rand.Text(),
+		CreatedAt:    time.Now(),
+	}
+}
+
+// Sign returns the signature receivers recompute to authenticate a
+// delivery: sha256= followed by the hex HMAC-SHA256 of "timestamp.body".
+func (w *Webhook) Sign(timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPayload is the JSON body of a delivery.
+type WebhookPayload struct {
+	Event         string              `json:"event"`
+	Campeonato    string              `json:"campeonato"`
+	OcorridoEm    time.Time           `json:"ocorrido_em"`
+	Partida       *FormattedMatch     `json:"partida,omitempty"`
+	Classificacao []FormattedStanding `json:"classificacao,omitempty"`
+}
+
+// WebhookDelivery logs one event sent, or still to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID            int        `gorm:"primaryKey" json:"id"`
+	WebhookID     int        `gorm:"index;not null" json:"-"`
+	Webhook       Webhook    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Event         string     `gorm:"size:32;not null" json:"event"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Status        string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	LastError     string     `gorm:"size:512" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Failed records a failed attempt, scheduling the next one with exponential
+// backoff until maxAttempts is reached.
+func (d *WebhookDelivery) Failed(now time.Time, code int, reason string, maxAttempts int, backoff time.Duration) {
+	d.ResponseCode = code
+	d.LastError = truncate(reason, 512)
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(backoff << (d.Attempts - 1))
+}
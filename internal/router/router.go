@@ -3,27 +3,220 @@ package router
 import (
 	"github.com/fut-app/internal/controller"
 	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
 	"github.com/labstack/echo/v4"
 )
 
-func Setup(app *echo.Echo, control *controller.Controller, secretKey string) {
+func Setup(
+	app *echo.Echo,
+	control *controller.Controller,
+	secretKey string,
+	apiKeys middleware.APIKeyAuthenticator,
+	sessions middleware.SessionValidator,
+	calendarTokens middleware.CalendarTokenAuthenticator,
+	rateLimit echo.MiddlewareFunc,
+	authRateLimit echo.MiddlewareFunc,
+) {
+	jwt := middleware.JWTMiddleware(secretKey, sessions)
+
 	authEndpoints(
-		app.Group("/auth"),
+		app.Group("/auth", authRateLimit),
 		&control.Auth,
-		secretKey,
+		jwt,
+	)
+	oidcEndpoints(
+		app.Group("/auth/oidc", authRateLimit),
+		&control.OIDC,
+	)
+	twoFactorEndpoints(
+		app.Group("/auth/2fa", authRateLimit, jwt),
+		&control.Auth,
+	)
+	apiKeyEndpoints(
+		app.Group(
+			"/auth/api-keys",
+			jwt,
+			middleware.RequireScopes(model.ScopeAPIKeysManage),
+		),
+		&control.APIKeys,
 	)
 	championshipEndpoints(
-		app.Group("/campeonatos", middleware.JWTMiddleware(secretKey)),
+		app.Group(
+			"/campeonatos",
+			middleware.APIKeyOrJWTMiddleware(secretKey, apiKeys, sessions),
+			rateLimit,
+		),
 		&control.Champion,
 	)
+	// Registered outside the groups so calendar apps may authenticate with
+	// the calendar token alone.
+	calendarEndpoints(
+		app,
+		&control.Calendar,
+		middleware.CalendarTokenMiddleware(secretKey, apiKeys, sessions, calendarTokens),
+		rateLimit,
+		middleware.RequireScopes(model.ScopeCompetitionsRead),
+	)
+	meEndpoints(
+		app.Group(
+			"/me",
+			middleware.APIKeyOrJWTMiddleware(secretKey, apiKeys, sessions),
+			rateLimit,
+			middleware.RequireScopes(model.ScopeCompetitionsRead),
+		),
+		&control.Favorites,
+		&control.Webhooks,
+		&control.Calendar,
+	)
+	liveEndpoints(
+		app.Group(
+			"/partidas",
+			middleware.JWTOrQueryTokenMiddleware(secretKey, sessions),
+			rateLimit,
+			middleware.RequireScopes(model.ScopeCompetitionsRead),
+		),
+		&control.Live,
+	)
+	socketEndpoints(
+		app.Group(
+			"/ws",
+			middleware.JWTOrQueryTokenMiddleware(secretKey, sessions),
+			rateLimit,
+			middleware.RequireScopes(model.ScopeCompetitionsRead),
+		),
+		&control.Socket,
+	)
+	graphQLEndpoints(
+		app.Group(
+			"/graphql",
+			jwt,
+			rateLimit,
+			middleware.RequireScopes(model.ScopeCompetitionsRead),
+		),
+		&control.GraphQL,
+	)
+	adminEndpoints(
+		app.Group(
+			"/admin",
+			jwt,
+			middleware.RequireRole(model.RoleAdmin),
+			middleware.RequirePlatform(),
+		),
+		&control.Audit,
+		&control.Organizations,
+	)
+	docsEndpoints(
+		app,
+		&control.Docs,
+	)
 }
 
-func authEndpoints(auth *echo.Group, control *controller.Auth, secretKey string) {
+func authEndpoints(auth *echo.Group, control *controller.Auth, jwt echo.MiddlewareFunc) {
 	auth.POST("/login", control.Authenticate)
-	auth.POST("/create", control.CreateCredentials, middleware.JWTMiddleware(secretKey))
+	auth.POST("/login/2fa", control.AuthenticateTwoFactor)
+	auth.POST(
+		"/create",
+		control.CreateCredentials,
+		jwt,
+		middleware.RequireRole(model.RoleAdmin),
+		middleware.RequireScopes(model.ScopeUsersManage),
+	)
+	auth.PATCH(
+		"/credentials/:user",
+		control.UpdateCredentials,
+		jwt,
+		middleware.RequireRole(model.RoleAdmin),
+		middleware.RequireScopes(model.ScopeUsersManage),
+	)
+	auth.DELETE(
+		"/credentials/:user",
+		control.DeleteCredentials,
+		jwt,
+		middleware.RequireRole(model.RoleAdmin),
+		middleware.RequireScopes(model.ScopeUsersManage),
+	)
+	auth.POST("/refresh", control.RefreshToken, jwt)
+	auth.POST("/logout", control.Logout, jwt)
+	auth.POST("/introspect", control.Introspect)
+	auth.GET("/userinfo", control.UserInfo, jwt)
+	auth.POST("/password/change", control.ChangePassword, jwt)
+	auth.POST("/password/forgot", control.ForgotPassword)
+	auth.POST("/password/reset", control.ResetPassword)
+}
+
+func oidcEndpoints(oidc *echo.Group, control *controller.OIDC) {
+	oidc.GET("/login", control.Login)
+	oidc.GET("/callback", control.Callback)
+}
+
+func twoFactorEndpoints(twoFactor *echo.Group, control *controller.Auth) {
+	twoFactor.POST("/enroll", control.EnrollTwoFactor)
+	twoFactor.POST("/activate", control.ActivateTwoFactor)
+	twoFactor.POST("/disable", control.DisableTwoFactor)
+}
+
+func apiKeyEndpoints(keys *echo.Group, control *controller.APIKeys) {
+	keys.POST("", control.CreateAPIKey)
+	keys.GET("", control.ListAPIKeys)
+	keys.DELETE("/:id", control.DeleteAPIKey)
 }
 
 func championshipEndpoints(champion *echo.Group, control *controller.Champion) {
-	champion.GET("/", control.Championship)
+	champion.GET("/", control.Championship, middleware.RequireScopes(model.ScopeCompetitionsRead))
+	champion.GET("/:id/classificacao", control.Standings, middleware.RequireScopes(model.ScopeCompetitionsRead))
+	champion.GET("/:id/artilharia", control.Scorers, middleware.RequireScopes(model.ScopeCompetitionsRead))
 	// TODO: endpoint for filters
 }
+
+func calendarEndpoints(app *echo.Echo, control *controller.Calendar, middlewares ...echo.MiddlewareFunc) {
+	app.GET("/campeonatos/:id/partidas.ics", control.CompetitionCalendar, middlewares...)
+	app.GET("/times/:id/partidas.ics", control.TeamCalendar, middlewares...)
+}
+
+func meEndpoints(me *echo.Group, favorites *controller.Favorites, webhooks *controller.Webhooks, calendar *controller.Calendar) {
+	me.GET("/favorites", favorites.ListFavorites)
+	me.PUT("/favorites/campeonatos/:id", favorites.FollowCompetition, middleware.RequireScopes(model.ScopeFavoritesWrite))
+	me.DELETE("/favorites/campeonatos/:id", favorites.UnfollowCompetition, middleware.RequireScopes(model.ScopeFavoritesWrite))
+	me.PUT("/favorites/times/:id", favorites.FollowTeam, middleware.RequireScopes(model.ScopeFavoritesWrite))
+	me.DELETE("/favorites/times/:id", favorites.UnfollowTeam, middleware.RequireScopes(model.ScopeFavoritesWrite))
+	me.GET("/feed", favorites.Feed)
+	me.POST("/webhooks", webhooks.CreateWebhook, middleware.RequireScopes(model.ScopeWebhooksWrite))
+	me.GET("/webhooks", webhooks.ListWebhooks)
+	me.DELETE("/webhooks/:id", webhooks.DeleteWebhook, middleware.RequireScopes(model.ScopeWebhooksWrite))
+	me.GET("/webhooks/:id/deliveries", webhooks.ListDeliveries)
+	me.POST("/calendar-token", calendar.CreateCalendarToken, middleware.RequireScopes(model.ScopeCalendarWrite))
+	me.DELETE("/calendar-token", calendar.DeleteCalendarToken, middleware.RequireScopes(model.ScopeCalendarWrite))
+}
+
+func liveEndpoints(live *echo.Group, control *controller.Live) {
+	live.GET("/ao-vivo/stream", control.Stream)
+}
+
+func socketEndpoints(socket *echo.Group, control *controller.Socket) {
+	socket.GET("", control.Connect)
+}
+
+func graphQLEndpoints(graphQL *echo.Group, control *controller.GraphQL) {
+	graphQL.GET("", control.Query)
+	graphQL.POST("", control.Query)
+}
+
+func adminEndpoints(admin *echo.Group, audit *controller.Audit, organizations *controller.Organizations) {
+	admin.GET("/audit", audit.ListAuditEvents, middleware.RequireScopes(model.ScopeAuditRead))
+	admin.POST(
+		"/organizations",
+		organizations.CreateOrganization,
+		middleware.RequireScopes(model.ScopeUsersManage),
+	)
+	admin.GET(
+		"/organizations",
+		organizations.ListOrganizations,
+		middleware.RequireScopes(model.ScopeUsersManage),
+	)
+}
+
+func docsEndpoints(app *echo.Echo, control *controller.Docs) {
+	app.GET("/openapi.json", control.OpenAPI)
+	app.GET("/docs", control.SwaggerUI)
+	app.GET("/docs/:asset", control.SwaggerUIAsset)
+}
@@ -0,0 +1,125 @@
+//go:build unit
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fut-app/api"
+	"github.com/fut-app/internal/controller"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+func TestSetupRoutesAreDocumented(t *testing.T) {
+	is := require.New(t)
+
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	is.Nil(json.Unmarshal(api.OpenAPI, &spec))
+
+	app := echo.New()
+	Setup(app, &controller.Controller{}, "secret", nil, nil, nil, middleware.RateLimit(middleware.RateLimitPolicy{}, nil), middleware.RateLimit(middleware.RateLimitPolicy{}, nil))
+
+	routes := app.Routes()
+	is.NotEmpty(routes)
+	for _, route := range routes {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		operations, ok := spec.Paths[path]
+		is.Truef(ok, "route %s %s is missing from api/openapi.json", route.Method, route.Path)
+
+		_, ok = operations[strings.ToLower(route.Method)]
+		is.Truef(ok, "operation %s %s is missing from api/openapi.json", route.Method, route.Path)
+	}
+}
+
+type fakeCalendarTokens struct{}
+
+func (fakeCalendarTokens) AuthenticateCalendarToken(context.Context, string) (*model.CalendarToken, error) {
+	return nil, errors.New("calendar token not found")
+}
+
+func TestSetupCalendarRoutesAcceptCalendarTokens(t *testing.T) {
+	is := require.New(t)
+
+	app := echo.New()
+	Setup(app, &controller.Controller{}, "secret", nil, nil, fakeCalendarTokens{}, middleware.RateLimit(middleware.RateLimitPolicy{}, nil), middleware.RateLimit(middleware.RateLimitPolicy{}, nil))
+
+	for _, target := range []string{"/campeonatos/campeonato_2021/partidas.ics", "/times/time_057/partidas.ics"} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target+"?token=cal_unknown", nil))
+
+		is.Equal(http.StatusUnauthorized, rec.Code, target)
+		is.Contains(rec.Body.String(), "invalid calendar token", target)
+	}
+}
+
+func TestSetupCredentialRoutesRequireAdmins(t *testing.T) {
+	is := require.New(t)
+
+	app := echo.New()
+	Setup(app, &controller.Controller{}, "secret", nil, nil, nil, middleware.RateLimit(middleware.RateLimitPolicy{}, nil), middleware.RateLimit(middleware.RateLimitPolicy{}, nil))
+
+	credential := model.Credential{User: "fan", Role: model.RoleUser}
+	token, err := credential.GenerateToken("secret", model.Scopes{model.ScopeUsersManage})
+	is.Nil(err)
+
+	for _, route := range []struct{ method, target string }{
+		{http.MethodPost, "/auth/create"},
+		{http.MethodPatch, "/auth/credentials/other"},
+		{http.MethodDelete, "/auth/credentials/other"},
+	} {
+		req := httptest.NewRequest(route.method, route.target, strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		is.Equal(http.StatusForbidden, rec.Code, route.target)
+		is.Contains(rec.Body.String(), "insufficient role", route.target)
+	}
+}
+
+func TestSetupMeWritesRequireWriteScopes(t *testing.T) {
+	is := require.New(t)
+
+	app := echo.New()
+	Setup(app, &controller.Controller{}, "secret", nil, nil, nil, middleware.RateLimit(middleware.RateLimitPolicy{}, nil), middleware.RateLimit(middleware.RateLimitPolicy{}, nil))
+
+	credential := model.Credential{User: "fan", Role: model.RoleUser}
+	token, err := credential.GenerateToken("secret", model.Scopes{model.ScopeCompetitionsRead})
+	is.Nil(err)
+
+	for _, route := range []struct{ method, target, scope string }{
+		{http.MethodPut, "/me/favorites/campeonatos/campeonato_2021", model.ScopeFavoritesWrite},
+		{http.MethodDelete, "/me/favorites/campeonatos/campeonato_2021", model.ScopeFavoritesWrite},
+		{http.MethodPut, "/me/favorites/times/time_057", model.ScopeFavoritesWrite},
+		{http.MethodDelete, "/me/favorites/times/time_057", model.ScopeFavoritesWrite},
+		{http.MethodPost, "/me/webhooks", model.ScopeWebhooksWrite},
+		{http.MethodDelete, "/me/webhooks/1", model.ScopeWebhooksWrite},
+		{http.MethodPost, "/me/calendar-token", model.ScopeCalendarWrite},
+		{http.MethodDelete, "/me/calendar-token", model.ScopeCalendarWrite},
+	} {
+		req := httptest.NewRequest(route.method, route.target, nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		is.Equal(http.StatusForbidden, rec.Code, route.target)
+		is.Contains(rec.Body.String(), "requires "+route.scope, route.target)
+	}
+}
@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	authv1 "github.com/fut-app/api/proto/auth/v1"
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/hasher"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthServer implements authv1.AuthServiceServer with the same checks as
+// the /auth HTTP endpoints.
+type AuthServer struct {
+	authv1.UnimplementedAuthServiceServer
+
+	SignIn    service.Login
+	Service   service.CredentialsRepository
+	Audit     service.AuditRepository
+	SecretKey string
+	Config    config.AuthConfig
+	Logger    *slog.Logger
+}
+
+func NewAuthServer(
+	db service.CredentialsDatabase,
+	audit service.AuditRepository,
+	secretKey string,
+	cfg config.AuthConfig,
+	passwords hasher.Hasher,
+	logger *slog.Logger,
+) AuthServer {
+	return AuthServer{
+		SignIn:    service.NewLogin(&db, secretKey, cfg, passwords, logger),
+		Service:   &db,
+		Audit:     audit,
+		SecretKey: secretKey,
+		Config:    cfg,
+		Logger:    logger,
+	}
+}
+
+func (a AuthServer) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	authRequest := model.AuthRequest{
+		User:     req.GetUser(),
+		Password: req.GetPassword(),
+		Scope:    req.GetScope(),
+	}
+	if err := authRequest.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := a.SignIn.Password(ctx, authRequest)
+	return a.loginResponse(ctx, req.GetUser(), result, err)
+}
+
+func (a AuthServer) LoginTwoFactor(ctx context.Context, req *authv1.LoginTwoFactorRequest) (*authv1.LoginResponse, error) {
+	twoFactorRequest := model.TwoFactorLoginRequest{
+		ChallengeToken: req.GetChallengeToken(),
+		Code:           req.GetCode(),
+		RecoveryCode:   req.GetRecoveryCode(),
+	}
+	if err := twoFactorRequest.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := a.SignIn.TwoFactor(ctx, twoFactorRequest)
+	return a.loginResponse(ctx, "", result, err)
+}
+
+// loginResponse maps the outcome of a login step to its gRPC answer and
+// audit event, as the HTTP controller does. user names the target until a
+// credential is found.
+func (a AuthServer) loginResponse(ctx context.Context, user string, result service.LoginResult, err error) (*authv1.LoginResponse, error) {
+	if result.Credential != nil {
+		user = result.Credential.User
+	}
+
+	var scopeErr *service.ScopeError
+	switch {
+	case errors.Is(err, service.ErrInvalidPassword):
+		a.recordAudit(ctx, model.AuditLoginFailure, user, false, "invalid credentials")
+		return nil, status.Error(codes.Unauthenticated, service.ErrInvalidPassword.Error())
+	case errors.As(err, &scopeErr):
+		return nil, status.Error(codes.InvalidArgument, "invalid_scope: "+scopeErr.Error())
+	case errors.Is(err, service.ErrInvalidChallenge):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrTwoFactorLocked):
+		a.recordAudit(ctx, model.AuditLoginFailure, user, false, err.Error())
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		a.recordAudit(ctx, model.AuditLoginFailure, user, false, err.Error())
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, service.ErrTokenFailed.Error())
+	}
+
+	if result.AccessToken == "" {
+		return &authv1.LoginResponse{
+			MfaRequired:        result.Challenge.MFARequired,
+			ChallengeToken:     result.Challenge.ChallengeToken,
+			EnrollmentRequired: result.Challenge.EnrollmentRequired,
+			EnrollmentToken:    result.Challenge.EnrollmentToken,
+		}, nil
+	}
+	a.recordAudit(ctx, model.AuditLoginSuccess, user, true, "scope="+result.Scopes.String())
+	return &authv1.LoginResponse{AccessToken: result.AccessToken, Scope: result.Scopes.String()}, nil
+}
+
+// Refresh issues a new token for the caller, granting the scopes of the
+// current one that the credential still holds, until the session reaches
+// Config.MaxSessionAge.
+func (a AuthServer) Refresh(ctx context.Context, _ *authv1.RefreshRequest) (*authv1.RefreshResponse, error) {
+	caller, _ := CallerFrom(ctx)
+	credential, err := a.Service.FindCredentials(ctx, &model.Credential{User: caller.User})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unknown user")
+	}
+
+	if len(caller.Scopes) == 0 {
+		return nil, status.Error(codes.PermissionDenied, "token cannot be refreshed")
+	}
+	if time.Since(caller.AuthTime) > a.Config.MaxSessionAge {
+		a.recordAudit(ctx, model.AuditTokenRefresh, credential.User, false, "session expired")
+		return nil, status.Error(codes.Unauthenticated, "session expired, sign in again")
+	}
+	scopes, err := credential.GrantScopes(caller.Scopes)
+	if err != nil {
+		a.recordAudit(ctx, model.AuditTokenRefresh, credential.User, false, err.Error())
+		return nil, status.Error(codes.InvalidArgument, "invalid_scope: "+err.Error())
+	}
+
+	token, err := credential.GenerateRefreshedToken(a.SecretKey, scopes, caller.AuthTime)
+	if err != nil {
+		a.Logger.ErrorContext(ctx, "failed to generate token", "error", err)
+		return nil, status.Error(codes.Internal, "something is wrong with provided credentials")
+	}
+
+	a.recordAudit(ctx, model.AuditTokenRefresh, credential.User, true, "scope="+scopes.String())
+	return &authv1.RefreshResponse{AccessToken: token, Scope: scopes.String()}, nil
+}
+
+// Validate reports whether token is an active access token: correctly
+// signed, unexpired, not a challenge token and issued after the last
+// revocation of its user. Like /auth/introspect, callers authenticate as one
+// of the introspection clients, with Basic credentials in the authorization
+// metadata.
+func (a AuthServer) Validate(ctx context.Context, req *authv1.ValidateRequest) (*authv1.ValidateResponse, error) {
+	id, secret := clientCredentials(ctx)
+	if !model.IntrospectionClientAllowed(a.Config.IntrospectionClients, id, secret) {
+		return nil, status.Error(codes.Unauthenticated, "invalid_client")
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	inactive := &authv1.ValidateResponse{Active: false}
+
+	claims, err := middleware.VerifyToken(req.GetToken(), a.SecretKey)
+	if err != nil {
+		return inactive, nil
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != "" {
+		return inactive, nil
+	}
+	user, _ := claims["username"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if user == "" || err != nil || issuedAt == nil {
+		return inactive, nil
+	}
+
+	credential, err := a.Service.FindCredentials(ctx, &model.Credential{User: user})
+	if err != nil || credential.SessionRevoked(issuedAt.Time) {
+		return inactive, nil
+	}
+
+	scope, _ := claims["scope"].(string)
+	role, _ := claims["role"].(string)
+	orgID, _ := claims["org_id"].(float64)
+	response := &authv1.ValidateResponse{
+		Active:   true,
+		Username: user,
+		Scope:    scope,
+		Role:     role,
+		OrgId:    int64(orgID),
+		IssuedAt: timestamppb.New(issuedAt.Time),
+	}
+	if expiresAt, _ := claims.GetExpirationTime(); expiresAt != nil {
+		response.ExpiresAt = timestamppb.New(expiresAt.Time)
+	}
+	return response, nil
+}
+
+func (a AuthServer) recordAudit(ctx context.Context, event string, target string, success bool, detail string) {
+	if a.Audit == nil {
+		return
+	}
+	caller, _ := CallerFrom(ctx)
+	auditEvent := &model.AuditEvent{
+		Event:   event,
+		Actor:   caller.User,
+		Target:  target,
+		Success: success,
+		Detail:  detail,
+	}
+	auditEvent.IP = peerIP(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+			auditEvent.UserAgent = userAgent[0]
+		}
+	}
+	_ = a.Audit.RecordAuditEvent(ctx, auditEvent)
+}
+
+// peerIP returns the address of the client, without its port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	address := p.Addr.String()
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+// clientCredentials reads the Basic client_id:client_secret pair of the
+// authorization metadata.
+func clientCredentials(ctx context.Context) (string, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationMetadata)
+	if len(values) != 1 {
+		return "", ""
+	}
+	encoded, ok := strings.CutPrefix(values[0], "Basic ")
+	if !ok {
+		return "", ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ""
+	}
+	id, secret, _ := strings.Cut(string(decoded), ":")
+	return id, secret
+}
@@ -0,0 +1,253 @@
+//go:build unit
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authv1 "github.com/fut-app/api/proto/auth/v1"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/totp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogin(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+
+	tests := []struct {
+		name               string
+		req                *authv1.LoginRequest
+		expectedCode       codes.Code
+		expectedScope      string
+		expectedEnrollment bool
+	}{
+		{
+			name:          "when the credentials are valid, should issue a token",
+			req:           &authv1.LoginRequest{User: "torcedor", Password: testPassword},
+			expectedCode:  codes.OK,
+			expectedScope: model.ScopeCompetitionsRead,
+		},
+		{
+			name:         "when the password is wrong, should return unauthenticated",
+			req:          &authv1.LoginRequest{User: "torcedor", Password: "Xpto-654321"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "when the user is missing, should return invalid argument",
+			req:          &authv1.LoginRequest{Password: testPassword},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "when a scope is not allowed, should return invalid argument",
+			req:          &authv1.LoginRequest{User: "torcedor", Password: testPassword, Scope: model.ScopeAuditRead},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:               "when an admin has no two-factor, should require enrollment",
+			req:                &authv1.LoginRequest{User: "admin", Password: testPassword},
+			expectedCode:       codes.OK,
+			expectedEnrollment: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := server.auth.Login(context.Background(), test.req)
+			is.Equal(test.expectedCode, status.Code(err))
+			if test.expectedCode != codes.OK {
+				return
+			}
+
+			is.Equal(test.expectedEnrollment, res.GetEnrollmentRequired())
+			if test.expectedEnrollment {
+				is.Empty(res.GetAccessToken())
+				is.NotEmpty(res.GetEnrollmentToken())
+				return
+			}
+			claims, err := middleware.VerifyToken(res.GetAccessToken(), testSecretKey)
+			is.Nil(err)
+			is.Equal(test.req.GetUser(), claims["username"])
+			is.Equal(test.expectedScope, claims["scope"])
+			is.Equal(test.expectedScope, res.GetScope())
+		})
+	}
+
+	t.Run("when logging in, should record the audit events", func(t *testing.T) {
+		page, err := server.audit.ListAuditEvents(context.Background(), model.AuditFilter{Target: "torcedor", PerPage: 10})
+		is.Nil(err)
+
+		var events []string
+		for _, event := range page.Events {
+			events = append(events, event.Event)
+		}
+		is.ElementsMatch([]string{model.AuditLoginSuccess, model.AuditLoginFailure}, events)
+	})
+}
+
+func TestLoginRehashesOutdatedPasswords(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+
+	outdated := &hasher.Argon2id{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	credential, err := (&model.AuthRequest{User: "legacy", Password: testPassword}).ParseAuthRequestToCredential(outdated)
+	is.Nil(err)
+	is.Nil(server.credentials.CreateCredentials(context.Background(), credential))
+
+	server.login(t, "legacy")
+
+	stored, err := server.credentials.FindCredentials(context.Background(), &model.Credential{User: "legacy"})
+	is.Nil(err)
+	is.False(testHasher.NeedsRehash(stored.EncryptedPassword))
+	is.True(stored.CheckPassword(testPassword))
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+
+	secret, err := totp.GenerateSecret()
+	is.Nil(err)
+	credential, err := server.credentials.FindCredentials(context.Background(), &model.Credential{User: "torcedor"})
+	is.Nil(err)
+	credential.TOTPSecret = secret
+	credential.TOTPEnabled = true
+	is.Nil(server.credentials.UpdateCredentials(context.Background(), credential, "totp_secret", "totp_enabled"))
+
+	// challenge runs the first login step.
+	challenge := func() string {
+		res, err := server.auth.Login(context.Background(), &authv1.LoginRequest{User: "torcedor", Password: testPassword})
+		is.Nil(err)
+		is.True(res.GetMfaRequired())
+		is.Empty(res.GetAccessToken())
+		return res.GetChallengeToken()
+	}
+
+	var redeemed string
+	t.Run("when the code is valid, should issue a token with the scopes of the challenge", func(t *testing.T) {
+		redeemed = challenge()
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		is.Nil(err)
+
+		res, err := server.auth.LoginTwoFactor(context.Background(), &authv1.LoginTwoFactorRequest{ChallengeToken: redeemed, Code: code})
+		is.Nil(err)
+		is.Equal(model.ScopeCompetitionsRead, res.GetScope())
+		claims, err := middleware.VerifyToken(res.GetAccessToken(), testSecretKey)
+		is.Nil(err)
+		is.Equal("torcedor", claims["username"])
+	})
+
+	t.Run("when the challenge was redeemed, should return unauthenticated", func(t *testing.T) {
+		code, err := totp.Code(secret, totp.Step(time.Now())+1)
+		is.Nil(err)
+		_, err = server.auth.LoginTwoFactor(context.Background(), &authv1.LoginTwoFactorRequest{ChallengeToken: redeemed, Code: code})
+		is.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("when both code and recovery code are sent, should return invalid argument", func(t *testing.T) {
+		_, err := server.auth.LoginTwoFactor(context.Background(), &authv1.LoginTwoFactorRequest{ChallengeToken: challenge(), Code: "000000", RecoveryCode: "xpto"})
+		is.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("when codes keep failing, should lock the credential", func(t *testing.T) {
+		token := challenge()
+		for range 2 {
+			_, err := server.auth.LoginTwoFactor(context.Background(), &authv1.LoginTwoFactorRequest{ChallengeToken: token, Code: "000000"})
+			is.Equal(codes.Unauthenticated, status.Code(err))
+		}
+		_, err := server.auth.LoginTwoFactor(context.Background(), &authv1.LoginTwoFactorRequest{ChallengeToken: token, Code: "000000"})
+		is.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+
+	t.Run("when the token is valid, should issue a new one with its scopes", func(t *testing.T) {
+		res, err := server.auth.Refresh(withToken(server.login(t, "torcedor")), &authv1.RefreshRequest{})
+		is.Nil(err)
+		is.Equal(model.ScopeCompetitionsRead, res.GetScope())
+
+		claims, err := middleware.VerifyToken(res.GetAccessToken(), testSecretKey)
+		is.Nil(err)
+		is.Equal("torcedor", claims["username"])
+	})
+
+	t.Run("when the session is older than the maximum age, should return unauthenticated", func(t *testing.T) {
+		credential, err := server.credentials.FindCredentials(context.Background(), &model.Credential{User: "torcedor"})
+		is.Nil(err)
+		token, err := credential.GenerateRefreshedToken(testSecretKey, model.DefaultScopes, time.Now().Add(-2*time.Hour))
+		is.Nil(err)
+
+		_, err = server.auth.Refresh(withToken(token), &authv1.RefreshRequest{})
+		is.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("when the token is missing, should return unauthenticated", func(t *testing.T) {
+		_, err := server.auth.Refresh(context.Background(), &authv1.RefreshRequest{})
+		is.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("when the token is an enrollment token, should return permission denied", func(t *testing.T) {
+		login, err := server.auth.Login(context.Background(), &authv1.LoginRequest{User: "admin", Password: testPassword})
+		is.Nil(err)
+
+		_, err = server.auth.Refresh(withToken(login.GetEnrollmentToken()), &authv1.RefreshRequest{})
+		is.Equal(codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestValidate(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+	token := server.login(t, "torcedor")
+
+	challenge, err := (&model.Credential{User: "torcedor"}).GenerateChallengeToken(testSecretKey, nil, time.Minute)
+	is.Nil(err)
+
+	t.Run("when the token is active, should return its claims", func(t *testing.T) {
+		res, err := server.auth.Validate(withClient(testClient), &authv1.ValidateRequest{Token: token})
+		is.Nil(err)
+		is.True(res.GetActive())
+		is.Equal("torcedor", res.GetUsername())
+		is.Equal(model.ScopeCompetitionsRead, res.GetScope())
+		is.Equal(model.RoleUser, res.GetRole())
+		is.Equal(time.Hour, res.GetExpiresAt().AsTime().Sub(res.GetIssuedAt().AsTime()))
+	})
+
+	for name, inactive := range map[string]string{
+		"when the token is malformed, should report it inactive":      "not-a-token",
+		"when the token is a challenge, should report it inactive":    challenge,
+		"when the token was tampered with, should report it inactive": token + "x",
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := server.auth.Validate(withClient(testClient), &authv1.ValidateRequest{Token: inactive})
+			is.Nil(err)
+			is.False(res.GetActive())
+			is.Empty(res.GetUsername())
+		})
+	}
+
+	t.Run("when the token is missing, should return invalid argument", func(t *testing.T) {
+		_, err := server.auth.Validate(withClient(testClient), &authv1.ValidateRequest{})
+		is.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	for name, ctx := range map[string]context.Context{
+		"when the client credentials are missing, should return unauthenticated": context.Background(),
+		"when the client secret is wrong, should return unauthenticated":         withClient("billing:wrong"),
+		"when a bearer token is sent instead, should return unauthenticated":     withToken(token),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := server.auth.Validate(ctx, &authv1.ValidateRequest{Token: token})
+			is.Equal(codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
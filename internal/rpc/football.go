@@ -0,0 +1,189 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	footballv1 "github.com/fut-app/api/proto/football/v1"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FootballServer implements footballv1.FootballServiceServer on top of the
+// football app, like the /campeonatos HTTP endpoints.
+type FootballServer struct {
+	footballv1.UnimplementedFootballServiceServer
+
+	Service service.FootballAPI
+	Logger  *slog.Logger
+}
+
+func NewFootballServer(football service.FootballAPI, logger *slog.Logger) FootballServer {
+	return FootballServer{
+		Service: football,
+		Logger:  logger,
+	}
+}
+
+func (f FootballServer) ListCompetitions(ctx context.Context, _ *footballv1.ListCompetitionsRequest) (*footballv1.ListCompetitionsResponse, error) {
+	competitions, err := f.Service.CompetitionList(ctx)
+	if err != nil {
+		return nil, f.footballStatus(ctx, err, "competitions not found", "failed to list competitions")
+	}
+
+	response := &footballv1.ListCompetitionsResponse{}
+	for _, competition := range competitions.Competitions {
+		response.Competitions = append(response.Competitions, toCompetition(competition))
+	}
+	return response, nil
+}
+
+func (f FootballServer) GetStandings(ctx context.Context, req *footballv1.GetStandingsRequest) (*footballv1.GetStandingsResponse, error) {
+	competitionID, err := model.ParseCompetitionID(req.GetCompetitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	standings, err := f.Service.CompetitionStandings(ctx, competitionID)
+	if err != nil {
+		return nil, f.footballStatus(ctx, err, "competition not found", "failed to fetch standings", "competition", competitionID)
+	}
+
+	response := &footballv1.GetStandingsResponse{Season: toSeason(standings.Season)}
+	for _, standing := range standings.Standings {
+		converted := &footballv1.Standing{
+			Stage: standing.Stage,
+			Type:  standing.Type,
+			Group: standing.Group,
+		}
+		for _, row := range standing.Table {
+			converted.Table = append(converted.Table, &footballv1.TableRow{
+				Position:       int32(row.Position),
+				Team:           toTeam(row.Team),
+				PlayedGames:    int32(row.PlayedGames),
+				Won:            int32(row.Won),
+				Draw:           int32(row.Draw),
+				Lost:           int32(row.Lost),
+				Points:         int32(row.Points),
+				GoalsFor:       int32(row.GoalsFor),
+				GoalsAgainst:   int32(row.GoalsAgainst),
+				GoalDifference: int32(row.GoalDifference),
+			})
+		}
+		response.Standings = append(response.Standings, converted)
+	}
+	return response, nil
+}
+
+func (f FootballServer) ListMatches(ctx context.Context, req *footballv1.ListMatchesRequest) (*footballv1.ListMatchesResponse, error) {
+	filter := model.MatchFilter{
+		Status:   req.GetStatus(),
+		DateFrom: req.GetDateFrom(),
+		DateTo:   req.GetDateTo(),
+	}
+	for name, date := range map[string]string{"date_from": filter.DateFrom, "date_to": filter.DateTo} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%s must be a date as YYYY-MM-DD", name))
+		}
+	}
+
+	var (
+		matches *model.MatchResponse
+		err     error
+	)
+	if req.GetCompetitionId() == "" {
+		matches, err = f.Service.Matches(ctx, filter)
+	} else {
+		competitionID, parseErr := model.ParseCompetitionID(req.GetCompetitionId())
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, parseErr.Error())
+		}
+		matches, err = f.Service.CompetitionMatches(ctx, competitionID, filter)
+	}
+	if err != nil {
+		return nil, f.footballStatus(ctx, err, "competition not found", "failed to fetch matches", "competition", req.GetCompetitionId())
+	}
+
+	response := &footballv1.ListMatchesResponse{}
+	for _, match := range matches.Matches {
+		converted := &footballv1.Match{
+			Id:       int64(match.ID),
+			UtcDate:  timestamppb.New(match.UTCDate),
+			Status:   match.Status,
+			Matchday: optionalInt32(match.Matchday),
+			Stage:    match.Stage,
+			HomeTeam: toTeam(match.HomeTeam),
+			AwayTeam: toTeam(match.AwayTeam),
+			Score: &footballv1.Score{
+				Winner:   match.Score.Winner,
+				FullTime: toScoreSet(match.Score.FullTime),
+				HalfTime: toScoreSet(match.Score.HalfTime),
+			},
+		}
+		if match.Competition.ID != 0 {
+			converted.CompetitionId = model.CompetitionID(match.Competition.ID)
+		}
+		response.Matches = append(response.Matches, converted)
+	}
+	return response, nil
+}
+
+// footballStatus maps football app errors to gRPC codes: unknown ids are
+// not found and every other failure, logged with msg and args, is
+// unavailable, as the app is remote.
+func (f FootballServer) footballStatus(ctx context.Context, err error, notFound string, msg string, args ...any) error {
+	if errors.Is(err, service.ErrFootballNotFound) {
+		return status.Error(codes.NotFound, notFound)
+	}
+	f.Logger.ErrorContext(ctx, msg, append(args, "error", err)...)
+	return status.Error(codes.Unavailable, "football app is unavailable")
+}
+
+func toCompetition(competition model.Competition) *footballv1.Competition {
+	return &footballv1.Competition{
+		Id:            model.CompetitionID(competition.ID),
+		Name:          competition.Name,
+		Code:          competition.Code,
+		CurrentSeason: toSeason(competition.CurrentSeason),
+	}
+}
+
+func toSeason(season model.Season) *footballv1.Season {
+	return &footballv1.Season{
+		Id:              int32(season.ID),
+		StartDate:       season.StartDate,
+		EndDate:         season.EndDate,
+		CurrentMatchday: optionalInt32(season.CurrentMatchday),
+	}
+}
+
+func toTeam(team model.Team) *footballv1.Team {
+	return &footballv1.Team{
+		Id:        model.TeamID(team.ID),
+		Name:      team.Name,
+		ShortName: team.ShortName,
+		Tla:       team.TLA,
+		Crest:     team.Crest,
+	}
+}
+
+func toScoreSet(score model.ScoreSet) *footballv1.ScoreSet {
+	return &footballv1.ScoreSet{
+		Home: optionalInt32(score.Home),
+		Away: optionalInt32(score.Away),
+	}
+}
+
+func optionalInt32(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	converted := int32(*value)
+	return &converted
+}
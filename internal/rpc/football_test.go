@@ -0,0 +1,235 @@
+//go:build unit
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	footballv1 "github.com/fut-app/api/proto/football/v1"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeFootball serves the Premier League and fails for any other
+// competition. It records the last match filter it received.
+type fakeFootball struct {
+	filter      model.MatchFilter
+	unavailable bool
+}
+
+var (
+	arsenal = model.Team{ID: 57, Name: "Arsenal FC", ShortName: "Arsenal", TLA: "ARS"}
+	chelsea = model.Team{ID: 61, Name: "Chelsea FC", ShortName: "Chelsea", TLA: "CHE"}
+	premier = model.Competition{
+		ID:            2021,
+		Name:          "Premier League",
+		Code:          "PL",
+		CurrentSeason: model.Season{ID: 2403, StartDate: "2025-08-15", EndDate: "2026-05-24"},
+	}
+)
+
+func (f *fakeFootball) CompetitionList(context.Context) (*model.CompetitionResponse, error) {
+	if f.unavailable {
+		return nil, errors.New("connection refused")
+	}
+	return &model.CompetitionResponse{Competitions: []model.Competition{premier}}, nil
+}
+
+func (f *fakeFootball) CompetitionMatches(_ context.Context, competitionID int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	if competitionID != premier.ID {
+		return nil, service.ErrFootballNotFound
+	}
+	return f.Matches(context.Background(), filter)
+}
+
+func (f *fakeFootball) CompetitionStandings(_ context.Context, competitionID int) (*model.StandingsResponse, error) {
+	if competitionID != premier.ID {
+		return nil, service.ErrFootballNotFound
+	}
+	return &model.StandingsResponse{
+		Competition: premier,
+		Season:      premier.CurrentSeason,
+		Standings: []model.Standing{
+			{Stage: "REGULAR_SEASON", Type: "TOTAL", Table: []model.TableRow{
+				{Position: 1, Team: arsenal, PlayedGames: 8, Won: 6, Draw: 2, Points: 20, GoalsFor: 15, GoalsAgainst: 3, GoalDifference: 12},
+				{Position: 2, Team: chelsea, PlayedGames: 8, Won: 5, Lost: 3, Points: 15, GoalsFor: 11, GoalsAgainst: 9, GoalDifference: 2},
+			}},
+		},
+	}, nil
+}
+
+func (f *fakeFootball) CompetitionScorers(context.Context, int) (*model.ScorersResponse, error) {
+	return &model.ScorersResponse{}, nil
+}
+
+func (f *fakeFootball) Team(context.Context, int) (*model.Team, error) {
+	return nil, service.ErrFootballNotFound
+}
+
+func (f *fakeFootball) TeamMatches(context.Context, int, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{}, nil
+}
+
+func (f *fakeFootball) Matches(_ context.Context, filter model.MatchFilter) (*model.MatchResponse, error) {
+	f.filter = filter
+	two, one, matchday := 2, 1, 9
+	return &model.MatchResponse{Matches: []model.Match{
+		{
+			ID:          537,
+			UTCDate:     time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC),
+			Status:      model.MatchFinished,
+			Matchday:    &matchday,
+			Stage:       "REGULAR_SEASON",
+			Competition: premier,
+			HomeTeam:    arsenal,
+			AwayTeam:    chelsea,
+			Score:       model.Score{FullTime: model.ScoreSet{Home: &two, Away: &one}},
+		},
+	}}, nil
+}
+
+// asJSON renders message as JSON to compare it with the expected payload.
+func asJSON(t *testing.T, message proto.Message) string {
+	t.Helper()
+	out, err := protojson.Marshal(message)
+	require.Nil(t, err)
+	return string(out)
+}
+
+func TestListCompetitions(t *testing.T) {
+	is := require.New(t)
+	football := &fakeFootball{}
+	server := newTestServer(t, football)
+	ctx := withToken(server.login(t, "torcedor"))
+
+	t.Run("when the football app answers, should list the competitions", func(t *testing.T) {
+		res, err := server.football.ListCompetitions(ctx, &footballv1.ListCompetitionsRequest{})
+		is.Nil(err)
+		is.JSONEq(`{"competitions":[{
+			"id":"campeonato_2021",
+			"name":"Premier League",
+			"code":"PL",
+			"currentSeason":{"id":2403,"startDate":"2025-08-15","endDate":"2026-05-24"}
+		}]}`, asJSON(t, res))
+	})
+
+	t.Run("when the football app fails, should return unavailable", func(t *testing.T) {
+		football.unavailable = true
+		_, err := server.football.ListCompetitions(ctx, &footballv1.ListCompetitionsRequest{})
+		is.Equal(codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestGetStandings(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+	ctx := withToken(server.login(t, "torcedor"))
+
+	t.Run("when the competition exists, should return its tables", func(t *testing.T) {
+		res, err := server.football.GetStandings(ctx, &footballv1.GetStandingsRequest{CompetitionId: "campeonato_2021"})
+		is.Nil(err)
+		is.JSONEq(`{
+			"season":{"id":2403,"startDate":"2025-08-15","endDate":"2026-05-24"},
+			"standings":[{"stage":"REGULAR_SEASON","type":"TOTAL","table":[
+				{"position":1,"team":{"id":"time_057","name":"Arsenal FC","shortName":"Arsenal","tla":"ARS"},"playedGames":8,"won":6,"draw":2,"points":20,"goalsFor":15,"goalsAgainst":3,"goalDifference":12},
+				{"position":2,"team":{"id":"time_061","name":"Chelsea FC","shortName":"Chelsea","tla":"CHE"},"playedGames":8,"won":5,"lost":3,"points":15,"goalsFor":11,"goalsAgainst":9,"goalDifference":2}
+			]}]
+		}`, asJSON(t, res))
+	})
+
+	tests := []struct {
+		name          string
+		competitionID string
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "when the id is malformed, should return invalid argument",
+			competitionID: "2021",
+			expectedCode:  codes.InvalidArgument,
+		},
+		{
+			name:          "when the competition is unknown, should return not found",
+			competitionID: "campeonato_2013",
+			expectedCode:  codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := server.football.GetStandings(ctx, &footballv1.GetStandingsRequest{CompetitionId: test.competitionID})
+			is.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestListMatches(t *testing.T) {
+	is := require.New(t)
+	football := &fakeFootball{}
+	server := newTestServer(t, football)
+	ctx := withToken(server.login(t, "torcedor"))
+
+	t.Run("when filtering a competition, should pass the filter and return its matches", func(t *testing.T) {
+		res, err := server.football.ListMatches(ctx, &footballv1.ListMatchesRequest{
+			CompetitionId: "campeonato_2021",
+			Status:        model.MatchFinished,
+			DateFrom:      "2026-10-01",
+			DateTo:        "2026-10-31",
+		})
+		is.Nil(err)
+		is.Equal(model.MatchFilter{Status: model.MatchFinished, DateFrom: "2026-10-01", DateTo: "2026-10-31"}, football.filter)
+		is.JSONEq(`{"matches":[{
+			"id":"537",
+			"competitionId":"campeonato_2021",
+			"utcDate":"2026-10-18T14:00:00Z",
+			"status":"FINISHED",
+			"matchday":9,
+			"stage":"REGULAR_SEASON",
+			"homeTeam":{"id":"time_057","name":"Arsenal FC","shortName":"Arsenal","tla":"ARS"},
+			"awayTeam":{"id":"time_061","name":"Chelsea FC","shortName":"Chelsea","tla":"CHE"},
+			"score":{"fullTime":{"home":2,"away":1},"halfTime":{}}
+		}]}`, asJSON(t, res))
+	})
+
+	t.Run("when no competition is given, should list every competition", func(t *testing.T) {
+		res, err := server.football.ListMatches(ctx, &footballv1.ListMatchesRequest{})
+		is.Nil(err)
+		is.Len(res.GetMatches(), 1)
+	})
+
+	tests := []struct {
+		name         string
+		req          *footballv1.ListMatchesRequest
+		expectedCode codes.Code
+	}{
+		{
+			name:         "when a date is malformed, should return invalid argument",
+			req:          &footballv1.ListMatchesRequest{DateFrom: "18/10/2026"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "when the id is malformed, should return invalid argument",
+			req:          &footballv1.ListMatchesRequest{CompetitionId: "premier"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "when the competition is unknown, should return not found",
+			req:          &footballv1.ListMatchesRequest{CompetitionId: "campeonato_2013"},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := server.football.ListMatches(ctx, test.req)
+			is.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/logger"
+	"github.com/fut-app/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationMetadata carries the Bearer token, like the Authorization
+// header of the HTTP API.
+const AuthorizationMetadata = "authorization"
+
+// Caller is the user authenticated by the interceptors.
+type Caller struct {
+	User   string
+	Scopes model.Scopes
+	Role   string
+	OrgID  int
+	// AuthTime is when the session of the token started.
+	AuthTime time.Time
+}
+
+type callerKey struct{}
+
+// CallerFrom returns the caller of an authenticated call.
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// Authenticator checks the Bearer token of incoming calls as
+// middleware.JWTMiddleware does for HTTP requests. Sessions may be nil to
+// skip the revocation check.
+type Authenticator struct {
+	SecretKey string
+	Sessions  middleware.SessionValidator
+	// Public lists the full method names callable without a token.
+	Public map[string]bool
+	// Scopes lists the scopes each full method requires.
+	Scopes map[string]model.Scopes
+}
+
+// Unary authenticates unary calls.
+func (a Authenticator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream authenticates streaming calls.
+func (a Authenticator) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	if a.Public[method] {
+		return ctx, nil
+	}
+
+	caller, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if missing := a.Scopes[method].Unknown(caller.Scopes); len(missing) > 0 {
+		return nil, status.Error(codes.PermissionDenied, "insufficient scope: requires "+missing.String())
+	}
+
+	ctx = context.WithValue(ctx, callerKey{}, caller)
+	ctx = logger.AppendCtx(ctx, slog.String("user", caller.User))
+	// Zero leaves platform callers unscoped, as in middleware.SetOrganization.
+	if caller.OrgID != 0 {
+		ctx = tenant.WithOrganization(ctx, caller.OrgID)
+	}
+	return ctx, nil
+}
+
+func (a Authenticator) authenticate(ctx context.Context) (Caller, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationMetadata)
+	if len(values) != 1 {
+		return Caller{}, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	claims, err := middleware.ParseAccessToken(ctx, values[0], a.SecretKey, a.Sessions)
+	if err != nil {
+		return Caller{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return Caller{
+		User:     claims.User,
+		Scopes:   claims.Scopes,
+		Role:     claims.Role,
+		OrgID:    claims.OrgID,
+		AuthTime: claims.AuthTime,
+	}, nil
+}
+
+// Recover turns handler panics into Internal errors, like the Recover
+// middleware of the HTTP server.
+func Recover(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.ErrorContext(ctx, "panic in grpc handler", "method", info.FullMethod, "panic", r)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
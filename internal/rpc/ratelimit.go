@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/fut-app/internal/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimiter throttles calls with the policies of the HTTP API: callers
+// by user and role, anonymous calls such as Login by peer IP with the /auth
+// limit. It must run after the Authenticator. Store failures let calls
+// through.
+type RateLimiter struct {
+	Callers   middleware.RateLimitPolicy
+	Anonymous middleware.RateLimitPolicy
+	Store     middleware.RateLimitStore
+}
+
+// Unary throttles unary calls.
+func (r RateLimiter) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := r.take(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream throttles the opening of streaming calls.
+func (r RateLimiter) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.take(stream.Context()); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func (r RateLimiter) take(ctx context.Context) error {
+	policy := r.Anonymous
+	caller, ok := CallerFrom(ctx)
+	if ok {
+		policy = r.Callers
+	}
+	if !policy.Enabled {
+		return nil
+	}
+
+	key, limit := policy.Subject(caller.User, caller.Role, peerIP(ctx))
+	quota, err := policy.Take(ctx, r.Store, key, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store unavailable", "error", err)
+		return nil
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(quota.Limit),
+		"ratelimit-remaining", strconv.Itoa(quota.Remaining),
+		"ratelimit-reset", strconv.Itoa(quota.Reset),
+	))
+	if quota.Exceeded {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %d seconds", quota.Reset)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package rpc serves the auth and football APIs over gRPC for other
+// backend services. Calls authenticate with the same Bearer tokens as the
+// HTTP API, sent in the authorization metadata.
+package rpc
+
+import (
+	"log/slog"
+
+	authv1 "github.com/fut-app/api/proto/auth/v1"
+	footballv1 "github.com/fut-app/api/proto/football/v1"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"google.golang.org/grpc"
+)
+
+// NewServer registers the auth and football services behind the JWT and
+// rate limit interceptors. Login and LoginTwoFactor need no token and
+// Validate authenticates introspection clients itself; football calls
+// require competitions:read, like the /campeonatos routes.
+func NewServer(
+	auth AuthServer,
+	football FootballServer,
+	secretKey string,
+	sessions middleware.SessionValidator,
+	rateLimiter RateLimiter,
+	logger *slog.Logger,
+	options ...grpc.ServerOption,
+) *grpc.Server {
+	read := model.Scopes{model.ScopeCompetitionsRead}
+	authenticator := Authenticator{
+		SecretKey: secretKey,
+		Sessions:  sessions,
+		Public: map[string]bool{
+			authv1.AuthService_Login_FullMethodName:          true,
+			authv1.AuthService_LoginTwoFactor_FullMethodName: true,
+			authv1.AuthService_Validate_FullMethodName:       true,
+		},
+		Scopes: map[string]model.Scopes{
+			footballv1.FootballService_ListCompetitions_FullMethodName: read,
+			footballv1.FootballService_GetStandings_FullMethodName:     read,
+			footballv1.FootballService_ListMatches_FullMethodName:      read,
+		},
+	}
+
+	server := grpc.NewServer(append(
+		options,
+		grpc.ChainUnaryInterceptor(Recover(logger), authenticator.Unary(), rateLimiter.Unary()),
+		grpc.ChainStreamInterceptor(authenticator.Stream(), rateLimiter.Stream()),
+	)...)
+	authv1.RegisterAuthServiceServer(server, auth)
+	footballv1.RegisterFootballServiceServer(server, football)
+	return server
+}
@@ -0,0 +1,227 @@
+//go:build unit
+
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	authv1 "github.com/fut-app/api/proto/auth/v1"
+	footballv1 "github.com/fut-app/api/proto/football/v1"
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/internal/service"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/sqlite/sqlitetest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testSecretKey = "D41D8CD98F00B204E9800998ECF8427E"
+	testPassword  = "Xpto-123456"
+	testClient    = "billing:billing-secret"
+)
+
+// testHasher uses cheap parameters to keep the tests fast.
+var testHasher = &hasher.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+type testServer struct {
+	auth        authv1.AuthServiceClient
+	football    footballv1.FootballServiceClient
+	credentials service.CredentialsDatabase
+	audit       service.AuditDatabase
+}
+
+// newTestServer serves NewServer over an in-memory listener, with a user
+// holding competitions:read, one holding audit:read and an admin.
+func newTestServer(t *testing.T, football service.FootballAPI) *testServer {
+	t.Helper()
+	return newRateLimitedTestServer(t, football, RateLimiter{})
+}
+
+func newRateLimitedTestServer(t *testing.T, football service.FootballAPI, rateLimiter RateLimiter) *testServer {
+	t.Helper()
+	is := require.New(t)
+
+	db := sqlitetest.NewDB(t, model.Models()...)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	credentials := service.NewDatabase(db, log)
+	audit := service.NewAuditDatabase(db, log)
+	cfg := config.AuthConfig{
+		RequireAdmin2FA:      true,
+		ChallengeTTL:         time.Minute,
+		MFAMaxFailures:       3,
+		MFALockout:           time.Minute,
+		MaxSessionAge:        time.Hour,
+		IntrospectionClients: []string{testClient},
+	}
+	server := NewServer(
+		NewAuthServer(credentials, &audit, testSecretKey, cfg, testHasher, log),
+		NewFootballServer(football, log),
+		testSecretKey,
+		&credentials,
+		rateLimiter,
+		log,
+	)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	is.Nil(err)
+	t.Cleanup(func() { conn.Close() })
+
+	for _, req := range []model.AuthRequest{
+		{User: "torcedor", Password: testPassword, Scope: model.ScopeCompetitionsRead},
+		{User: "auditor", Password: testPassword, Scope: model.ScopeAuditRead},
+		{User: "admin", Password: testPassword, Role: model.RoleAdmin},
+	} {
+		credential, err := req.ParseAuthRequestToCredential(testHasher)
+		is.Nil(err)
+		is.Nil(credentials.CreateCredentials(context.Background(), credential))
+	}
+
+	return &testServer{
+		auth:        authv1.NewAuthServiceClient(conn),
+		football:    footballv1.NewFootballServiceClient(conn),
+		credentials: credentials,
+		audit:       audit,
+	}
+}
+
+// login returns an access token of user.
+func (s *testServer) login(t *testing.T, user string) string {
+	t.Helper()
+	res, err := s.auth.Login(context.Background(), &authv1.LoginRequest{User: user, Password: testPassword})
+	require.Nil(t, err)
+	require.NotEmpty(t, res.GetAccessToken())
+	return res.GetAccessToken()
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, "Bearer "+token)
+}
+
+// withClient authenticates as the introspection client id:secret.
+func withClient(client string) context.Context {
+	credentials := base64.StdEncoding.EncodeToString([]byte(client))
+	return metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, "Basic "+credentials)
+}
+
+func TestAuthenticator(t *testing.T) {
+	is := require.New(t)
+	server := newTestServer(t, &fakeFootball{})
+	torcedor := server.login(t, "torcedor")
+
+	challenge, err := (&model.Credential{User: "torcedor"}).GenerateChallengeToken(testSecretKey, model.Scopes{model.ScopeCompetitionsRead}, time.Minute)
+	is.Nil(err)
+	foreign, err := (&model.Credential{User: "torcedor"}).GenerateToken("another-secret-key-with-32-bytes", model.Scopes{model.ScopeCompetitionsRead})
+	is.Nil(err)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		expectedCode codes.Code
+	}{
+		{
+			name:         "when the token is valid, should call the service",
+			ctx:          withToken(torcedor),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "when the token is missing, should return unauthenticated",
+			ctx:          context.Background(),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "when the metadata is not a bearer token, should return unauthenticated",
+			ctx:          metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, torcedor),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "when the token is signed with another key, should return unauthenticated",
+			ctx:          withToken(foreign),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "when the token is a two-factor challenge, should return unauthenticated",
+			ctx:          withToken(challenge),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "when the token lacks competitions:read, should return permission denied",
+			ctx:          withToken(server.login(t, "auditor")),
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := server.football.ListCompetitions(test.ctx, &footballv1.ListCompetitionsRequest{})
+			is.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+
+	t.Run("when the session was revoked, should return unauthenticated", func(t *testing.T) {
+		credential, err := server.credentials.FindCredentials(context.Background(), &model.Credential{User: "torcedor"})
+		is.Nil(err)
+		revokedAt := time.Now()
+		credential.SessionsRevokedAt = &revokedAt
+		is.Nil(server.credentials.UpdateCredentials(context.Background(), credential, "sessions_revoked_at"))
+
+		_, err = server.football.ListCompetitions(withToken(torcedor), &footballv1.ListCompetitionsRequest{})
+		is.Equal(codes.Unauthenticated, status.Code(err))
+		is.Equal("session revoked", status.Convert(err).Message())
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	is := require.New(t)
+	server := newRateLimitedTestServer(t, &fakeFootball{}, RateLimiter{
+		Callers:   middleware.RateLimitPolicy{Enabled: true, Window: time.Hour, Default: 2},
+		Anonymous: middleware.RateLimitPolicy{Enabled: true, Window: time.Hour, Default: 2, ByIP: true},
+		Store:     middleware.NewMemoryRateLimitStore(),
+	})
+
+	var torcedor string
+	t.Run("when anonymous calls exceed the limit of the peer, should return resource exhausted", func(t *testing.T) {
+		_, err := server.auth.Login(context.Background(), &authv1.LoginRequest{User: "torcedor", Password: "Xpto-654321"})
+		is.Equal(codes.Unauthenticated, status.Code(err))
+		torcedor = server.login(t, "torcedor")
+
+		_, err = server.auth.Login(context.Background(), &authv1.LoginRequest{User: "torcedor", Password: testPassword})
+		is.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("when authenticated calls exceed the limit of the caller, should return resource exhausted", func(t *testing.T) {
+		var header metadata.MD
+		for range 2 {
+			_, err := server.football.ListCompetitions(withToken(torcedor), &footballv1.ListCompetitionsRequest{}, grpc.Header(&header))
+			is.Nil(err)
+		}
+		is.Equal([]string{"0"}, header.Get("ratelimit-remaining"))
+
+		_, err := server.football.ListCompetitions(withToken(torcedor), &footballv1.ListCompetitionsRequest{})
+		is.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+}
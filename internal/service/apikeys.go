@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrAPIKeyExpired        = errors.New("api key expired")
+	ErrAPIKeyCreationFailed = errors.New("failed to create api key")
+	ErrAPIKeyListFailed     = errors.New("failed to list api keys")
+)
+
+type APIKeysDatabase struct {
+	Gorm   *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewAPIKeysDatabase(gorm *gorm.DB, logger *slog.Logger) APIKeysDatabase {
+	return APIKeysDatabase{
+		Gorm:   gorm,
+		Logger: logger,
+	}
+}
+
+type APIKeysRepository interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	ListAPIKeys(ctx context.Context, credentialID int) ([]model.APIKey, error)
+	DeleteAPIKey(ctx context.Context, credentialID int, id int) error
+	AuthenticateAPIKey(ctx context.Context, raw string) (*model.APIKey, error)
+}
+
+func (d *APIKeysDatabase) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	if err := d.Gorm.WithContext(ctx).Create(key).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to create api key", "error", err)
+		return ErrAPIKeyCreationFailed
+	}
+	return nil
+}
+
+func (d *APIKeysDatabase) ListAPIKeys(ctx context.Context, credentialID int) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := d.Gorm.
+		WithContext(ctx).
+		Where(&model.APIKey{CredentialID: credentialID}).
+		Order("id").
+		Find(&keys).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list api keys", "error", err)
+		return nil, ErrAPIKeyListFailed
+	}
+	return keys, nil
+}
+
+func (d *APIKeysDatabase) DeleteAPIKey(ctx context.Context, credentialID int, id int) error {
+	result := d.Gorm.
+		WithContext(ctx).
+		Where(&model.APIKey{ID: id, CredentialID: credentialID}).
+		Delete(&model.APIKey{})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to delete api key", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves a plain key to its stored record, with the
+// owning credential, and records its usage.
+func (d *APIKeysDatabase) AuthenticateAPIKey(ctx context.Context, raw string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := d.Gorm.
+		WithContext(ctx).
+		Preload("Credential").
+		Where(&model.APIKey{HashedKey: model.HashAPIKey(raw)}).
+		First(&key).Error
+	if err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	now := time.Now()
+	if key.Expired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	err = d.Gorm.
+		WithContext(ctx).
+		Model(&key).
+		UpdateColumn("last_used_at", now).Error
+	if err != nil {
+		d.Logger.WarnContext(ctx, "failed to record api key usage", "key_id", key.ID, "error", err)
+	}
+	key.LastUsedAt = &now
+
+	return &key, nil
+}
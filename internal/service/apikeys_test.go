@@ -0,0 +1,66 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeysDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := newTestDatabase(t)
+	credentials := NewDatabase(db, newTestLogger())
+	keys := NewAPIKeysDatabase(db, newTestLogger())
+
+	credential := model.Credential{User: "batch", EncryptedPassword: "x"}
+	is.Nil(credentials.CreateCredentials(ctx, &credential))
+
+	request := model.APIKeyRequest{Name: "nightly", Scopes: model.Scopes{model.ScopeCompetitionsRead}}
+	key, raw, err := request.NewAPIKey(credential.ID)
+	is.Nil(err)
+	is.Nil(keys.CreateAPIKey(ctx, key))
+	is.NotEqual(raw, key.HashedKey)
+
+	past := time.Now().Add(-time.Minute)
+	expired, expiredRaw, err := request.NewAPIKey(credential.ID)
+	is.Nil(err)
+	expired.ExpiresAt = &past
+	is.Nil(keys.CreateAPIKey(ctx, expired))
+
+	t.Run("when key is valid, should return it with owner and record usage", func(t *testing.T) {
+		authenticated, err := keys.AuthenticateAPIKey(ctx, raw)
+		is.Nil(err)
+		is.Equal("batch", authenticated.Credential.User)
+		is.Equal(model.Scopes{model.ScopeCompetitionsRead}, authenticated.Scopes)
+
+		stored, err := keys.ListAPIKeys(ctx, credential.ID)
+		is.Nil(err)
+		is.Len(stored, 2)
+		is.NotNil(stored[0].LastUsedAt)
+	})
+
+	t.Run("when key is expired, should return error", func(t *testing.T) {
+		_, err := keys.AuthenticateAPIKey(ctx, expiredRaw)
+		is.Equal(ErrAPIKeyExpired, err)
+	})
+
+	t.Run("when key is unknown, should return error", func(t *testing.T) {
+		_, err := keys.AuthenticateAPIKey(ctx, "fut_unknown")
+		is.Equal(ErrAPIKeyNotFound, err)
+	})
+
+	t.Run("when key is deleted, should not authenticate anymore", func(t *testing.T) {
+		is.Nil(keys.DeleteAPIKey(ctx, credential.ID, key.ID))
+		is.Equal(ErrAPIKeyNotFound, keys.DeleteAPIKey(ctx, credential.ID, key.ID))
+		_, err := keys.AuthenticateAPIKey(ctx, raw)
+		is.Equal(ErrAPIKeyNotFound, err)
+	})
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuditRecordFailed = errors.New("failed to record audit event")
+	ErrAuditListFailed   = errors.New("failed to list audit events")
+)
+
+type AuditDatabase struct {
+	Gorm   *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewAuditDatabase(gorm *gorm.DB, logger *slog.Logger) AuditDatabase {
+	return AuditDatabase{
+		Gorm:   gorm,
+		Logger: logger,
+	}
+}
+
+type AuditRepository interface {
+	RecordAuditEvent(ctx context.Context, event *model.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter model.AuditFilter) (*model.AuditPage, error)
+}
+
+func (d *AuditDatabase) RecordAuditEvent(ctx context.Context, event *model.AuditEvent) error {
+	if err := d.Gorm.WithContext(ctx).Create(event).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to record audit event", "event", event.Event, "target", event.Target, "error", err)
+		return ErrAuditRecordFailed
+	}
+	return nil
+}
+
+// ListAuditEvents returns the newest events first.
+func (d *AuditDatabase) ListAuditEvents(ctx context.Context, filter model.AuditFilter) (*model.AuditPage, error) {
+	query := d.Gorm.
+		WithContext(ctx).
+		Model(&model.AuditEvent{}).
+		Where(&model.AuditEvent{Event: filter.Event, Actor: filter.Actor, Target: filter.Target})
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	page := model.AuditPage{
+		Events:  []model.AuditEvent{},
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+	}
+	if err := query.Count(&page.Total).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to count audit events", "error", err)
+		return nil, ErrAuditListFailed
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.PerPage).
+		Offset((filter.Page - 1) * filter.PerPage).
+		Find(&page.Events).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list audit events", "error", err)
+		return nil, ErrAuditListFailed
+	}
+	return &page, nil
+}
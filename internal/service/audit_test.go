@@ -0,0 +1,39 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	gormDB := newTestDatabase(t)
+	db := NewAuditDatabase(gormDB, newTestLogger())
+
+	old := &model.AuditEvent{Event: model.AuditLoginSuccess, Target: "fan", Success: true, CreatedAt: time.Now().Add(-48 * time.Hour)}
+	recent := &model.AuditEvent{Event: model.AuditLoginFailure, Target: "fan"}
+	is.Nil(db.RecordAuditEvent(ctx, old))
+	is.Nil(db.RecordAuditEvent(ctx, recent))
+
+	t.Run("when filtering by date, should return only events in the range", func(t *testing.T) {
+		from := time.Now().Add(-time.Hour)
+		page, err := db.ListAuditEvents(ctx, model.AuditFilter{From: &from, Page: 1, PerPage: 10})
+		is.Nil(err)
+		is.Equal(int64(1), page.Total)
+		is.Equal(recent.ID, page.Events[0].ID)
+	})
+
+	t.Run("when updating or deleting an event, should return error", func(t *testing.T) {
+		is.ErrorIs(gormDB.Model(old).Update("success", false).Error, model.ErrAuditAppendOnly)
+		is.ErrorIs(gormDB.Delete(old).Error, model.ErrAuditAppendOnly)
+	})
+}
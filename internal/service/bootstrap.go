@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/hasher"
+	"gorm.io/gorm"
+)
+
+var ErrBootstrapAdminInOrganization = errors.New("bootstrap admin belongs to an organization")
+
+// NewPasswordPolicy builds the policy new passwords must follow.
+func NewPasswordPolicy(cfg config.PasswordPolicyConfig) model.PasswordPolicy {
+	return model.PasswordPolicy{
+		MinLength:     cfg.MinLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}
+}
+
+// BootstrapAdmin creates the platform admin described by cfg with every
+// scope, or promotes the credential already holding that user, which keeps
+// its password. It reports whether the credential was created and is safe
+// to run on every migration.
+func (d *CredentialsDatabase) BootstrapAdmin(
+	ctx context.Context,
+	cfg config.BootstrapAdminConfig,
+	policy model.PasswordPolicy,
+	passwords hasher.Hasher,
+) (bool, error) {
+	var stored model.Credential
+	err := d.Gorm.WithContext(ctx).Where(&model.Credential{User: cfg.User}).First(&stored).Error
+	switch {
+	case err == nil:
+		if stored.OrgID != nil {
+			return false, ErrBootstrapAdminInOrganization
+		}
+		stored.Role = model.RoleAdmin
+		stored.Scopes = model.KnownScopes
+		if err := d.UpdateCredentials(ctx, &stored, "role", "scopes"); err != nil {
+			return false, err
+		}
+		d.Logger.InfoContext(ctx, "bootstrap admin promoted", "target", cfg.User)
+		return false, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		d.Logger.ErrorContext(ctx, "bootstrap admin lookup failed", "target", cfg.User, "error", err)
+		return false, ErrCreationFailed
+	}
+
+	if err := policy.Check(cfg.User, cfg.Password); err != nil {
+		return false, err
+	}
+	request := model.AuthRequest{
+		User:     cfg.User,
+		Password: cfg.Password,
+		Email:    cfg.Email,
+		Scope:    model.KnownScopes.String(),
+		Role:     model.RoleAdmin,
+	}
+	if err := request.Validate(); err != nil {
+		return false, err
+	}
+	credential, err := request.ParseAuthRequestToCredential(passwords)
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to hash bootstrap admin password", "error", err)
+		return false, ErrCreationFailed
+	}
+	if err := d.CreateCredentials(ctx, credential); err != nil {
+		return false, err
+	}
+	d.Logger.InfoContext(ctx, "bootstrap admin created", "target", cfg.User)
+	return true, nil
+}
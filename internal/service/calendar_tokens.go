@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCalendarTokenNotFound = errors.New("calendar token not found")
+	ErrCalendarTokenFailed   = errors.New("failed to store calendar token")
+)
+
+type CalendarTokensDatabase struct {
+	Gorm   *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewCalendarTokensDatabase(gorm *gorm.DB, logger *slog.Logger) CalendarTokensDatabase {
+	return CalendarTokensDatabase{
+		Gorm:   gorm,
+		Logger: logger,
+	}
+}
+
+type CalendarTokensRepository interface {
+	RotateCalendarToken(ctx context.Context, token *model.CalendarToken) error
+	RevokeCalendarToken(ctx context.Context, credentialID int) error
+	AuthenticateCalendarToken(ctx context.Context, raw string) (*model.CalendarToken, error)
+}
+
+// RotateCalendarToken stores the token, replacing the previous token of the
+// credential so URLs shared with it stop working.
+func (d *CalendarTokensDatabase) RotateCalendarToken(ctx context.Context, token *model.CalendarToken) error {
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where(&model.CalendarToken{CredentialID: token.CredentialID}).
+			Delete(&model.CalendarToken{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to rotate calendar token", "error", err)
+		return ErrCalendarTokenFailed
+	}
+	return nil
+}
+
+func (d *CalendarTokensDatabase) RevokeCalendarToken(ctx context.Context, credentialID int) error {
+	result := d.Gorm.
+		WithContext(ctx).
+		Where(&model.CalendarToken{CredentialID: credentialID}).
+		Delete(&model.CalendarToken{})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to revoke calendar token", "error", result.Error)
+		return ErrCalendarTokenFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrCalendarTokenNotFound
+	}
+	return nil
+}
+
+// AuthenticateCalendarToken resolves a plain token to its stored record,
+// with the owning credential, and records its usage.
+func (d *CalendarTokensDatabase) AuthenticateCalendarToken(ctx context.Context, raw string) (*model.CalendarToken, error) {
+	var token model.CalendarToken
+	err := d.Gorm.
+		WithContext(ctx).
+		Preload("Credential").
+		Where(&model.CalendarToken{HashedToken: model.HashCalendarToken(raw)}).
+		First(&token).Error
+	if err != nil {
+		return nil, ErrCalendarTokenNotFound
+	}
+
+	now := time.Now()
+	err = d.Gorm.
+		WithContext(ctx).
+		Model(&token).
+		UpdateColumn("last_used_at", now).Error
+	if err != nil {
+		d.Logger.WarnContext(ctx, "failed to record calendar token usage", "token_id", token.ID, "error", err)
+	}
+	token.LastUsedAt = &now
+
+	return &token, nil
+}
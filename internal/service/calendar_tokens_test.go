@@ -0,0 +1,52 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarTokensDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := newTestDatabase(t)
+	credentials := NewDatabase(db, newTestLogger())
+	tokens := NewCalendarTokensDatabase(db, newTestLogger())
+
+	credential := model.Credential{User: "fan", EncryptedPassword: "x"}
+	is.Nil(credentials.CreateCredentials(ctx, &credential))
+
+	first, firstRaw, err := model.NewCalendarToken(credential.ID)
+	is.Nil(err)
+	is.Nil(tokens.RotateCalendarToken(ctx, first))
+	is.NotEqual(firstRaw, first.HashedToken)
+
+	t.Run("when token is valid, should return it with owner and record usage", func(t *testing.T) {
+		authenticated, err := tokens.AuthenticateCalendarToken(ctx, firstRaw)
+		is.Nil(err)
+		is.Equal("fan", authenticated.Credential.User)
+		is.NotNil(authenticated.LastUsedAt)
+	})
+
+	t.Run("when token is rotated, should only authenticate the new one", func(t *testing.T) {
+		second, secondRaw, err := model.NewCalendarToken(credential.ID)
+		is.Nil(err)
+		is.Nil(tokens.RotateCalendarToken(ctx, second))
+
+		_, err = tokens.AuthenticateCalendarToken(ctx, firstRaw)
+		is.Equal(ErrCalendarTokenNotFound, err)
+		_, err = tokens.AuthenticateCalendarToken(ctx, secondRaw)
+		is.Nil(err)
+	})
+
+	t.Run("when token is revoked, should not authenticate anymore", func(t *testing.T) {
+		is.Nil(tokens.RevokeCalendarToken(ctx, credential.ID))
+		is.Equal(ErrCalendarTokenNotFound, tokens.RevokeCalendarToken(ctx, credential.ID))
+	})
+}
@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
 	"gorm.io/gorm"
 )
 
@@ -12,40 +15,187 @@ var (
 	ErrUserNotFound    = errors.New("invalid credentials")
 	ErrInvalidPassword = errors.New("invalid credentials")
 	ErrCreationFailed  = errors.New("failed to create new credentials")
+	ErrUpdateFailed    = errors.New("failed to update credentials")
+	ErrDeleteFailed    = errors.New("failed to delete credentials")
 )
 
 type CredentialsDatabase struct {
-	Gorm *gorm.DB
+	Gorm   *gorm.DB
+	Logger *slog.Logger
 }
 
-func NewDatabase(gorm *gorm.DB) CredentialsDatabase {
+func NewDatabase(gorm *gorm.DB, logger *slog.Logger) CredentialsDatabase {
 	return CredentialsDatabase{
-		Gorm: gorm,
+		Gorm:   gorm,
+		Logger: logger,
 	}
 }
 
 type CredentialsRepository interface {
 	FindCredentials(ctx context.Context, credentials *model.Credential) (*model.Credential, error)
 	CreateCredentials(ctx context.Context, credentials *model.Credential) error
+	UpdateCredentials(ctx context.Context, credentials *model.Credential, columns ...string) error
+	DeleteCredentials(ctx context.Context, credentials *model.Credential) error
+	ReplaceRecoveryCodes(ctx context.Context, credentialID int, hashes []string) error
+	UseRecoveryCode(ctx context.Context, credentialID int, hash string) error
+	RecordTwoFactorFailure(ctx context.Context, credentialID int, maxFailures int, lockout time.Duration) (bool, error)
+	RedeemTwoFactorChallenge(ctx context.Context, credentialID int, challengeID string) error
+	CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error
+	FindPasswordResetToken(ctx context.Context, raw string) (*model.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, token *model.PasswordResetToken, encryptedPassword string) error
+	FindExternalIdentity(ctx context.Context, issuer string, subject string) (*model.Credential, error)
+	ProvisionExternalCredential(ctx context.Context, credential *model.Credential, identity *model.ExternalIdentity) error
+	CreateOrganization(ctx context.Context, organization *model.Organization) error
+	FindOrganization(ctx context.Context, slug string) (*model.Organization, error)
+	ListOrganizations(ctx context.Context) ([]model.Organization, error)
+}
+
+// tenantScope restricts credential queries to the organization of the
+// caller. Unscoped contexts, such as logins and platform admins, see every
+// credential.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orgID, ok := tenant.Organization(ctx); ok {
+			return db.Where("org_id = ?", orgID)
+		}
+		return db
+	}
+}
+
+// tenantCredentialScope restricts queries on tables owned by a credential to
+// the credentials of the caller's organization.
+func tenantCredentialScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orgID, ok := tenant.Organization(ctx); ok {
+			owned := db.Session(&gorm.Session{NewDB: true}).
+				Model(&model.Credential{}).
+				Select("id").
+				Where("org_id = ?", orgID)
+			return db.Where("credential_id IN (?)", owned)
+		}
+		return db
+	}
 }
 
 func (d *CredentialsDatabase) FindCredentials(ctx context.Context, credential *model.Credential) (*model.Credential, error) {
 	var storedCredential model.Credential
 	result := d.Gorm.
 		WithContext(ctx).
-		Where("user", credential.User).
+		Scopes(tenantScope(ctx)).
+		Where(&model.Credential{User: credential.User}).
 		First(&storedCredential)
 	if result.Error != nil {
+		d.Logger.WarnContext(ctx, "credentials lookup failed", "target", credential.User, "error", result.Error)
 		return nil, ErrUserNotFound
 	}
 
 	return &storedCredential, nil
 }
 
+// CreateCredentials stores the credential in the caller's organization,
+// when scoped to one.
 func (d *CredentialsDatabase) CreateCredentials(ctx context.Context, credential *model.Credential) error {
-	err := d.Gorm.Create(&credential).Error
+	if orgID, ok := tenant.Organization(ctx); ok {
+		credential.OrgID = &orgID
+	}
+	err := d.Gorm.WithContext(ctx).Create(credential).Error
 	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to create credentials", "target", credential.User, "error", err)
 		return ErrCreationFailed
 	}
 	return nil
 }
+
+// UpdateCredentials persists only the given columns of the credential.
+func (d *CredentialsDatabase) UpdateCredentials(ctx context.Context, credential *model.Credential, columns ...string) error {
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantScope(ctx)).
+		Model(credential).
+		Select(columns).
+		Updates(credential).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to update credentials", "target", credential.User, "error", err)
+		return ErrUpdateFailed
+	}
+	return nil
+}
+
+// BackfillScopes stores explicit scopes on credentials created before
+// scopes existed, which used to be allowed every scope. Admins keep every
+// scope and other users get model.DefaultScopes. Then it applies the
+// model.ScopeSplits each credential has not seen yet. It returns how many
+// credentials were updated and is safe to run on every migration.
+func (d *CredentialsDatabase) BackfillScopes(ctx context.Context) (int64, error) {
+	var updated int64
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, backfill := range []struct {
+			role   string
+			scopes model.Scopes
+		}{
+			{role: model.RoleAdmin, scopes: model.KnownScopes},
+			{role: "", scopes: model.DefaultScopes},
+		} {
+			query := tx.Model(&model.Credential{}).Where("scopes IS NULL OR scopes = ''")
+			if backfill.role != "" {
+				query = query.Where("role = ?", backfill.role)
+			}
+			result := query.Update("scopes", backfill.scopes)
+			if result.Error != nil {
+				return result.Error
+			}
+			updated += result.RowsAffected
+		}
+
+		var legacy []model.Credential
+		err := tx.Select("id", "scopes", "scopes_revision").
+			Where("scopes_revision < ?", len(model.ScopeSplits)).
+			Find(&legacy).Error
+		if err != nil {
+			return err
+		}
+		for _, credential := range legacy {
+			scopes := credential.Scopes
+			for _, split := range model.ScopeSplits[credential.ScopesRevision:] {
+				if scopes.Has(split.From) {
+					scopes = scopes.Grant(split.Granted)
+				}
+			}
+			err := tx.Model(&model.Credential{ID: credential.ID}).Updates(map[string]any{
+				"scopes":          scopes,
+				"scopes_revision": len(model.ScopeSplits),
+			}).Error
+			if err != nil {
+				return err
+			}
+			if len(scopes) != len(credential.Scopes) {
+				updated++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to backfill credential scopes", "error", err)
+		return 0, ErrUpdateFailed
+	}
+	return updated, nil
+}
+
+func (d *CredentialsDatabase) DeleteCredentials(ctx context.Context, credential *model.Credential) error {
+	if credential.User == "" {
+		return ErrUserNotFound
+	}
+	result := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantScope(ctx)).
+		Where(&model.Credential{User: credential.User}).
+		Delete(&model.Credential{})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to delete credentials", "target", credential.User, "error", result.Error)
+		return ErrDeleteFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
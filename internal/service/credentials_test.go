@@ -0,0 +1,189 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/sqlite/sqlitetest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func newTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	return sqlitetest.NewDB(t, model.Models()...)
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCredentialsDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := NewDatabase(newTestDatabase(t), newTestLogger())
+
+	request := model.AuthRequest{User: "admin", Password: "xpto123"}
+	credential, err := request.ParseAuthRequestToCredential(&hasher.Bcrypt{Cost: bcrypt.MinCost})
+	is.Nil(err)
+	is.Nil(db.CreateCredentials(ctx, credential))
+	is.NotZero(credential.ID)
+
+	tests := []struct {
+		name        string
+		user        string
+		hasErr      bool
+		expectedErr error
+	}{
+		{
+			name: "when user exists, should return stored credential",
+			user: "admin",
+		},
+		{
+			name:        "when user doesn't exist, should return error",
+			user:        "ghost",
+			hasErr:      true,
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored, err := db.FindCredentials(ctx, &model.Credential{User: test.user})
+			if test.hasErr {
+				is.Equal(test.expectedErr, err)
+				return
+			}
+			is.Nil(err)
+			is.Equal(credential.ID, stored.ID)
+			is.True(stored.CheckPassword("xpto123"))
+			is.False(stored.CheckPassword("wrong"))
+		})
+	}
+
+	t.Run("when user already exists, should return error", func(t *testing.T) {
+		duplicated, err := request.ParseAuthRequestToCredential(&hasher.Bcrypt{Cost: bcrypt.MinCost})
+		is.Nil(err)
+		is.Equal(ErrCreationFailed, db.CreateCredentials(ctx, duplicated))
+	})
+
+	t.Run("when credentials have no scopes, should backfill them by role", func(t *testing.T) {
+		legacy := map[string]string{"legacy-admin": model.RoleAdmin, "legacy-user": model.RoleUser}
+		for user, role := range legacy {
+			is.Nil(db.Gorm.Exec("INSERT INTO credentials (user, encrypted_password, role) VALUES (?, ?, ?)", user, credential.EncryptedPassword, role).Error)
+		}
+
+		updated, err := db.BackfillScopes(ctx)
+		is.Nil(err)
+		is.Equal(int64(2), updated)
+
+		for user, expected := range map[string]model.Scopes{"legacy-admin": model.KnownScopes, "legacy-user": model.DefaultScopes, "admin": model.DefaultScopes} {
+			stored, err := db.FindCredentials(ctx, &model.Credential{User: user})
+			is.Nil(err)
+			is.Equal(expected, stored.Scopes)
+		}
+
+		updated, err = db.BackfillScopes(ctx)
+		is.Nil(err)
+		is.Zero(updated)
+	})
+
+	t.Run("when credentials predate a scope split, should grant the split scopes once", func(t *testing.T) {
+		is.Nil(db.Gorm.Exec("INSERT INTO credentials (user, encrypted_password, role, scopes) VALUES (?, ?, ?, ?)", "reader", credential.EncryptedPassword, model.RoleUser, model.ScopeCompetitionsRead).Error)
+		is.Nil(db.Gorm.Exec("INSERT INTO credentials (user, encrypted_password, role, scopes) VALUES (?, ?, ?, ?)", "auditor", credential.EncryptedPassword, model.RoleUser, model.ScopeAuditRead).Error)
+
+		updated, err := db.BackfillScopes(ctx)
+		is.Nil(err)
+		is.Equal(int64(1), updated)
+
+		for user, expected := range map[string]model.Scopes{
+			"reader":  {model.ScopeCompetitionsRead, model.ScopeFavoritesWrite, model.ScopeWebhooksWrite, model.ScopeCalendarWrite},
+			"auditor": {model.ScopeAuditRead},
+		} {
+			stored, err := db.FindCredentials(ctx, &model.Credential{User: user})
+			is.Nil(err)
+			is.Equal(expected, stored.Scopes)
+			is.Equal(len(model.ScopeSplits), stored.ScopesRevision)
+		}
+
+		stored, err := db.FindCredentials(ctx, &model.Credential{User: "reader"})
+		is.Nil(err)
+		stored.Scopes = model.Scopes{model.ScopeCompetitionsRead}
+		is.Nil(db.UpdateCredentials(ctx, stored, "scopes"))
+		updated, err = db.BackfillScopes(ctx)
+		is.Nil(err)
+		is.Zero(updated, "scopes removed after the split stay removed")
+	})
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := NewDatabase(newTestDatabase(t), newTestLogger())
+	passwords := &hasher.Bcrypt{Cost: bcrypt.MinCost}
+	policy := model.PasswordPolicy{MinLength: 10, RequireDigit: true}
+	cfg := config.BootstrapAdminConfig{User: "root", Password: "Xpto-123456"}
+
+	t.Run("when the password breaks the policy, should not create the admin", func(t *testing.T) {
+		_, err := db.BootstrapAdmin(ctx, config.BootstrapAdminConfig{User: "root", Password: "short"}, policy, passwords)
+		var policyErr *model.PasswordPolicyError
+		is.ErrorAs(err, &policyErr)
+		_, err = db.FindCredentials(ctx, &model.Credential{User: "root"})
+		is.Equal(ErrUserNotFound, err)
+	})
+
+	t.Run("when the admin doesn't exist, should create a platform admin with every scope", func(t *testing.T) {
+		created, err := db.BootstrapAdmin(ctx, cfg, policy, passwords)
+		is.Nil(err)
+		is.True(created)
+
+		stored, err := db.FindCredentials(ctx, &model.Credential{User: "root"})
+		is.Nil(err)
+		is.Equal(model.RoleAdmin, stored.Role)
+		is.Equal(model.KnownScopes, stored.Scopes)
+		is.Nil(stored.OrgID)
+		is.True(stored.CheckPassword(cfg.Password))
+	})
+
+	t.Run("when the user exists, should promote it and keep its password", func(t *testing.T) {
+		request := model.AuthRequest{User: "legacy", Password: "xpto123"}
+		legacy, err := request.ParseAuthRequestToCredential(passwords)
+		is.Nil(err)
+		is.Nil(db.CreateCredentials(ctx, legacy))
+
+		created, err := db.BootstrapAdmin(ctx, config.BootstrapAdminConfig{User: "legacy", Password: cfg.Password}, policy, passwords)
+		is.Nil(err)
+		is.False(created)
+
+		stored, err := db.FindCredentials(ctx, &model.Credential{User: "legacy"})
+		is.Nil(err)
+		is.Equal(model.RoleAdmin, stored.Role)
+		is.Equal(model.KnownScopes, stored.Scopes)
+		is.True(stored.CheckPassword("xpto123"))
+	})
+
+	t.Run("when the user belongs to an organization, should refuse to promote it", func(t *testing.T) {
+		organization := model.Organization{Slug: "torcida", Name: "Torcida"}
+		is.Nil(db.CreateOrganization(ctx, &organization))
+		request := model.AuthRequest{User: "member", Password: "xpto123"}
+		member, err := request.ParseAuthRequestToCredential(passwords)
+		is.Nil(err)
+		member.OrgID = &organization.ID
+		is.Nil(db.CreateCredentials(ctx, member))
+
+		_, err = db.BootstrapAdmin(ctx, config.BootstrapAdminConfig{User: "member", Password: cfg.Password}, policy, passwords)
+		is.Equal(ErrBootstrapAdminInOrganization, err)
+	})
+}
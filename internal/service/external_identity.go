@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+// FindExternalIdentity returns the credential linked to the issuer subject.
+func (d *CredentialsDatabase) FindExternalIdentity(ctx context.Context, issuer string, subject string) (*model.Credential, error) {
+	var identity model.ExternalIdentity
+	err := d.Gorm.
+		WithContext(ctx).
+		Preload("Credential").
+		Where(&model.ExternalIdentity{Issuer: issuer, Subject: subject}).
+		First(&identity).Error
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return &identity.Credential, nil
+}
+
+// ProvisionExternalCredential creates the credential and its identity link
+// atomically.
+func (d *CredentialsDatabase) ProvisionExternalCredential(ctx context.Context, credential *model.Credential, identity *model.ExternalIdentity) error {
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(credential).Error; err != nil {
+			return err
+		}
+		identity.CredentialID = credential.ID
+		return tx.Create(identity).Error
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to provision external credentials", "target", credential.User, "error", err)
+		return ErrCreationFailed
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFavoriteNotFound = errors.New("favorite not found")
+	ErrFavoriteFailed   = errors.New("failed to store favorite")
+	ErrFavoriteList     = errors.New("failed to list favorites")
+	ErrTooManyFavorites = errors.New("too many favorites")
+)
+
+type FavoritesDatabase struct {
+	Gorm *gorm.DB
+	// Max bounds the favorites of each credential, which also bounds the
+	// football app calls of its feed.
+	Max    int
+	Logger *slog.Logger
+}
+
+func NewFavoritesDatabase(gorm *gorm.DB, max int, logger *slog.Logger) FavoritesDatabase {
+	return FavoritesDatabase{
+		Gorm:   gorm,
+		Max:    max,
+		Logger: logger,
+	}
+}
+
+type FavoritesRepository interface {
+	ListFavorites(ctx context.Context, credentialID int) ([]model.Favorite, error)
+	AddFavorite(ctx context.Context, favorite *model.Favorite) error
+	RemoveFavorite(ctx context.Context, credentialID int, kind string, externalID int) error
+}
+
+func (d *FavoritesDatabase) ListFavorites(ctx context.Context, credentialID int) ([]model.Favorite, error) {
+	var favorites []model.Favorite
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Where(&model.Favorite{CredentialID: credentialID}).
+		Order("kind, external_id").
+		Find(&favorites).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list favorites", "error", err)
+		return nil, ErrFavoriteList
+	}
+	return favorites, nil
+}
+
+// AddFavorite follows the item unless the credential already has Max
+// favorites. Following it again only refreshes its name.
+func (d *FavoritesDatabase) AddFavorite(ctx context.Context, favorite *model.Favorite) error {
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var others int64
+		err := tx.Model(&model.Favorite{}).
+			Where(&model.Favorite{CredentialID: favorite.CredentialID}).
+			Where("NOT (kind = ? AND external_id = ?)", favorite.Kind, favorite.ExternalID).
+			Count(&others).Error
+		if err != nil {
+			return err
+		}
+		if others >= int64(d.Max) {
+			return ErrTooManyFavorites
+		}
+		return tx.
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "credential_id"}, {Name: "kind"}, {Name: "external_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			}).
+			Create(favorite).Error
+	})
+	if errors.Is(err, ErrTooManyFavorites) {
+		return err
+	}
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to add favorite", "kind", favorite.Kind, "external_id", favorite.ExternalID, "error", err)
+		return ErrFavoriteFailed
+	}
+	return nil
+}
+
+func (d *FavoritesDatabase) RemoveFavorite(ctx context.Context, credentialID int, kind string, externalID int) error {
+	result := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Where(&model.Favorite{CredentialID: credentialID, Kind: kind, ExternalID: externalID}).
+		Delete(&model.Favorite{})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to remove favorite", "kind", kind, "external_id", externalID, "error", result.Error)
+		return ErrFavoriteFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrFavoriteNotFound
+	}
+	return nil
+}
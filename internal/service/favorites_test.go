@@ -0,0 +1,64 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavoritesDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	gormDB := newTestDatabase(t)
+	credentials := NewDatabase(gormDB, newTestLogger())
+	db := NewFavoritesDatabase(gormDB, 2, newTestLogger())
+
+	organization := &model.Organization{Slug: "tricolor", Name: "Torcida Tricolor"}
+	is.Nil(credentials.CreateOrganization(ctx, organization))
+	fan := &model.Credential{User: "fan", EncryptedPassword: "x"}
+	is.Nil(credentials.CreateCredentials(ctx, fan))
+
+	t.Run("when following twice, should keep one favorite with the latest name", func(t *testing.T) {
+		is.Nil(db.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteCompetition, ExternalID: 2021, Name: "PL"}))
+		is.Nil(db.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteCompetition, ExternalID: 2021, Name: "Premier League"}))
+		is.Nil(db.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteTeam, ExternalID: 2021, Name: "Team"}))
+
+		favorites, err := db.ListFavorites(ctx, fan.ID)
+		is.Nil(err)
+		is.Len(favorites, 2)
+		is.Equal("Premier League", favorites[0].Name)
+	})
+
+	t.Run("when at the limit, should refuse new favorites but refresh followed ones", func(t *testing.T) {
+		is.ErrorIs(db.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteTeam, ExternalID: 57, Name: "Arsenal FC"}), ErrTooManyFavorites)
+		is.Nil(db.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteTeam, ExternalID: 2021, Name: "Team"}))
+
+		favorites, err := db.ListFavorites(ctx, fan.ID)
+		is.Nil(err)
+		is.Len(favorites, 2)
+	})
+
+	t.Run("when scoped to another organization, should not see the favorites", func(t *testing.T) {
+		other := tenant.WithOrganization(ctx, organization.ID)
+		favorites, err := db.ListFavorites(other, fan.ID)
+		is.Nil(err)
+		is.Empty(favorites)
+		is.ErrorIs(db.RemoveFavorite(other, fan.ID, model.FavoriteTeam, 2021), ErrFavoriteNotFound)
+	})
+
+	t.Run("when unfollowing, should remove only that favorite", func(t *testing.T) {
+		is.Nil(db.RemoveFavorite(ctx, fan.ID, model.FavoriteTeam, 2021))
+		is.ErrorIs(db.RemoveFavorite(ctx, fan.ID, model.FavoriteTeam, 2021), ErrFavoriteNotFound)
+
+		favorites, err := db.ListFavorites(ctx, fan.ID)
+		is.Nil(err)
+		is.Len(favorites, 1)
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/fut-app/internal/model"
@@ -17,51 +20,135 @@ var (
 	ErrToReadResponse         = errors.New("failed to read response")
 	ErrToComunicateFootbalAPP = errors.New("failed to comunicate with footbal app")
 	ErrToUnmarshal            = errors.New("failed to unmarshal response")
+	ErrFootballNotFound       = errors.New("not found in footbal app")
 )
 
 type Football struct {
 	URL string
+	// Token authenticates with football-data.org, which answers 403 on
+	// everything beyond the competition list without it.
+	Token  string
+	Client *http.Client
+	Logger *slog.Logger
 }
 
-func NewFootball(url string) Football {
+func NewFootball(url string, token string, timeout time.Duration, logger *slog.Logger) Football {
 	return Football{
-		URL: url,
+		URL:   url,
+		Token: token,
+		Client: &http.Client{
+			Timeout: timeout,
+		},
+		Logger: logger,
 	}
 }
 
 type FootballAPI interface {
 	CompetitionList(ctx context.Context) (*model.CompetitionResponse, error)
+	CompetitionMatches(ctx context.Context, competitionID int, filter model.MatchFilter) (*model.MatchResponse, error)
+	CompetitionStandings(ctx context.Context, competitionID int) (*model.StandingsResponse, error)
+	CompetitionScorers(ctx context.Context, competitionID int) (*model.ScorersResponse, error)
+	Team(ctx context.Context, teamID int) (*model.Team, error)
+	TeamMatches(ctx context.Context, teamID int, filter model.MatchFilter) (*model.MatchResponse, error)
+	Matches(ctx context.Context, filter model.MatchFilter) (*model.MatchResponse, error)
 }
 
 func (f *Football) CompetitionList(ctx context.Context) (*model.CompetitionResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", f.URL+"/v4/competitions", nil)
-	if err != nil {
-		return nil, ErrToCreateRequest
+	var competitionResponse model.CompetitionResponse
+	if err := f.get(ctx, "/v4/competitions", nil, &competitionResponse); err != nil {
+		return nil, err
+	}
+	return &competitionResponse, nil
+}
+
+func (f *Football) CompetitionMatches(ctx context.Context, competitionID int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	var matchResponse model.MatchResponse
+	path := fmt.Sprintf("/v4/competitions/%d/matches", competitionID)
+	if err := f.get(ctx, path, filter.Query(), &matchResponse); err != nil {
+		return nil, err
+	}
+	return &matchResponse, nil
+}
+
+func (f *Football) CompetitionStandings(ctx context.Context, competitionID int) (*model.StandingsResponse, error) {
+	var standingsResponse model.StandingsResponse
+	path := fmt.Sprintf("/v4/competitions/%d/standings", competitionID)
+	if err := f.get(ctx, path, nil, &standingsResponse); err != nil {
+		return nil, err
+	}
+	return &standingsResponse, nil
+}
+
+func (f *Football) CompetitionScorers(ctx context.Context, competitionID int) (*model.ScorersResponse, error) {
+	var scorersResponse model.ScorersResponse
+	path := fmt.Sprintf("/v4/competitions/%d/scorers", competitionID)
+	if err := f.get(ctx, path, nil, &scorersResponse); err != nil {
+		return nil, err
+	}
+	return &scorersResponse, nil
+}
+
+func (f *Football) Team(ctx context.Context, teamID int) (*model.Team, error) {
+	var team model.Team
+	if err := f.get(ctx, fmt.Sprintf("/v4/teams/%d", teamID), nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (f *Football) TeamMatches(ctx context.Context, teamID int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	var matchResponse model.MatchResponse
+	path := fmt.Sprintf("/v4/teams/%d/matches", teamID)
+	if err := f.get(ctx, path, filter.Query(), &matchResponse); err != nil {
+		return nil, err
+	}
+	return &matchResponse, nil
+}
+
+// Matches lists the matches of every competition the football app covers.
+func (f *Football) Matches(ctx context.Context, filter model.MatchFilter) (*model.MatchResponse, error) {
+	var matchResponse model.MatchResponse
+	if err := f.get(ctx, "/v4/matches", filter.Query(), &matchResponse); err != nil {
+		return nil, err
 	}
+	return &matchResponse, nil
+}
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// get calls the football app and decodes the JSON body into out.
+func (f *Football) get(ctx context.Context, path string, query url.Values, out any) error {
+	target := f.URL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return ErrToCreateRequest
 	}
+	req.Header.Set("X-Auth-Token", f.Token)
 
-	resp, err := client.Do(req)
+	resp, err := f.Client.Do(req)
 	if err != nil {
-		return nil, ErrToDoRequest
+		f.Logger.ErrorContext(ctx, "football app request failed", "url", req.URL.String(), "error", err)
+		return ErrToDoRequest
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, ErrToReadResponse
+		return ErrToReadResponse
 	}
 
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrFootballNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, ErrToComunicateFootbalAPP
+		f.Logger.ErrorContext(ctx, "football app returned unexpected status", "url", req.URL.String(), "status", resp.StatusCode)
+		return ErrToComunicateFootbalAPP
 	}
 
-	var competitionResponse model.CompetitionResponse
-	if err := json.Unmarshal(body, &competitionResponse); err != nil {
-		return nil, ErrToUnmarshal
+	if err := json.Unmarshal(body, out); err != nil {
+		f.Logger.ErrorContext(ctx, "failed to unmarshal football app response", "error", err)
+		return ErrToUnmarshal
 	}
-
-	return &competitionResponse, nil
+	return nil
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/model"
+)
+
+type footballCacheKey struct {
+	call   string
+	id     int
+	filter model.MatchFilter
+}
+
+type footballCacheEntry struct {
+	response any
+	expires  time.Time
+}
+
+// FootballCache shares the matches and standings fetched from the football
+// app for TTL, so feeds of users following the same items cost one upstream
+// call per interval. Failures are not cached. Cached responses are shared
+// between callers and must not be modified.
+type FootballCache struct {
+	FootballAPI
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[footballCacheKey]footballCacheEntry
+}
+
+func NewFootballCache(football FootballAPI, ttl time.Duration) *FootballCache {
+	return &FootballCache{
+		FootballAPI: football,
+		TTL:         ttl,
+	}
+}
+
+func (f *FootballCache) CompetitionMatches(ctx context.Context, competitionID int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	return cached(f, footballCacheKey{"competition_matches", competitionID, filter}, func() (*model.MatchResponse, error) {
+		return f.FootballAPI.CompetitionMatches(ctx, competitionID, filter)
+	})
+}
+
+func (f *FootballCache) CompetitionStandings(ctx context.Context, competitionID int) (*model.StandingsResponse, error) {
+	return cached(f, footballCacheKey{call: "competition_standings", id: competitionID}, func() (*model.StandingsResponse, error) {
+		return f.FootballAPI.CompetitionStandings(ctx, competitionID)
+	})
+}
+
+func (f *FootballCache) TeamMatches(ctx context.Context, teamID int, filter model.MatchFilter) (*model.MatchResponse, error) {
+	return cached(f, footballCacheKey{"team_matches", teamID, filter}, func() (*model.MatchResponse, error) {
+		return f.FootballAPI.TeamMatches(ctx, teamID, filter)
+	})
+}
+
+func cached[T any](f *FootballCache, key footballCacheKey, fetch func() (T, error)) (T, error) {
+	now := time.Now()
+	f.mu.Lock()
+	entry, ok := f.entries[key]
+	f.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.response.(T), nil
+	}
+
+	response, err := fetch()
+	if err != nil {
+		return response, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.entries == nil {
+		f.entries = make(map[footballCacheKey]footballCacheEntry)
+	}
+	// Keys carry the feed's date window, so drop the stale ones as they go.
+	for stale, entry := range f.entries {
+		if !now.Before(entry.expires) {
+			delete(f.entries, stale)
+		}
+	}
+	f.entries[key] = footballCacheEntry{response: response, expires: now.Add(f.TTL)}
+	return response, nil
+}
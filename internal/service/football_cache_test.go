@@ -0,0 +1,55 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// standingsFootball counts the standings calls and fails while err is set.
+type standingsFootball struct {
+	FootballAPI
+	calls int
+	err   error
+}
+
+func (f *standingsFootball) CompetitionStandings(context.Context, int) (*model.StandingsResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &model.StandingsResponse{}, nil
+}
+
+func TestFootballCache(t *testing.T) {
+	is := require.New(t)
+	ctx := context.Background()
+	football := &standingsFootball{err: errors.New("upstream down")}
+	cache := NewFootballCache(football, 50*time.Millisecond)
+
+	_, err := cache.CompetitionStandings(ctx, 2021)
+	is.Error(err)
+	football.err = nil
+
+	first, err := cache.CompetitionStandings(ctx, 2021)
+	is.Nil(err)
+	second, err := cache.CompetitionStandings(ctx, 2021)
+	is.Nil(err)
+	is.Same(first, second)
+	is.Equal(2, football.calls, "should not cache failures")
+
+	_, err = cache.CompetitionStandings(ctx, 2013)
+	is.Nil(err)
+	is.Equal(3, football.calls, "should key responses by competition")
+
+	time.Sleep(cache.TTL)
+	_, err = cache.CompetitionStandings(ctx, 2021)
+	is.Nil(err)
+	is.Equal(4, football.calls, "should refetch once expired")
+}
@@ -0,0 +1,35 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFootballSendsToken(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "football-data-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"standings": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	football := NewFootball(server.URL, "football-data-token", time.Second, newTestLogger())
+	_, err := football.CompetitionStandings(context.Background(), 2021)
+	is.Nil(err)
+
+	football.Token = ""
+	_, err = football.CompetitionStandings(context.Background(), 2021)
+	is.Equal(ErrToComunicateFootbalAPP, err)
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/model"
+)
+
+// hubBuffer is the number of messages a client may lag behind before it is
+// dropped.
+const hubBuffer = 64
+
+var ErrTooManySubscriptions = errors.New("too many subscriptions")
+
+// HubClient is one connection of the hub. The transport writes out what it
+// reads from Send, which is closed once the client is dropped or leaves.
+type HubClient struct {
+	Send chan model.SocketMessage
+
+	subscriptions map[int]struct{}
+	gone          bool
+}
+
+// SocketHub routes the subscriptions of socket clients.
+type SocketHub interface {
+	Register() *HubClient
+	Unregister(client *HubClient)
+	Subscribe(client *HubClient, competitionID int) error
+	Unsubscribe(client *HubClient, competitionID int)
+	Reply(client *HubClient, message model.SocketMessage)
+}
+
+type competitionFeed struct {
+	subscribers map[*HubClient]struct{}
+	standings   []model.FormattedStanding
+	scorers     []model.FormattedScorer
+	fingerprint [2]string
+	refreshing  bool
+}
+
+// Hub fans live matches, standings and scorers out to the clients
+// subscribed to each competition. Matches come from the shared live scores
+// poller; standings and scorers are polled every Interval while a
+// competition has subscribers.
+type Hub struct {
+	Live             LiveSubscriber
+	Football         FootballAPI
+	Interval         time.Duration
+	MaxSubscriptions int
+	Logger           *slog.Logger
+
+	mu           sync.Mutex
+	clients      map[*HubClient]struct{}
+	competitions map[int]*competitionFeed
+	matches      map[int]model.FormattedMatch
+	stop         context.CancelFunc
+	closed       bool
+}
+
+func NewHub(live *LiveScores, football Football, interval time.Duration, maxSubscriptions int, logger *slog.Logger) *Hub {
+	return &Hub{
+		Live:             live,
+		Football:         &football,
+		Interval:         interval,
+		MaxSubscriptions: maxSubscriptions,
+		Logger:           logger,
+	}
+}
+
+// Register adds a client without subscriptions.
+func (h *Hub) Register() *HubClient {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := &HubClient{
+		Send:          make(chan model.SocketMessage, hubBuffer),
+		subscriptions: make(map[int]struct{}),
+	}
+	if h.closed {
+		client.gone = true
+		close(client.Send)
+		return client
+	}
+	if h.clients == nil {
+		h.clients = make(map[*HubClient]struct{})
+		h.competitions = make(map[int]*competitionFeed)
+	}
+	h.clients[client] = struct{}{}
+	return client
+}
+
+// Unregister removes the client and closes its Send channel.
+func (h *Hub) Unregister(client *HubClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(client)
+}
+
+// Subscribe adds the competition to the client and queues the acknowledgement
+// followed by what is known of it: live matches, standings and scorers.
+func (h *Hub) Subscribe(client *HubClient, competitionID int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if client.gone {
+		return nil
+	}
+	if _, ok := client.subscriptions[competitionID]; ok {
+		h.send(client, model.SocketMessage{Type: model.SocketSubscribed, Campeonato: model.CompetitionID(competitionID)})
+		return nil
+	}
+	if len(client.subscriptions) >= h.MaxSubscriptions {
+		return ErrTooManySubscriptions
+	}
+
+	feed, ok := h.competitions[competitionID]
+	if !ok {
+		feed = &competitionFeed{subscribers: make(map[*HubClient]struct{})}
+		h.competitions[competitionID] = feed
+	}
+	feed.subscribers[client] = struct{}{}
+	client.subscriptions[competitionID] = struct{}{}
+	if h.stop == nil {
+		h.start()
+	}
+
+	publicID := model.CompetitionID(competitionID)
+	h.send(client, model.SocketMessage{Type: model.SocketSubscribed, Campeonato: publicID})
+	for _, match := range h.competitionMatches(publicID) {
+		h.send(client, model.SocketMessage{Type: model.SocketMatch, Campeonato: publicID, Partida: &match})
+	}
+	if feed.standings != nil {
+		h.send(client, model.SocketMessage{Type: model.SocketStandings, Campeonato: publicID, Classificacao: feed.standings})
+	}
+	if feed.scorers != nil {
+		h.send(client, model.SocketMessage{Type: model.SocketScorers, Campeonato: publicID, Artilharia: feed.scorers})
+	}
+	if !ok {
+		h.refresh(competitionID, feed)
+	}
+	return nil
+}
+
+// Unsubscribe removes the competition from the client and acknowledges it.
+func (h *Hub) Unsubscribe(client *HubClient, competitionID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if client.gone {
+		return
+	}
+	h.unsubscribe(client, competitionID)
+	h.send(client, model.SocketMessage{Type: model.SocketUnsubscribed, Campeonato: model.CompetitionID(competitionID)})
+}
+
+// Reply queues a message to one client, e.g. an error or a pong.
+func (h *Hub) Reply(client *HubClient, message model.SocketMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !client.gone {
+		h.send(client, message)
+	}
+}
+
+// Close drops every client, e.g. on server shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for client := range h.clients {
+		h.drop(client)
+	}
+}
+
+// unsubscribe forgets a competition once nobody follows it. Callers hold mu.
+func (h *Hub) unsubscribe(client *HubClient, competitionID int) {
+	delete(client.subscriptions, competitionID)
+	feed, ok := h.competitions[competitionID]
+	if !ok {
+		return
+	}
+	delete(feed.subscribers, client)
+	if len(feed.subscribers) == 0 {
+		delete(h.competitions, competitionID)
+	}
+	if len(h.competitions) == 0 {
+		h.halt()
+	}
+}
+
+// drop removes a client and closes its Send channel. Callers hold mu.
+func (h *Hub) drop(client *HubClient) {
+	if client.gone {
+		return
+	}
+	for competitionID := range client.subscriptions {
+		h.unsubscribe(client, competitionID)
+	}
+	client.gone = true
+	delete(h.clients, client)
+	close(client.Send)
+}
+
+// send queues a message without blocking, dropping clients that fell
+// behind. Callers hold mu.
+func (h *Hub) send(client *HubClient, message model.SocketMessage) {
+	if client.gone {
+		return
+	}
+	select {
+	case client.Send <- message:
+	default:
+		h.Logger.Warn("dropping slow socket client")
+		h.drop(client)
+	}
+}
+
+func (h *Hub) broadcast(competitionID int, message model.SocketMessage) {
+	feed, ok := h.competitions[competitionID]
+	if !ok {
+		return
+	}
+	for client := range feed.subscribers {
+		h.send(client, message)
+	}
+}
+
+func (h *Hub) competitionMatches(publicID string) []model.FormattedMatch {
+	var matches []model.FormattedMatch
+	for _, match := range h.matches {
+		if match.Campeonato == publicID {
+			matches = append(matches, match)
+		}
+	}
+	slices.SortFunc(matches, func(a, b model.FormattedMatch) int {
+		return a.ID - b.ID
+	})
+	return matches
+}
+
+// start follows the live matches and polls the subscribed competitions.
+// Callers hold mu.
+func (h *Hub) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.stop = cancel
+	h.matches = make(map[int]model.FormattedMatch)
+	snapshot, events, unsubscribe := h.Live.Subscribe()
+	for _, match := range snapshot {
+		h.matches[match.ID] = match
+	}
+	go h.run(ctx, events, unsubscribe)
+}
+
+// halt stops following once no competition is subscribed. Callers hold mu.
+func (h *Hub) halt() {
+	if h.stop != nil {
+		h.stop()
+		h.stop = nil
+	}
+	h.matches = nil
+}
+
+func (h *Hub) run(ctx context.Context, events <-chan model.LiveEvent, unsubscribe func()) {
+	defer func() { unsubscribe() }()
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The live poller dropped us or is shutting down: wait a
+				// moment, then follow it again from a fresh snapshot.
+				unsubscribe()
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				var snapshot []model.FormattedMatch
+				snapshot, events, unsubscribe = h.Live.Subscribe()
+				h.resync(ctx, snapshot)
+				continue
+			}
+			h.follow(ctx, event)
+		case <-ticker.C:
+			h.mu.Lock()
+			if ctx.Err() == nil {
+				for competitionID, feed := range h.competitions {
+					h.refresh(competitionID, feed)
+				}
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+func (h *Hub) follow(ctx context.Context, event model.LiveEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ctx.Err() == nil {
+		h.apply(event)
+	}
+}
+
+// apply records a live match change and broadcasts it to the subscribers of
+// its competition. Callers hold mu.
+func (h *Hub) apply(event model.LiveEvent) {
+	if h.stop == nil {
+		return
+	}
+	match := event.Partida
+	message := model.SocketMessage{Type: model.SocketMatch, Campeonato: match.Campeonato, Partida: &match}
+	if event.Type == model.LiveMatchEnded {
+		message.Type = model.SocketMatchEnded
+		delete(h.matches, match.ID)
+	} else {
+		h.matches[match.ID] = match
+	}
+	if competitionID, err := model.ParseCompetitionID(match.Campeonato); err == nil {
+		h.broadcast(competitionID, message)
+	}
+}
+
+// resync replaces the live matches with a snapshot and broadcasts what
+// changed while the hub was not following.
+func (h *Hub) resync(ctx context.Context, snapshot []model.FormattedMatch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+
+	previous, current := h.matches, make(map[int]model.FormattedMatch, len(snapshot))
+	for _, match := range snapshot {
+		current[match.ID] = match
+	}
+	h.matches = current
+	for id, match := range current {
+		if known, ok := previous[id]; !ok || changed(known, match) {
+			h.apply(model.LiveEvent{Type: model.LiveMatchUpdate, Partida: match})
+		}
+	}
+	for id, match := range previous {
+		if _, ok := current[id]; !ok {
+			h.apply(model.LiveEvent{Type: model.LiveMatchEnded, Partida: match})
+		}
+	}
+}
+
+// refresh fetches standings and scorers in the background unless a fetch is
+// already running. Callers hold mu.
+func (h *Hub) refresh(competitionID int, feed *competitionFeed) {
+	if feed.refreshing {
+		return
+	}
+	feed.refreshing = true
+	go h.fetch(competitionID, feed)
+}
+
+func (h *Hub) fetch(competitionID int, feed *competitionFeed) {
+	ctx := context.Background()
+	standings, standingsErr := h.Football.CompetitionStandings(ctx, competitionID)
+	scorers, scorersErr := h.Football.CompetitionScorers(ctx, competitionID)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	feed.refreshing = false
+	if h.competitions[competitionID] != feed {
+		return
+	}
+
+	publicID := model.CompetitionID(competitionID)
+	if standingsErr != nil {
+		h.Logger.Warn("failed to fetch standings", "competition", competitionID, "error", standingsErr)
+	} else if table := model.FormatStandings(*standings); fingerprint(table) != feed.fingerprint[0] {
+		feed.standings = table
+		feed.fingerprint[0] = fingerprint(table)
+		h.broadcast(competitionID, model.SocketMessage{Type: model.SocketStandings, Campeonato: publicID, Classificacao: table})
+	}
+
+	if scorersErr != nil {
+		h.Logger.Warn("failed to fetch scorers", "competition", competitionID, "error", scorersErr)
+	} else if ranking := model.FormatScorers(*scorers); fingerprint(ranking) != feed.fingerprint[1] {
+		feed.scorers = ranking
+		feed.fingerprint[1] = fingerprint(ranking)
+		h.broadcast(competitionID, model.SocketMessage{Type: model.SocketScorers, Campeonato: publicID, Artilharia: ranking})
+	}
+}
+
+// fingerprint summarizes rows to detect changes between polls.
+func fingerprint[T any](rows []T) string {
+	var summary strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&summary, "%v;", row)
+	}
+	return summary.String()
+}
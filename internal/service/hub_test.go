@@ -0,0 +1,130 @@
+//go:build unit
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLive hands out one events channel driven by the test.
+type fakeLive struct {
+	snapshot []model.FormattedMatch
+	events   chan model.LiveEvent
+}
+
+func (f *fakeLive) Subscribe() ([]model.FormattedMatch, <-chan model.LiveEvent, func()) {
+	return f.snapshot, f.events, func() {}
+}
+
+func next(t *testing.T, client *HubClient) model.SocketMessage {
+	t.Helper()
+	select {
+	case message, ok := <-client.Send:
+		require.True(t, ok, "client dropped")
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("no socket message received")
+		return model.SocketMessage{}
+	}
+}
+
+func TestHub(t *testing.T) {
+	is := require.New(t)
+	live := &fakeLive{
+		snapshot: []model.FormattedMatch{
+			{ID: 1, Campeonato: "campeonato_2021", Status: model.MatchInPlay},
+			{ID: 2, Campeonato: "campeonato_2014", Status: model.MatchInPlay},
+		},
+		events: make(chan model.LiveEvent),
+	}
+	football := &liveFootball{leader: "Arsenal FC"}
+	hub := &Hub{Live: live, Football: football, Interval: time.Hour, MaxSubscriptions: 2, Logger: newTestLogger()}
+	t.Cleanup(hub.Close)
+
+	fan := hub.Register()
+
+	t.Run("when subscribing, should acknowledge and send the known state", func(t *testing.T) {
+		is.Nil(hub.Subscribe(fan, 2021))
+
+		is.Equal(model.SocketMessage{Type: model.SocketSubscribed, Campeonato: "campeonato_2021"}, next(t, fan))
+		match := next(t, fan)
+		is.Equal(model.SocketMatch, match.Type)
+		is.Equal(1, match.Partida.ID)
+
+		standings := next(t, fan)
+		is.Equal(model.SocketStandings, standings.Type)
+		is.Equal("Arsenal FC", standings.Classificacao[0].Time)
+		scorers := next(t, fan)
+		is.Equal(model.SocketScorers, scorers.Type)
+		is.Equal("Bukayo Saka", scorers.Artilharia[0].Jogador)
+	})
+
+	t.Run("when a live match changes, should only notify its competition", func(t *testing.T) {
+		two := 2
+		live.events <- model.LiveEvent{Type: model.LiveMatchUpdate, Partida: model.FormattedMatch{ID: 2, Campeonato: "campeonato_2014"}}
+		live.events <- model.LiveEvent{Type: model.LiveMatchEnded, Partida: model.FormattedMatch{ID: 1, Campeonato: "campeonato_2021", GolsMandante: &two}}
+
+		ended := next(t, fan)
+		is.Equal(model.SocketMatchEnded, ended.Type)
+		is.Equal(2, *ended.Partida.GolsMandante)
+	})
+
+	t.Run("when standings did not change, should not resend them", func(t *testing.T) {
+		feed := hub.competitions[2021]
+		hub.fetch(2021, feed)
+		is.Empty(fan.Send)
+
+		football.leader = "Chelsea FC"
+		hub.fetch(2021, feed)
+		message := next(t, fan)
+		is.Equal(model.SocketStandings, message.Type)
+		is.Equal("Chelsea FC", message.Classificacao[0].Time)
+		is.Equal(model.SocketScorers, next(t, fan).Type)
+	})
+
+	t.Run("when over the subscription limit, should refuse", func(t *testing.T) {
+		is.Nil(hub.Subscribe(fan, 2014))
+		is.Equal(model.SocketSubscribed, next(t, fan).Type)
+		is.ErrorIs(hub.Subscribe(fan, 2002), ErrTooManySubscriptions)
+	})
+
+	t.Run("when unsubscribing, should stop notifying the competition", func(t *testing.T) {
+		hub.Unsubscribe(fan, 2014)
+		for message := next(t, fan); message.Type != model.SocketUnsubscribed; message = next(t, fan) {
+		}
+		live.events <- model.LiveEvent{Type: model.LiveMatchUpdate, Partida: model.FormattedMatch{ID: 3, Campeonato: "campeonato_2014"}}
+		hub.Reply(fan, model.SocketMessage{Type: model.SocketPong})
+		is.Equal(model.SocketPong, next(t, fan).Type)
+	})
+
+	t.Run("when a client lags behind, should drop it without blocking the others", func(t *testing.T) {
+		slow := hub.Register()
+		is.Nil(hub.Subscribe(slow, 2021))
+		for range hubBuffer {
+			hub.Reply(slow, model.SocketMessage{Type: model.SocketPong})
+		}
+
+		hub.Reply(fan, model.SocketMessage{Type: model.SocketPong})
+		is.Equal(model.SocketPong, next(t, fan).Type)
+		for range hubBuffer {
+			<-slow.Send
+		}
+		_, ok := <-slow.Send
+		is.False(ok)
+	})
+
+	t.Run("when the last client leaves, should stop following", func(t *testing.T) {
+		hub.Unregister(fan)
+		for range fan.Send {
+		}
+
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		is.Nil(hub.stop)
+		is.Empty(hub.competitions)
+	})
+}
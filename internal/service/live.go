@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fut-app/internal/model"
+)
+
+// liveBuffer is the number of events a subscriber may lag behind before it
+// is dropped.
+const liveBuffer = 32
+
+// LiveSubscriber streams the changes to live matches.
+type LiveSubscriber interface {
+	Subscribe() ([]model.FormattedMatch, <-chan model.LiveEvent, func())
+}
+
+// LiveScores shares one upstream poller of live matches between every
+// subscriber, so N clients cost one football app call per interval. The
+// poller only runs while someone is subscribed.
+type LiveScores struct {
+	Football FootballAPI
+	Interval time.Duration
+	Logger   *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan model.LiveEvent]struct{}
+	matches     map[int]model.FormattedMatch
+	generation  int
+	stop        context.CancelFunc
+	closed      bool
+}
+
+func NewLiveScores(football Football, interval time.Duration, logger *slog.Logger) *LiveScores {
+	return &LiveScores{
+		Football: &football,
+		Interval: interval,
+		Logger:   logger,
+	}
+}
+
+// Subscribe returns the live matches known so far and a channel with every
+// later change. The channel is closed when the subscriber lags behind or
+// the hub closes; unsubscribe must be called once done.
+func (l *LiveScores) Subscribe() ([]model.FormattedMatch, <-chan model.LiveEvent, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make(chan model.LiveEvent, liveBuffer)
+	if l.closed {
+		close(events)
+		return nil, events, func() {}
+	}
+
+	if l.subscribers == nil {
+		l.subscribers = make(map[chan model.LiveEvent]struct{})
+	}
+	l.subscribers[events] = struct{}{}
+	if l.stop == nil {
+		l.start()
+	}
+
+	snapshot := make([]model.FormattedMatch, 0, len(l.matches))
+	for _, match := range l.matches {
+		snapshot = append(snapshot, match)
+	}
+	slices.SortFunc(snapshot, func(a, b model.FormattedMatch) int {
+		return a.ID - b.ID
+	})
+
+	var once sync.Once
+	return snapshot, events, func() {
+		once.Do(func() { l.unsubscribe(events) })
+	}
+}
+
+func (l *LiveScores) unsubscribe(events chan model.LiveEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.subscribers[events]; !ok {
+		return
+	}
+	delete(l.subscribers, events)
+	close(events)
+	if len(l.subscribers) == 0 {
+		l.halt()
+	}
+}
+
+// Close ends every subscription, e.g. on server shutdown.
+func (l *LiveScores) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	for events := range l.subscribers {
+		delete(l.subscribers, events)
+		close(events)
+	}
+	l.halt()
+}
+
+// start launches the poller. Callers hold mu.
+func (l *LiveScores) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.stop = cancel
+	l.generation++
+	l.matches = make(map[int]model.FormattedMatch)
+	go l.run(ctx, l.generation)
+}
+
+// halt stops the poller and forgets its state. Callers hold mu.
+func (l *LiveScores) halt() {
+	if l.stop != nil {
+		l.stop()
+		l.stop = nil
+	}
+	l.matches = nil
+}
+
+func (l *LiveScores) run(ctx context.Context, generation int) {
+	ticker := time.NewTicker(l.Interval)
+	defer ticker.Stop()
+	for {
+		l.poll(ctx, generation)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the live matches and broadcasts what changed since the last
+// poll of the same generation.
+func (l *LiveScores) poll(ctx context.Context, generation int) {
+	response, err := l.Football.Matches(ctx, model.MatchFilter{Status: model.MatchLive})
+	if err != nil {
+		if ctx.Err() == nil {
+			l.Logger.WarnContext(ctx, "failed to fetch live matches", "error", err)
+		}
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if generation != l.generation || l.stop == nil {
+		return
+	}
+
+	live := make(map[int]model.FormattedMatch, len(response.Matches))
+	for _, match := range model.FormatMatches(response.Matches) {
+		live[match.ID] = match
+		if previous, ok := l.matches[match.ID]; !ok || changed(previous, match) {
+			l.broadcast(model.LiveEvent{Type: model.LiveMatchUpdate, Partida: match})
+		}
+	}
+	for id, match := range l.matches {
+		if _, ok := live[id]; !ok {
+			l.broadcast(model.LiveEvent{Type: model.LiveMatchEnded, Partida: match})
+		}
+	}
+	if l.stop != nil {
+		l.matches = live
+	}
+}
+
+func changed(previous, current model.FormattedMatch) bool {
+	return previous.Status != current.Status ||
+		!equalGoals(previous.GolsMandante, current.GolsMandante) ||
+		!equalGoals(previous.GolsVisitante, current.GolsVisitante)
+}
+
+func equalGoals(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// broadcast drops subscribers whose buffer is full instead of blocking the
+// others; they reconnect and start from a fresh snapshot. Callers hold mu.
+func (l *LiveScores) broadcast(event model.LiveEvent) {
+	for events := range l.subscribers {
+		select {
+		case events <- event:
+		default:
+			l.Logger.Warn("dropping slow live scores subscriber")
+			delete(l.subscribers, events)
+			close(events)
+		}
+	}
+	if len(l.subscribers) == 0 {
+		l.halt()
+	}
+}
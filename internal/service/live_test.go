@@ -0,0 +1,117 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFootball serves the live matches set by the test and counts the
+// upstream calls.
+type countingFootball struct {
+	liveFootball
+	mu      sync.Mutex
+	calls   int
+	matches []model.Match
+}
+
+func (f *countingFootball) Matches(context.Context, model.MatchFilter) (*model.MatchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return &model.MatchResponse{Matches: f.matches}, nil
+}
+
+func (f *countingFootball) set(matches ...model.Match) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.matches = matches
+}
+
+func receive(t *testing.T, events <-chan model.LiveEvent) model.LiveEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no live event received")
+		return model.LiveEvent{}
+	}
+}
+
+func TestLiveScores(t *testing.T) {
+	is := require.New(t)
+	goals := func(home, away int) model.Score {
+		return model.Score{FullTime: model.ScoreSet{Home: &home, Away: &away}}
+	}
+	football := &countingFootball{}
+	football.set(model.Match{ID: 1, Status: model.MatchInPlay, Score: goals(0, 0), Competition: model.Competition{ID: 2021}})
+
+	// The interval is long enough for the test to drive every poll after
+	// the one made on start.
+	live := &LiveScores{Football: football, Interval: time.Hour, Logger: newTestLogger()}
+	t.Cleanup(live.Close)
+
+	_, first, unsubscribeFirst := live.Subscribe()
+	event := receive(t, first)
+	is.Equal(model.LiveMatchUpdate, event.Type)
+	is.Equal("campeonato_2021", event.Partida.Campeonato)
+
+	t.Run("when several clients subscribe, should share the upstream poller", func(t *testing.T) {
+		snapshot, second, unsubscribe := live.Subscribe()
+		defer unsubscribe()
+		is.Len(snapshot, 1)
+		is.Equal(1, snapshot[0].ID)
+
+		football.set(model.Match{ID: 1, Status: model.MatchInPlay, Score: goals(1, 0)})
+		live.poll(context.Background(), live.generation)
+
+		for _, events := range []<-chan model.LiveEvent{first, second} {
+			event := receive(t, events)
+			is.Equal(model.LiveMatchUpdate, event.Type)
+			is.Equal(1, *event.Partida.GolsMandante)
+		}
+		is.Equal(2, football.calls)
+	})
+
+	t.Run("when nothing changed, should not send events", func(t *testing.T) {
+		live.poll(context.Background(), live.generation)
+		is.Empty(first)
+	})
+
+	t.Run("when a match leaves the live list, should send its last state", func(t *testing.T) {
+		football.set()
+		live.poll(context.Background(), live.generation)
+
+		event := receive(t, first)
+		is.Equal(model.LiveMatchEnded, event.Type)
+		is.Equal(1, event.Partida.ID)
+	})
+
+	t.Run("when a subscriber lags behind, should drop it", func(t *testing.T) {
+		for i := range liveBuffer + 1 {
+			football.set(model.Match{ID: 1, Status: model.MatchInPlay, Score: goals(i, 0)})
+			live.poll(context.Background(), live.generation)
+		}
+		for range liveBuffer {
+			receive(t, first)
+		}
+		_, ok := <-first
+		is.False(ok)
+		unsubscribeFirst()
+	})
+
+	t.Run("when the last subscriber leaves, should stop polling", func(t *testing.T) {
+		live.mu.Lock()
+		defer live.mu.Unlock()
+		is.Nil(live.stop)
+		is.Empty(live.subscribers)
+	})
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/middleware"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/fut-app/pkg/totp"
+)
+
+var (
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrTwoFactorLocked      = errors.New("too many invalid two-factor codes, try again later")
+	ErrTokenFailed          = errors.New("something is wrong with provided credentials")
+)
+
+// ScopeError reports scopes requested beyond those the credential holds.
+type ScopeError struct {
+	inner error
+}
+
+func (err *ScopeError) Error() string {
+	return err.inner.Error()
+}
+
+func (err *ScopeError) Unwrap() error {
+	return err.inner
+}
+
+// LoginResult is the outcome of a login step: an access token, or the
+// challenge or enrollment token the caller must go through first.
+type LoginResult struct {
+	Credential  *model.Credential
+	Scopes      model.Scopes
+	AccessToken string
+	Challenge   model.LoginChallenge
+}
+
+// Login runs the sign-in steps shared by the HTTP and gRPC APIs. Callers
+// map its errors to their transport and record the audit events, which
+// need the client address.
+type Login struct {
+	Credentials CredentialsRepository
+	SecretKey   string
+	Config      config.AuthConfig
+	Hasher      hasher.Hasher
+	Logger      *slog.Logger
+
+	// dummyHash is checked against the password of unknown users, so that
+	// they take as long to reject as known ones.
+	dummyHash string
+}
+
+func NewLogin(
+	credentials CredentialsRepository,
+	secretKey string,
+	cfg config.AuthConfig,
+	passwords hasher.Hasher,
+	logger *slog.Logger,
+) Login {
+	dummyHash, err := passwords.Hash(rand.Text())
+	if err != nil {
+		logger.Error("failed to hash the dummy password", "error", err)
+	}
+	return Login{
+		Credentials: credentials,
+		SecretKey:   secretKey,
+		Config:      cfg,
+		Hasher:      passwords,
+		Logger:      logger,
+		dummyHash:   dummyHash,
+	}
+}
+
+// Password checks the user and password, upgrades an outdated hash and
+// completes the login with the requested scopes.
+func (l Login) Password(ctx context.Context, req model.AuthRequest) (LoginResult, error) {
+	credential, err := l.Credentials.FindCredentials(ctx, &model.Credential{User: req.User})
+	if err != nil {
+		hasher.Verify(l.dummyHash, req.Password)
+		l.Logger.WarnContext(ctx, "invalid credentials", "target", req.User)
+		return LoginResult{}, ErrInvalidPassword
+	}
+	if !credential.CheckPassword(req.Password) {
+		l.Logger.WarnContext(ctx, "invalid credentials", "target", req.User)
+		return LoginResult{}, ErrInvalidPassword
+	}
+
+	l.rehashIfOutdated(ctx, credential, req.Password)
+
+	scopes, err := credential.GrantScopes(model.ParseScopes(req.Scope))
+	if err != nil {
+		l.Logger.WarnContext(ctx, "invalid scope requested", "target", req.User, "error", err)
+		return LoginResult{Credential: credential}, &ScopeError{inner: err}
+	}
+
+	return l.Complete(ctx, credential, scopes)
+}
+
+// Complete answers a login whose first factor succeeded: with a two-factor
+// challenge, an enrollment token for admins who must enroll, or the access
+// token.
+func (l Login) Complete(ctx context.Context, credential *model.Credential, scopes model.Scopes) (LoginResult, error) {
+	result := LoginResult{Credential: credential, Scopes: scopes}
+
+	if credential.TOTPEnabled {
+		// Storing the challenge id lets only the latest challenge be
+		// redeemed.
+		challenge, err := credential.GenerateChallengeToken(l.SecretKey, scopes, l.Config.ChallengeTTL)
+		if err == nil {
+			err = l.Credentials.UpdateCredentials(ctx, credential, "mfa_challenge_id")
+		}
+		if err != nil {
+			l.Logger.ErrorContext(ctx, "failed to generate challenge token", "error", err)
+			return result, ErrTokenFailed
+		}
+		result.Challenge = model.LoginChallenge{MFARequired: true, ChallengeToken: challenge}
+		return result, nil
+	}
+
+	if l.Config.RequireAdmin2FA && credential.Role == model.RoleAdmin {
+		// A token without scopes only reaches the two-factor enrollment
+		// endpoints.
+		token, err := credential.GenerateToken(l.SecretKey, nil)
+		if err != nil {
+			l.Logger.ErrorContext(ctx, "failed to generate enrollment token", "error", err)
+			return result, ErrTokenFailed
+		}
+		l.Logger.WarnContext(ctx, "admin login without two-factor enrollment", "target", credential.User)
+		result.Challenge = model.LoginChallenge{EnrollmentRequired: true, EnrollmentToken: token}
+		return result, nil
+	}
+
+	token, err := credential.GenerateToken(l.SecretKey, scopes)
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "failed to generate token", "error", err)
+		return result, ErrTokenFailed
+	}
+	result.AccessToken = token
+	return result, nil
+}
+
+// TwoFactor redeems a challenge token with a TOTP or recovery code and
+// issues the access token with the scopes of the challenge. Failures count
+// towards Config.MFAMaxFailures, after which the credential is locked for
+// Config.MFALockout.
+func (l Login) TwoFactor(ctx context.Context, req model.TwoFactorLoginRequest) (LoginResult, error) {
+	claims, err := middleware.VerifyToken(req.ChallengeToken, l.SecretKey)
+	if err != nil || claims["purpose"] != model.PurposeMFA {
+		return LoginResult{}, ErrInvalidChallenge
+	}
+	user, _ := claims["username"].(string)
+	scope, _ := claims["scope"].(string)
+	challengeID, _ := claims["jti"].(string)
+
+	credential, err := l.Credentials.FindCredentials(ctx, &model.Credential{User: user})
+	if err != nil || !credential.TOTPEnabled || challengeID == "" || challengeID != credential.MFAChallengeID {
+		return LoginResult{}, ErrInvalidChallenge
+	}
+	result := LoginResult{Credential: credential, Scopes: model.ParseScopes(scope)}
+	if credential.MFALocked(time.Now()) {
+		return result, ErrTwoFactorLocked
+	}
+
+	if req.RecoveryCode != "" {
+		err = l.Credentials.UseRecoveryCode(ctx, credential.ID, model.HashRecoveryCode(req.RecoveryCode))
+		if err == nil {
+			l.Logger.WarnContext(ctx, "recovery code used", "target", credential.User)
+		}
+	} else {
+		err = l.VerifyCode(ctx, credential, req.Code)
+	}
+	if err != nil {
+		return result, l.twoFactorFailed(ctx, credential, err)
+	}
+
+	if err := l.Credentials.RedeemTwoFactorChallenge(ctx, credential.ID, challengeID); err != nil {
+		return LoginResult{}, ErrInvalidChallenge
+	}
+
+	token, err := credential.GenerateToken(l.SecretKey, result.Scopes)
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "failed to generate token", "error", err)
+		return result, ErrTokenFailed
+	}
+	result.AccessToken = token
+	return result, nil
+}
+
+// CheckCode verifies a code of the signed in user, as activating or disabling
+// two-factor requires. Wrong codes count towards the same lockout as the
+// second login step, so a stolen session cannot brute force the code.
+func (l Login) CheckCode(ctx context.Context, credential *model.Credential, code string) error {
+	if credential.MFALocked(time.Now()) {
+		return ErrTwoFactorLocked
+	}
+	if err := l.VerifyCode(ctx, credential, code); err != nil {
+		return l.twoFactorFailed(ctx, credential, err)
+	}
+	return nil
+}
+
+// twoFactorFailed records a wrong code and reports whether it locked the
+// credential.
+func (l Login) twoFactorFailed(ctx context.Context, credential *model.Credential, err error) error {
+	l.Logger.WarnContext(ctx, "invalid two-factor code", "target", credential.User, "error", err)
+	locked, err := l.Credentials.RecordTwoFactorFailure(ctx, credential.ID, l.Config.MFAMaxFailures, l.Config.MFALockout)
+	if err == nil && locked {
+		l.Logger.WarnContext(ctx, "two-factor locked", "target", credential.User)
+		return ErrTwoFactorLocked
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// VerifyCode validates a TOTP code and records its time step so the same
+// code cannot be replayed.
+func (l Login) VerifyCode(ctx context.Context, credential *model.Credential, code string) error {
+	step, ok := totp.Validate(credential.TOTPSecret, code, time.Now())
+	if !ok || step <= credential.TOTPLastStep {
+		return ErrInvalidTwoFactorCode
+	}
+
+	credential.TOTPLastStep = step
+	if err := l.Credentials.UpdateCredentials(ctx, credential, "totp_last_step"); err != nil {
+		return ErrUpdateFailed
+	}
+	return nil
+}
+
+// rehashIfOutdated upgrades the stored hash after a successful login when
+// it was produced with another algorithm or outdated parameters. Failures
+// are only logged: the login itself already succeeded.
+func (l Login) rehashIfOutdated(ctx context.Context, credential *model.Credential, password string) {
+	if !l.Hasher.NeedsRehash(credential.EncryptedPassword) {
+		return
+	}
+
+	encryptedPassword, err := l.Hasher.Hash(password)
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "failed to rehash password", "target", credential.User, "error", err)
+		return
+	}
+	credential.EncryptedPassword = encryptedPassword
+	if err := l.Credentials.UpdateCredentials(ctx, credential, "encrypted_password"); err != nil {
+		return
+	}
+	l.Logger.InfoContext(ctx, "password rehashed", "target", credential.User)
+}
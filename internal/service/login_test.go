@@ -0,0 +1,47 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/hasher"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginPassword(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	passwords := &hasher.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	db := NewDatabase(newTestDatabase(t), newTestLogger())
+	login := NewLogin(&db, "D41D8CD98F00B204E9800998ECF8427E", config.AuthConfig{}, passwords, newTestLogger())
+
+	request := model.AuthRequest{User: "fan", Password: "Xpto-123456"}
+	credential, err := request.ParseAuthRequestToCredential(passwords)
+	is.Nil(err)
+	is.Nil(db.CreateCredentials(ctx, credential))
+
+	t.Run("when the user is unknown, should check a dummy hash made with the current parameters", func(t *testing.T) {
+		is.NotEmpty(login.dummyHash)
+		is.False(passwords.NeedsRehash(login.dummyHash))
+
+		_, err := login.Password(ctx, model.AuthRequest{User: "ghost", Password: "Xpto-123456"})
+		is.Equal(ErrInvalidPassword, err)
+	})
+
+	t.Run("when the password is wrong, should return the same error", func(t *testing.T) {
+		_, err := login.Password(ctx, model.AuthRequest{User: "fan", Password: "wrong"})
+		is.Equal(ErrInvalidPassword, err)
+	})
+
+	t.Run("when the password is right, should issue a token", func(t *testing.T) {
+		result, err := login.Password(ctx, request)
+		is.Nil(err)
+		is.NotEmpty(result.AccessToken)
+	})
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrganizationFailed   = errors.New("failed to store organization")
+)
+
+// organizationScope shows organization members only their own
+// organization.
+func organizationScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orgID, ok := tenant.Organization(ctx); ok {
+			return db.Where("id = ?", orgID)
+		}
+		return db
+	}
+}
+
+func (d *CredentialsDatabase) CreateOrganization(ctx context.Context, organization *model.Organization) error {
+	if err := d.Gorm.WithContext(ctx).Create(organization).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to create organization", "slug", organization.Slug, "error", err)
+		return ErrOrganizationFailed
+	}
+	return nil
+}
+
+func (d *CredentialsDatabase) FindOrganization(ctx context.Context, slug string) (*model.Organization, error) {
+	var organization model.Organization
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(organizationScope(ctx)).
+		Where(&model.Organization{Slug: slug}).
+		First(&organization).Error
+	if err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return &organization, nil
+}
+
+func (d *CredentialsDatabase) ListOrganizations(ctx context.Context) ([]model.Organization, error) {
+	var organizations []model.Organization
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(organizationScope(ctx)).
+		Order("slug").
+		Find(&organizations).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list organizations", "error", err)
+		return nil, ErrOrganizationFailed
+	}
+	return organizations, nil
+}
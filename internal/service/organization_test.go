@@ -0,0 +1,75 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fut-app/internal/model"
+	"github.com/fut-app/pkg/tenant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantScope(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := NewDatabase(newTestDatabase(t), newTestLogger())
+	tricolor := &model.Organization{Slug: "tricolor", Name: "Torcida Tricolor"}
+	alviverde := &model.Organization{Slug: "alviverde", Name: "Torcida Alviverde"}
+	is.Nil(db.CreateOrganization(ctx, tricolor))
+	is.Nil(db.CreateOrganization(ctx, alviverde))
+
+	tricolorCtx := tenant.WithOrganization(ctx, tricolor.ID)
+	alviverdeCtx := tenant.WithOrganization(ctx, alviverde.ID)
+	is.Nil(db.CreateCredentials(tricolorCtx, &model.Credential{User: "tricolor-fan", EncryptedPassword: "x"}))
+	is.Nil(db.CreateCredentials(alviverdeCtx, &model.Credential{User: "verde-fan", EncryptedPassword: "x"}))
+
+	t.Run("when scoped to an organization, should only find its credentials", func(t *testing.T) {
+		credential, err := db.FindCredentials(tricolorCtx, &model.Credential{User: "tricolor-fan"})
+		is.Nil(err)
+		is.Equal(tricolor.ID, credential.OrganizationID())
+
+		_, err = db.FindCredentials(tricolorCtx, &model.Credential{User: "verde-fan"})
+		is.ErrorIs(err, ErrUserNotFound)
+	})
+
+	t.Run("when unscoped, should find every credential", func(t *testing.T) {
+		credential, err := db.FindCredentials(ctx, &model.Credential{User: "verde-fan"})
+		is.Nil(err)
+		is.Equal(alviverde.ID, credential.OrganizationID())
+	})
+
+	t.Run("when scoped to another organization, should not update or delete", func(t *testing.T) {
+		credential, err := db.FindCredentials(ctx, &model.Credential{User: "verde-fan"})
+		is.Nil(err)
+
+		credential.Email = "intruder@example.com"
+		is.Nil(db.UpdateCredentials(tricolorCtx, credential, "email"))
+		stored, err := db.FindCredentials(ctx, &model.Credential{User: "verde-fan"})
+		is.Nil(err)
+		is.Empty(stored.Email)
+
+		is.ErrorIs(db.DeleteCredentials(tricolorCtx, credential), ErrUserNotFound)
+	})
+
+	t.Run("when scoped to another organization, should not touch recovery codes", func(t *testing.T) {
+		credential, err := db.FindCredentials(ctx, &model.Credential{User: "verde-fan"})
+		is.Nil(err)
+		is.Nil(db.ReplaceRecoveryCodes(alviverdeCtx, credential.ID, []string{"hash"}))
+
+		is.ErrorIs(db.UseRecoveryCode(tricolorCtx, credential.ID, "hash"), ErrInvalidRecoveryCode)
+		is.Nil(db.UseRecoveryCode(alviverdeCtx, credential.ID, "hash"))
+	})
+
+	t.Run("when scoped to an organization, should only list it", func(t *testing.T) {
+		organizations, err := db.ListOrganizations(tricolorCtx)
+		is.Nil(err)
+		is.Len(organizations, 1)
+
+		_, err = db.FindOrganization(tricolorCtx, "alviverde")
+		is.ErrorIs(err, ErrOrganizationNotFound)
+	})
+}
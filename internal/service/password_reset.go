@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrResetTokenInvalid = errors.New("invalid or already used reset token")
+	ErrResetTokenExpired = errors.New("reset token expired")
+	ErrSessionRevoked    = errors.New("session revoked")
+)
+
+func (d *CredentialsDatabase) CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error {
+	if err := d.Gorm.WithContext(ctx).Create(token).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to create password reset token", "error", err)
+		return ErrCreationFailed
+	}
+	return nil
+}
+
+// FindPasswordResetToken resolves an unused plain token, with its
+// credential.
+func (d *CredentialsDatabase) FindPasswordResetToken(ctx context.Context, raw string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := d.Gorm.
+		WithContext(ctx).
+		Preload("Credential").
+		Scopes(tenantCredentialScope(ctx)).
+		Where("hashed_token = ? AND used_at IS NULL", model.HashResetToken(raw)).
+		First(&token).Error
+	if err != nil {
+		return nil, ErrResetTokenInvalid
+	}
+	if token.Expired(time.Now()) {
+		return nil, ErrResetTokenExpired
+	}
+	return &token, nil
+}
+
+// ResetPassword claims the token, burns every other pending token of the
+// same credential, stores the new password and revokes the existing
+// sessions. Only one of concurrent resets with the same token succeeds.
+func (d *CredentialsDatabase) ResetPassword(ctx context.Context, token *model.PasswordResetToken, encryptedPassword string) error {
+	now := time.Now()
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Scopes(tenantCredentialScope(ctx)).
+			Model(&model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return ErrResetTokenInvalid
+		}
+
+		err := tx.
+			Scopes(tenantCredentialScope(ctx)).
+			Model(&model.PasswordResetToken{}).
+			Where("credential_id = ? AND used_at IS NULL", token.CredentialID).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Scopes(tenantScope(ctx)).
+			Model(&model.Credential{ID: token.CredentialID}).
+			Updates(map[string]any{
+				"encrypted_password":  encryptedPassword,
+				"sessions_revoked_at": now,
+				"updated_at":          now,
+			}).Error
+	})
+	if errors.Is(err, ErrResetTokenInvalid) {
+		return err
+	}
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to reset password", "target", token.Credential.User, "error", err)
+		return ErrUpdateFailed
+	}
+	return nil
+}
+
+// ValidateSession rejects tokens issued before the user's sessions were
+// revoked.
+func (d *CredentialsDatabase) ValidateSession(ctx context.Context, user string, issuedAt time.Time) error {
+	credential, err := d.FindCredentials(ctx, &model.Credential{User: user})
+	if err != nil {
+		return err
+	}
+	if credential.SessionRevoked(issuedAt) {
+		return ErrSessionRevoked
+	}
+	return nil
+}
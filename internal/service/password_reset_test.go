@@ -0,0 +1,60 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordReset(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	db := NewDatabase(newTestDatabase(t), newTestLogger())
+
+	credential := &model.Credential{User: "fan", EncryptedPassword: "old"}
+	is.Nil(db.CreateCredentials(ctx, credential))
+
+	expired, expiredRaw, err := model.NewPasswordResetToken(credential.ID, -time.Minute)
+	is.Nil(err)
+	is.Nil(db.CreatePasswordResetToken(ctx, expired))
+	valid, validRaw, err := model.NewPasswordResetToken(credential.ID, time.Minute)
+	is.Nil(err)
+	is.Nil(db.CreatePasswordResetToken(ctx, valid))
+
+	_, err = db.FindPasswordResetToken(ctx, expiredRaw)
+	is.ErrorIs(err, ErrResetTokenExpired)
+	_, err = db.FindPasswordResetToken(ctx, "fut_rst_unknown")
+	is.ErrorIs(err, ErrResetTokenInvalid)
+
+	token, err := db.FindPasswordResetToken(ctx, validRaw)
+	is.Nil(err)
+	is.Equal("fan", token.Credential.User)
+	is.Nil(db.ResetPassword(ctx, token, "new"))
+
+	stored, err := db.FindCredentials(ctx, &model.Credential{User: "fan"})
+	is.Nil(err)
+	is.Equal("new", stored.EncryptedPassword)
+	is.NotNil(stored.SessionsRevokedAt)
+
+	_, err = db.FindPasswordResetToken(ctx, validRaw)
+	is.ErrorIs(err, ErrResetTokenInvalid)
+
+	// A used token must not be redeemable while the credential has another
+	// pending one.
+	pending, pendingRaw, err := model.NewPasswordResetToken(credential.ID, time.Minute)
+	is.Nil(err)
+	is.Nil(db.CreatePasswordResetToken(ctx, pending))
+	is.ErrorIs(db.ResetPassword(ctx, token, "again"), ErrResetTokenInvalid)
+	_, err = db.FindPasswordResetToken(ctx, pendingRaw)
+	is.Nil(err)
+
+	is.ErrorIs(db.ValidateSession(ctx, "fan", time.Now().Add(-time.Hour)), ErrSessionRevoked)
+	is.Nil(db.ValidateSession(ctx, "fan", time.Now().Add(time.Hour)))
+}
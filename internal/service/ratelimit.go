@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRateLimitFailed = errors.New("failed to count request")
+
+// RateLimitDatabase shares rate limit counters between replicas.
+type RateLimitDatabase struct {
+	Gorm   *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewRateLimitDatabase(gorm *gorm.DB, logger *slog.Logger) RateLimitDatabase {
+	return RateLimitDatabase{
+		Gorm:   gorm,
+		Logger: logger,
+	}
+}
+
+func (d *RateLimitDatabase) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, int, error) {
+	windowStart = windowStart.UTC()
+	previousStart := windowStart.Add(-window)
+
+	var counters []model.RateLimitCounter
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}, {Name: "window_start"}},
+				DoUpdates: clause.Assignments(map[string]any{"hits": gorm.Expr("rate_limit_counters.hits + 1")}),
+			}).
+			Create(&model.RateLimitCounter{Key: key, WindowStart: windowStart, Hits: 1}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("key = ? AND window_start < ?", key, previousStart).
+			Delete(&model.RateLimitCounter{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Where("key = ? AND window_start IN ?", key, []time.Time{previousStart, windowStart}).
+			Find(&counters).Error
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to count request", "key", key, "error", err)
+		return 0, 0, ErrRateLimitFailed
+	}
+
+	var current, previous int
+	for _, counter := range counters {
+		if counter.WindowStart.Equal(windowStart) {
+			current = counter.Hits
+		} else {
+			previous = counter.Hits
+		}
+	}
+	return current, previous, nil
+}
@@ -0,0 +1,58 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitDatabase(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	ctx := context.Background()
+
+	gormDB := newTestDatabase(t)
+	db := NewRateLimitDatabase(gormDB, newTestLogger())
+	window := time.Minute
+	start := time.Date(2026, 5, 10, 16, 0, 0, 0, time.UTC)
+
+	t.Run("when hitting the same window, should increment the current count", func(t *testing.T) {
+		for range 2 {
+			_, _, err := db.Hit(ctx, "user:fan", start, window)
+			is.Nil(err)
+		}
+		current, previous, err := db.Hit(ctx, "user:fan", start, window)
+		is.Nil(err)
+		is.Equal(3, current)
+		is.Equal(0, previous)
+	})
+
+	t.Run("when the window moves on, should report the previous count", func(t *testing.T) {
+		current, previous, err := db.Hit(ctx, "user:fan", start.Add(window), window)
+		is.Nil(err)
+		is.Equal(1, current)
+		is.Equal(3, previous)
+	})
+
+	t.Run("when counters are outdated, should delete them", func(t *testing.T) {
+		current, previous, err := db.Hit(ctx, "user:fan", start.Add(5*window), window)
+		is.Nil(err)
+		is.Equal(1, current)
+		is.Equal(0, previous)
+
+		var stored int64
+		is.Nil(gormDB.Model(&model.RateLimitCounter{}).Where("key = ?", "user:fan").Count(&stored).Error)
+		is.Equal(int64(1), stored)
+	})
+
+	t.Run("when keys differ, should count them apart", func(t *testing.T) {
+		current, _, err := db.Hit(ctx, "ip:10.0.0.1", start, window)
+		is.Nil(err)
+		is.Equal(1, current)
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRecoveryCode = errors.New("invalid recovery code")
+	ErrInvalidChallenge    = errors.New("invalid or expired challenge token")
+)
+
+// ReplaceRecoveryCodes discards every previous recovery code of the
+// credential and stores the new hashes.
+func (d *CredentialsDatabase) ReplaceRecoveryCodes(ctx context.Context, credentialID int, hashes []string) error {
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Scopes(tenantCredentialScope(ctx)).
+			Where(&model.RecoveryCode{CredentialID: credentialID}).
+			Delete(&model.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, model.RecoveryCode{
+				CredentialID: credentialID,
+				HashedCode:   hash,
+				CreatedAt:    time.Now(),
+			})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to store recovery codes", "error", err)
+		return ErrUpdateFailed
+	}
+	return nil
+}
+
+// UseRecoveryCode burns an unused recovery code.
+func (d *CredentialsDatabase) UseRecoveryCode(ctx context.Context, credentialID int, hash string) error {
+	result := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Model(&model.RecoveryCode{}).
+		Where("credential_id = ? AND hashed_code = ? AND used_at IS NULL", credentialID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to use recovery code", "error", result.Error)
+		return ErrUpdateFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidRecoveryCode
+	}
+	return nil
+}
+
+// RecordTwoFactorFailure counts a wrong two-factor code. The maxFailures-th
+// failure in a row locks the second login step for lockout and burns the
+// pending challenge, so the user must sign in with the password again. It
+// reports whether the credential got locked.
+func (d *CredentialsDatabase) RecordTwoFactorFailure(ctx context.Context, credentialID int, maxFailures int, lockout time.Duration) (bool, error) {
+	locked := false
+	err := d.Gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		credentials := func() *gorm.DB {
+			return tx.Scopes(tenantScope(ctx)).Model(&model.Credential{}).Where("id = ?", credentialID)
+		}
+
+		err := credentials().Update("mfa_failures", gorm.Expr("mfa_failures + 1")).Error
+		if err != nil {
+			return err
+		}
+		var failures int
+		if err := credentials().Select("mfa_failures").Scan(&failures).Error; err != nil {
+			return err
+		}
+		if failures < maxFailures {
+			return nil
+		}
+
+		locked = true
+		return credentials().Updates(map[string]any{
+			"mfa_failures":     0,
+			"mfa_challenge_id": "",
+			"mfa_locked_until": time.Now().Add(lockout),
+		}).Error
+	})
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to record two-factor failure", "error", err)
+		return false, ErrUpdateFailed
+	}
+	return locked, nil
+}
+
+// RedeemTwoFactorChallenge burns the pending challenge and clears the
+// failure count. A challenge already redeemed, or replaced by a newer
+// login, fails with ErrInvalidChallenge.
+func (d *CredentialsDatabase) RedeemTwoFactorChallenge(ctx context.Context, credentialID int, challengeID string) error {
+	if challengeID == "" {
+		return ErrInvalidChallenge
+	}
+	result := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantScope(ctx)).
+		Model(&model.Credential{}).
+		Where("id = ? AND mfa_challenge_id = ?", credentialID, challengeID).
+		Updates(map[string]any{
+			"mfa_challenge_id": "",
+			"mfa_failures":     0,
+		})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to redeem two-factor challenge", "error", result.Error)
+		return ErrUpdateFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidChallenge
+	}
+	return nil
+}
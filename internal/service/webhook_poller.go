@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+)
+
+// deliveryBatch bounds the deliveries attempted per tick.
+const deliveryBatch = 100
+
+var ErrPrivateNetwork = errors.New("webhook target is in a private network")
+
+// sharedNetworks are not covered by net.IP.IsPrivate but still reach
+// internal hosts: carrier-grade NAT and the NAT64 translation prefixes, which
+// map any IPv4 address, private ones included.
+var sharedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// startedStatuses are the football-data statuses of a match that kicked off.
+var startedStatuses = []string{
+	model.MatchInPlay,
+	model.MatchPaused,
+	"EXTRA_TIME",
+	"PENALTY_SHOOTOUT",
+	model.MatchFinished,
+}
+
+type matchState struct {
+	status string
+	goals  int
+}
+
+type competitionState struct {
+	matches   map[int]matchState
+	standings string
+}
+
+// WebhookPoller diffs the matches and standings of watched competitions
+// between polls, enqueues an event delivery for each subscribed webhook and
+// sends due deliveries, retrying failures with exponential backoff.
+type WebhookPoller struct {
+	Football FootballAPI
+	Webhooks WebhooksRepository
+	Client   *http.Client
+	Config   config.WebhookConfig
+	Logger   *slog.Logger
+
+	state map[int]*competitionState
+}
+
+func NewWebhookPoller(football Football, webhooks WebhooksDatabase, cfg config.WebhookConfig, logger *slog.Logger) *WebhookPoller {
+	return &WebhookPoller{
+		Football: &football,
+		Webhooks: &webhooks,
+		Client:   NewWebhookClient(cfg),
+		Config:   cfg,
+		Logger:   logger,
+	}
+}
+
+// NewWebhookClient returns a client that does not follow redirects and,
+// unless allowed, refuses to connect to loopback, private or shared
+// addresses once names are resolved.
+func NewWebhookClient(cfg config.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = func(_ string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if blockedAddress(host) {
+				return ErrPrivateNetwork
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func blockedAddress(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return true
+	}
+	addr, _ := netip.AddrFromSlice(ip)
+	return slices.ContainsFunc(sharedNetworks, func(network netip.Prefix) bool {
+		return network.Contains(addr.Unmap())
+	})
+}
+
+// Run polls and delivers every PollInterval until ctx is done. Deliveries
+// run on their own loop, so slow targets never delay event detection.
+func (p *WebhookPoller) Run(ctx context.Context) {
+	var loops sync.WaitGroup
+	loops.Go(func() { p.every(ctx, p.Poll) })
+	loops.Go(func() { p.every(ctx, p.Deliver) })
+	loops.Wait()
+}
+
+// every runs step now and then every PollInterval, skipping the ticks missed
+// while a step was still running.
+func (p *WebhookPoller) every(ctx context.Context, step func(context.Context)) {
+	ticker := time.NewTicker(p.Config.PollInterval)
+	defer ticker.Stop()
+	for {
+		step(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll detects the events of every watched competition and enqueues their
+// deliveries. The first poll of a competition only records its state.
+func (p *WebhookPoller) Poll(ctx context.Context) {
+	competitions, err := p.Webhooks.WatchedCompetitions(ctx)
+	if err != nil {
+		return
+	}
+
+	if p.state == nil {
+		p.state = make(map[int]*competitionState)
+	}
+	for id := range p.state {
+		if !slices.Contains(competitions, id) {
+			delete(p.state, id)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, competitionID := range competitions {
+		payloads, err := p.pollCompetition(ctx, competitionID, now)
+		if err != nil {
+			p.Logger.WarnContext(ctx, "failed to poll competition", "competition", competitionID, "error", err)
+			continue
+		}
+		for _, payload := range payloads {
+			p.enqueue(ctx, competitionID, payload, now)
+		}
+	}
+}
+
+func (p *WebhookPoller) pollCompetition(ctx context.Context, competitionID int, now time.Time) ([]model.WebhookPayload, error) {
+	matches, err := p.Football.CompetitionMatches(ctx, competitionID, model.MatchFilter{
+		DateFrom: now.AddDate(0, 0, -1).Format(time.DateOnly),
+		DateTo:   now.AddDate(0, 0, 1).Format(time.DateOnly),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state, known := p.state[competitionID]
+	if !known {
+		state = &competitionState{matches: make(map[int]matchState)}
+	}
+
+	var payloads []model.WebhookPayload
+	live := false
+	for _, match := range matches.Matches {
+		current := matchState{status: match.Status, goals: goals(match)}
+		previous, seen := state.matches[match.ID]
+		state.matches[match.ID] = current
+		if current.status != model.MatchFinished && slices.Contains(startedStatuses, current.status) {
+			live = true
+		}
+		if !seen {
+			continue
+		}
+
+		event := func(name string) {
+			formatted := model.FormatMatches([]model.Match{match})[0]
+			formatted.Campeonato = model.CompetitionID(competitionID)
+			payloads = append(payloads, model.WebhookPayload{
+				Event:      name,
+				Campeonato: model.CompetitionID(competitionID),
+				OcorridoEm: now,
+				Partida:    &formatted,
+			})
+		}
+		if !slices.Contains(startedStatuses, previous.status) && slices.Contains(startedStatuses, current.status) {
+			event(model.WebhookMatchStarted)
+		}
+		for range current.goals - previous.goals {
+			event(model.WebhookGoal)
+		}
+		if previous.status != model.MatchFinished && current.status == model.MatchFinished {
+			event(model.WebhookMatchFinished)
+			live = true
+		}
+	}
+
+	// Standings only move while matches are played, so they are refreshed
+	// then and on the first poll.
+	if !known || live {
+		standings, err := p.Football.CompetitionStandings(ctx, competitionID)
+		if err != nil {
+			p.Logger.WarnContext(ctx, "failed to fetch standings", "competition", competitionID, "error", err)
+		} else {
+			table := model.FormatStandings(*standings)
+			fingerprint := fingerprint(table)
+			if known && state.standings != "" && fingerprint != state.standings {
+				payloads = append(payloads, model.WebhookPayload{
+					Event:         model.WebhookStandingsChanged,
+					Campeonato:    model.CompetitionID(competitionID),
+					OcorridoEm:    now,
+					Classificacao: table,
+				})
+			}
+			state.standings = fingerprint
+		}
+	}
+
+	p.state[competitionID] = state
+	return payloads, nil
+}
+
+func goals(match model.Match) int {
+	var total int
+	if match.Score.FullTime.Home != nil {
+		total += *match.Score.FullTime.Home
+	}
+	if match.Score.FullTime.Away != nil {
+		total += *match.Score.FullTime.Away
+	}
+	return total
+}
+
+func (p *WebhookPoller) enqueue(ctx context.Context, competitionID int, payload model.WebhookPayload, now time.Time) {
+	webhooks, err := p.Webhooks.SubscribedWebhooks(ctx, competitionID, payload.Event)
+	if err != nil || len(webhooks) == 0 {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		p.Logger.ErrorContext(ctx, "failed to marshal webhook payload", "error", err)
+		return
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         payload.Event,
+			Payload:       string(body),
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	p.Webhooks.EnqueueDeliveries(ctx, deliveries)
+}
+
+// Deliver sends every due delivery once through at most Workers concurrent
+// requests, records the outcomes and returns once all of them are done.
+func (p *WebhookPoller) Deliver(ctx context.Context) {
+	deliveries, err := p.Webhooks.DueDeliveries(ctx, time.Now(), deliveryBatch)
+	if err != nil || len(deliveries) == 0 {
+		return
+	}
+
+	queue := make(chan *model.WebhookDelivery)
+	var workers sync.WaitGroup
+	for range min(max(p.Config.Workers, 1), len(deliveries)) {
+		workers.Go(func() {
+			for delivery := range queue {
+				p.attempt(ctx, delivery)
+			}
+		})
+	}
+	for i := range deliveries {
+		queue <- &deliveries[i]
+	}
+	close(queue)
+	workers.Wait()
+}
+
+func (p *WebhookPoller) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+	code, err := p.send(ctx, delivery)
+
+	now := time.Now()
+	switch {
+	case err != nil:
+		delivery.Failed(now, code, err.Error(), p.Config.MaxAttempts, p.Config.Backoff)
+	case code < 200 || code > 299:
+		delivery.Failed(now, code, "unexpected status "+strconv.Itoa(code), p.Config.MaxAttempts, p.Config.Backoff)
+	default:
+		delivery.Status = model.DeliverySucceeded
+		delivery.ResponseCode = code
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	}
+	if delivery.Status == model.DeliveryFailed {
+		p.Logger.WarnContext(ctx, "webhook delivery gave up", "delivery", delivery.ID, "webhook", delivery.WebhookID, "attempts", delivery.Attempts)
+	}
+	p.Webhooks.UpdateDelivery(ctx, delivery)
+}
+
+func (p *WebhookPoller) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fut-app-webhooks")
+	req.Header.Set(model.WebhookEventHeader, delivery.Event)
+	req.Header.Set(model.WebhookDeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(model.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(model.WebhookSignatureHeader, delivery.Webhook.Sign(timestamp, body))
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
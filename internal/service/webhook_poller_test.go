@@ -0,0 +1,267 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// liveFootball serves one competition whose match and standings the test
+// moves between polls.
+type liveFootball struct {
+	match  model.Match
+	leader string
+}
+
+func (f *liveFootball) CompetitionList(context.Context) (*model.CompetitionResponse, error) {
+	return &model.CompetitionResponse{}, nil
+}
+
+func (f *liveFootball) CompetitionMatches(context.Context, int, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{Matches: []model.Match{f.match}}, nil
+}
+
+func (f *liveFootball) CompetitionStandings(context.Context, int) (*model.StandingsResponse, error) {
+	return &model.StandingsResponse{Standings: []model.Standing{
+		{Type: "TOTAL", Table: []model.TableRow{{Position: 1, Team: model.Team{Name: f.leader}}}},
+	}}, nil
+}
+
+func (f *liveFootball) CompetitionScorers(context.Context, int) (*model.ScorersResponse, error) {
+	return &model.ScorersResponse{Scorers: []model.Scorer{
+		{Player: model.Player{Name: "Bukayo Saka"}, Team: model.Team{Name: f.leader}, Goals: 1},
+	}}, nil
+}
+
+func (f *liveFootball) Team(context.Context, int) (*model.Team, error) {
+	return nil, ErrFootballNotFound
+}
+
+func (f *liveFootball) TeamMatches(context.Context, int, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{}, nil
+}
+
+func (f *liveFootball) Matches(context.Context, model.MatchFilter) (*model.MatchResponse, error) {
+	return &model.MatchResponse{Matches: []model.Match{f.match}}, nil
+}
+
+// receiver records the deliveries whose signature matches the secret and
+// answers with status.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	received []model.WebhookPayload
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(model.WebhookTimestampHeader), 10, 64)
+	expected := (&model.Webhook{Secret: r.secret}).Sign(timestamp, body)
+	if req.Header.Get(model.WebhookSignatureHeader) != expected {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload model.WebhookPayload
+	json.Unmarshal(body, &payload)
+	r.received = append(r.received, payload)
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]string, 0, len(r.received))
+	for _, payload := range r.received {
+		events = append(events, payload.Event)
+	}
+	return events
+}
+
+func TestWebhookPoller(t *testing.T) {
+	is := require.New(t)
+	ctx := context.Background()
+
+	gormDB := newTestDatabase(t)
+	credentials := NewDatabase(gormDB, newTestLogger())
+	favorites := NewFavoritesDatabase(gormDB, 50, newTestLogger())
+	db := NewWebhooksDatabase(gormDB, newTestLogger())
+
+	fan := &model.Credential{User: "fan", EncryptedPassword: "x"}
+	is.Nil(credentials.CreateCredentials(ctx, fan))
+	is.Nil(favorites.AddFavorite(ctx, &model.Favorite{CredentialID: fan.ID, Kind: model.FavoriteCompetition, ExternalID: 2021, Name: "Premier League"}))
+
+	target := &receiver{status: http.StatusNoContent}
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	request := model.WebhookRequest{URL: server.URL, Events: model.WebhookEvents{model.WebhookMatchStarted, model.WebhookGoal, model.WebhookMatchFinished}}
+	webhook := request.NewWebhook(fan.ID)
+	is.Nil(db.CreateWebhook(ctx, webhook))
+	target.secret = webhook.Secret
+
+	cfg := config.WebhookConfig{Timeout: time.Second, MaxAttempts: 2, Backoff: 20 * time.Millisecond, AllowPrivateNetworks: true}
+	football := &liveFootball{
+		match:  model.Match{ID: 1, Status: model.MatchTimed, HomeTeam: model.Team{Name: "Arsenal FC"}, AwayTeam: model.Team{Name: "Chelsea FC"}},
+		leader: "Arsenal FC",
+	}
+	poller := &WebhookPoller{Football: football, Webhooks: &db, Client: NewWebhookClient(cfg), Config: cfg, Logger: newTestLogger()}
+	score := func(home, away int) model.Score {
+		return model.Score{FullTime: model.ScoreSet{Home: &home, Away: &away}}
+	}
+
+	t.Run("when polling a competition for the first time, should only record its state", func(t *testing.T) {
+		poller.Poll(ctx)
+		poller.Deliver(ctx)
+		is.Empty(target.events())
+	})
+
+	t.Run("when a match kicks off with a goal, should deliver signed events the webhook subscribed to", func(t *testing.T) {
+		football.match.Status = model.MatchInPlay
+		football.match.Score = score(1, 0)
+		football.leader = "Chelsea FC"
+		poller.Poll(ctx)
+		poller.Deliver(ctx)
+
+		is.Equal([]string{model.WebhookMatchStarted, model.WebhookGoal}, target.events())
+		is.Zero(target.invalid)
+		is.Equal("campeonato_2021", target.received[1].Campeonato)
+		is.Equal(1, *target.received[1].Partida.GolsMandante)
+	})
+
+	t.Run("when the receiver fails, should retry with backoff and log the deliveries", func(t *testing.T) {
+		target.status = http.StatusInternalServerError
+		football.match.Status = model.MatchFinished
+		poller.Poll(ctx)
+		poller.Deliver(ctx)
+
+		deliveries, err := db.ListDeliveries(ctx, fan.ID, webhook.ID, 10)
+		is.Nil(err)
+		is.Len(deliveries, 3)
+		is.Equal(model.WebhookMatchFinished, deliveries[0].Event)
+		is.Equal(model.DeliveryPending, deliveries[0].Status)
+		is.Equal(1, deliveries[0].Attempts)
+		is.Equal(http.StatusInternalServerError, deliveries[0].ResponseCode)
+		is.True(deliveries[0].NextAttemptAt.After(time.Now()))
+
+		poller.Deliver(ctx)
+		is.Len(target.events(), 3, "should wait for the backoff")
+
+		target.status = http.StatusOK
+		time.Sleep(cfg.Backoff)
+		poller.Deliver(ctx)
+
+		deliveries, err = db.ListDeliveries(ctx, fan.ID, webhook.ID, 10)
+		is.Nil(err)
+		is.Equal(model.DeliverySucceeded, deliveries[0].Status)
+		is.Equal(2, deliveries[0].Attempts)
+		is.NotNil(deliveries[0].DeliveredAt)
+	})
+
+	t.Run("when every attempt fails, should give up after the max attempts", func(t *testing.T) {
+		target.status = http.StatusBadGateway
+		football.match = model.Match{ID: 2, Status: model.MatchTimed}
+		poller.Poll(ctx)
+		football.match.Status = model.MatchInPlay
+		poller.Poll(ctx)
+
+		poller.Deliver(ctx)
+		time.Sleep(cfg.Backoff)
+		poller.Deliver(ctx)
+
+		deliveries, err := db.ListDeliveries(ctx, fan.ID, webhook.ID, 1)
+		is.Nil(err)
+		is.Equal(model.DeliveryFailed, deliveries[0].Status)
+		is.Equal(cfg.MaxAttempts, deliveries[0].Attempts)
+	})
+}
+
+func TestWebhookPollerDeliversConcurrently(t *testing.T) {
+	is := require.New(t)
+	ctx := context.Background()
+
+	gormDB := newTestDatabase(t)
+	credentials := NewDatabase(gormDB, newTestLogger())
+	db := NewWebhooksDatabase(gormDB, newTestLogger())
+
+	fan := &model.Credential{User: "fan", EncryptedPassword: "x"}
+	is.Nil(credentials.CreateCredentials(ctx, fan))
+
+	// Every request waits until all workers are busy, so a sequential
+	// delivery would time out instead of succeeding.
+	const workers = 3
+	var arrived sync.WaitGroup
+	arrived.Add(workers)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	}))
+	t.Cleanup(server.Close)
+
+	request := model.WebhookRequest{URL: server.URL, Events: model.WebhookEvents{model.WebhookGoal}}
+	webhook := request.NewWebhook(fan.ID)
+	is.Nil(db.CreateWebhook(ctx, webhook))
+	now := time.Now().UTC()
+	deliveries := make([]model.WebhookDelivery, workers)
+	for i := range deliveries {
+		deliveries[i] = model.WebhookDelivery{WebhookID: webhook.ID, Event: model.WebhookGoal, Payload: "{}", Status: model.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	}
+	is.Nil(db.EnqueueDeliveries(ctx, deliveries))
+
+	cfg := config.WebhookConfig{Timeout: time.Second, MaxAttempts: 1, Workers: workers, AllowPrivateNetworks: true}
+	poller := &WebhookPoller{Webhooks: &db, Client: NewWebhookClient(cfg), Config: cfg, Logger: newTestLogger()}
+	poller.Deliver(ctx)
+
+	stored, err := db.ListDeliveries(ctx, fan.ID, webhook.ID, workers)
+	is.Nil(err)
+	is.Len(stored, workers)
+	for _, delivery := range stored {
+		is.Equal(model.DeliverySucceeded, delivery.Status)
+	}
+}
+
+func TestWebhookClient(t *testing.T) {
+	is := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	t.Run("when private networks are not allowed, should refuse loopback targets", func(t *testing.T) {
+		client := NewWebhookClient(config.WebhookConfig{Timeout: time.Second})
+		_, err := client.Get(server.URL)
+		is.ErrorIs(err, ErrPrivateNetwork)
+	})
+
+	t.Run("when the target is in a shared or translated range, should refuse it", func(t *testing.T) {
+		for host, blocked := range map[string]bool{
+			"100.64.0.1":           true,
+			"100.127.255.254":      true,
+			"64:ff9b::a00:1":       true,
+			"64:ff9b:1::1":         true,
+			"::ffff:100.64.0.1":    true,
+			"100.128.0.1":          false,
+			"2001:db8::1":          false,
+			"93.184.215.14":        false,
+			"::ffff:93.184.215.14": false,
+		} {
+			is.Equal(blocked, blockedAddress(host), host)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fut-app/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrWebhookFailed   = errors.New("failed to store webhook")
+	ErrWebhookList     = errors.New("failed to list webhooks")
+)
+
+type WebhooksDatabase struct {
+	Gorm   *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewWebhooksDatabase(gorm *gorm.DB, logger *slog.Logger) WebhooksDatabase {
+	return WebhooksDatabase{
+		Gorm:   gorm,
+		Logger: logger,
+	}
+}
+
+type WebhooksRepository interface {
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) error
+	ListWebhooks(ctx context.Context, credentialID int) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, credentialID int, id int) error
+	ListDeliveries(ctx context.Context, credentialID int, webhookID int, limit int) ([]model.WebhookDelivery, error)
+	WatchedCompetitions(ctx context.Context) ([]int, error)
+	SubscribedWebhooks(ctx context.Context, competitionID int, event string) ([]model.Webhook, error)
+	EnqueueDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+func (d *WebhooksDatabase) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	if err := d.Gorm.WithContext(ctx).Create(webhook).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to create webhook", "error", err)
+		return ErrWebhookFailed
+	}
+	return nil
+}
+
+func (d *WebhooksDatabase) ListWebhooks(ctx context.Context, credentialID int) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Where(&model.Webhook{CredentialID: credentialID}).
+		Order("id").
+		Find(&webhooks).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list webhooks", "error", err)
+		return nil, ErrWebhookList
+	}
+	return webhooks, nil
+}
+
+func (d *WebhooksDatabase) DeleteWebhook(ctx context.Context, credentialID int, id int) error {
+	result := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Where(&model.Webhook{ID: id, CredentialID: credentialID}).
+		Delete(&model.Webhook{})
+	if result.Error != nil {
+		d.Logger.ErrorContext(ctx, "failed to delete webhook", "webhook", id, "error", result.Error)
+		return ErrWebhookFailed
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook owned by the
+// credential, newest first.
+func (d *WebhooksDatabase) ListDeliveries(ctx context.Context, credentialID int, webhookID int, limit int) ([]model.WebhookDelivery, error) {
+	var webhook model.Webhook
+	err := d.Gorm.
+		WithContext(ctx).
+		Scopes(tenantCredentialScope(ctx)).
+		Where(&model.Webhook{ID: webhookID, CredentialID: credentialID}).
+		First(&webhook).Error
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	var deliveries []model.WebhookDelivery
+	err = d.Gorm.
+		WithContext(ctx).
+		Where(&model.WebhookDelivery{WebhookID: webhook.ID}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list webhook deliveries", "webhook", webhookID, "error", err)
+		return nil, ErrWebhookList
+	}
+	return deliveries, nil
+}
+
+// WatchedCompetitions lists the competitions followed by owners of at
+// least one webhook.
+func (d *WebhooksDatabase) WatchedCompetitions(ctx context.Context) ([]int, error) {
+	var competitions []int
+	err := d.Gorm.
+		WithContext(ctx).
+		Model(&model.Favorite{}).
+		Distinct("favorites.external_id").
+		Joins("JOIN webhooks ON webhooks.credential_id = favorites.credential_id").
+		Where("favorites.kind = ?", model.FavoriteCompetition).
+		Order("favorites.external_id").
+		Pluck("favorites.external_id", &competitions).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list watched competitions", "error", err)
+		return nil, ErrWebhookList
+	}
+	return competitions, nil
+}
+
+// SubscribedWebhooks lists the webhooks whose owner follows the competition
+// and that subscribed to the event.
+func (d *WebhooksDatabase) SubscribedWebhooks(ctx context.Context, competitionID int, event string) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := d.Gorm.
+		WithContext(ctx).
+		Joins("JOIN favorites ON favorites.credential_id = webhooks.credential_id").
+		Where("favorites.kind = ? AND favorites.external_id = ?", model.FavoriteCompetition, competitionID).
+		Order("webhooks.id").
+		Find(&webhooks).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list subscribed webhooks", "competition", competitionID, "error", err)
+		return nil, ErrWebhookList
+	}
+
+	subscribed := webhooks[:0]
+	for _, webhook := range webhooks {
+		if webhook.Events.Has(event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed, nil
+}
+
+func (d *WebhooksDatabase) EnqueueDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.Gorm.WithContext(ctx).Create(&deliveries).Error; err != nil {
+		d.Logger.ErrorContext(ctx, "failed to enqueue webhook deliveries", "error", err)
+		return ErrWebhookFailed
+	}
+	return nil
+}
+
+// DueDeliveries returns pending deliveries whose next attempt is due, with
+// their webhook.
+func (d *WebhooksDatabase) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := d.Gorm.
+		WithContext(ctx).
+		Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to list due webhook deliveries", "error", err)
+		return nil, ErrWebhookList
+	}
+	return deliveries, nil
+}
+
+func (d *WebhooksDatabase) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	err := d.Gorm.
+		WithContext(ctx).
+		Model(delivery).
+		Select("status", "attempts", "response_code", "last_error", "next_attempt_at", "delivered_at").
+		Updates(delivery).Error
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "failed to update webhook delivery", "delivery", delivery.ID, "error", err)
+		return ErrWebhookFailed
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Package csvexport writes slices of flat view models as CSV that
+// spreadsheet apps open without an import wizard.
+package csvexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BOM marks the output as UTF-8 for Excel, which otherwise assumes the
+// system code page.
+const BOM = "\uFEFF"
+
+// decimalCommaLanguages write decimals with a comma, so spreadsheets in
+// those locales expect fields separated by semicolons.
+var decimalCommaLanguages = map[string]bool{
+	"pt": true, "es": true, "fr": true, "de": true, "it": true, "nl": true,
+	"ru": true, "pl": true, "tr": true, "sv": true, "da": true, "fi": true,
+	"nb": true, "cs": true, "id": true,
+}
+
+type Options struct {
+	// Comma separates fields; defaults to ','.
+	Comma rune
+	// DecimalComma writes floats with a decimal comma.
+	DecimalComma bool
+	// BOM prefixes the output with the UTF-8 byte order mark.
+	BOM bool
+	// Rename translates the column names, e.g. to the client's language.
+	Rename func(string) string
+}
+
+// ForLocale returns the options spreadsheets in locale, a BCP 47 tag such
+// as pt-BR, expect.
+func ForLocale(locale string) Options {
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	if decimalCommaLanguages[language] {
+		return Options{Comma: ';', DecimalComma: true}
+	}
+	return Options{Comma: ','}
+}
+
+// Header lists the columns of T, named after its json tags. Fields tagged
+// json:"-" are skipped.
+func Header[T any]() []string {
+	var header []string
+	for _, field := range fields(reflect.TypeFor[T]()) {
+		header = append(header, field.name)
+	}
+	return header
+}
+
+// Write streams a header and one record per row, with RFC 4180 line
+// endings. T must be a struct of
+// scalars, pointers to scalars or time.Time.
+func Write[T any](w io.Writer, rows []T, opts Options) error {
+	if opts.BOM {
+		if _, err := io.WriteString(w, BOM); err != nil {
+			return err
+		}
+	}
+
+	out := csv.NewWriter(w)
+	out.UseCRLF = true
+	if opts.Comma != 0 {
+		out.Comma = opts.Comma
+	}
+	columns := fields(reflect.TypeFor[T]())
+	header := Header[T]()
+	if opts.Rename != nil {
+		for i, name := range header {
+			header[i] = opts.Rename(name)
+		}
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		value := reflect.ValueOf(row)
+		for i, column := range columns {
+			record[i] = format(value.Field(column.index), opts)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+type field struct {
+	name  string
+	index int
+}
+
+func fields(t reflect.Type) []field {
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("csvexport: %s is not a struct", t))
+	}
+	var result []field
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = structField.Name
+		}
+		result = append(result, field{name: name, index: i})
+	}
+	return result
+}
+
+func format(value reflect.Value, opts Options) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if moment, ok := value.Interface().(time.Time); ok {
+		if moment.IsZero() {
+			return ""
+		}
+		return moment.UTC().Format(time.RFC3339)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return neutralize(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		number := strconv.FormatFloat(value.Float(), 'f', -1, 64)
+		if opts.DecimalComma {
+			number = strings.Replace(number, ".", ",", 1)
+		}
+		return number
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	default:
+		return neutralize(fmt.Sprint(value.Interface()))
+	}
+}
+
+// neutralize keeps text cells from being evaluated as spreadsheet formulas.
+func neutralize(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+package csvexport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	Position   int       `json:"posicao"`
+	Team       string    `json:"time"`
+	Difference int       `json:"saldo_gols"`
+	Average    float64   `json:"media"`
+	Goals      *int      `json:"gols,omitempty"`
+	Date       time.Time `json:"data"`
+	Internal   string    `json:"-"`
+}
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	is.Equal([]string{"posicao", "time", "saldo_gols", "media", "gols", "data"}, Header[row]())
+}
+
+func TestForLocale(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	tests := []struct {
+		name     string
+		locale   string
+		expected Options
+	}{
+		{name: "when locale is pt-BR, should separate with semicolons", locale: "pt-BR", expected: Options{Comma: ';', DecimalComma: true}},
+		{name: "when locale is only a language, should match it", locale: "es", expected: Options{Comma: ';', DecimalComma: true}},
+		{name: "when locale is en-US, should separate with commas", locale: "en-US", expected: Options{Comma: ','}},
+		{name: "when locale is empty, should separate with commas", expected: Options{Comma: ','}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			is.Equal(test.expected, ForLocale(test.locale))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	three := 3
+	rows := []row{
+		{Position: 1, Team: "Arsenal FC", Difference: 12, Average: 2.5, Goals: &three, Date: time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC), Internal: "x"},
+		{Position: 2, Team: "=HYPERLINK(\"x\")", Difference: -4, Average: 1},
+		{Position: 3, Team: "São Paulo; FC"},
+	}
+
+	t.Run("when options are default, should write comma separated values", func(t *testing.T) {
+		var out strings.Builder
+		is.Nil(Write(&out, rows, Options{}))
+		is.Equal(
+			"posicao,time,saldo_gols,media,gols,data\r\n"+
+				"1,Arsenal FC,12,2.5,3,2026-10-18T14:00:00Z\r\n"+
+				"2,\"'=HYPERLINK(\"\"x\"\")\",-4,1,,\r\n"+
+				"3,São Paulo; FC,0,0,,\r\n",
+			out.String(),
+		)
+	})
+
+	t.Run("when locale uses decimal comma, should separate with semicolons and add the BOM", func(t *testing.T) {
+		var out strings.Builder
+		opts := ForLocale("pt-BR")
+		opts.BOM = true
+		is.Nil(Write(&out, rows[:1], opts))
+		is.Equal(BOM+"posicao;time;saldo_gols;media;gols;data\r\n1;Arsenal FC;12;2,5;3;2026-10-18T14:00:00Z\r\n", out.String())
+	})
+
+	t.Run("when columns are renamed, should only change the header", func(t *testing.T) {
+		var out strings.Builder
+		names := map[string]string{"posicao": "position", "time": "team"}
+		opts := Options{Rename: func(name string) string {
+			if renamed, ok := names[name]; ok {
+				return renamed
+			}
+			return name
+		}}
+		is.Nil(Write(&out, rows[:1], opts))
+		is.Equal("position,team,saldo_gols,media,gols,data\r\n1,Arsenal FC,12,2.5,3,2026-10-18T14:00:00Z\r\n", out.String())
+	})
+
+	t.Run("when a text field holds the separator, should quote it", func(t *testing.T) {
+		var out strings.Builder
+		is.Nil(Write(&out, rows[2:], ForLocale("pt-BR")))
+		is.Contains(out.String(), "3;\"São Paulo; FC\";0;0;;\r\n")
+	})
+}
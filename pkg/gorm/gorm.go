@@ -1,16 +1,58 @@
 package gorm
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/pkg/postgres"
+	"github.com/fut-app/pkg/sqlite"
 	"gorm.io/gorm"
 )
 
-// NewGorm initialize session with database.
-func NewGorm(dialector gorm.Dialector) *gorm.DB {
-	db, err := gorm.Open(dialector)
+// NewDialector selects the database dialector configured in DB_DRIVER.
+func NewDialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.Database.Driver {
+	case config.DriverPostgres:
+		return postgres.NewPostgres(cfg.Postgres), nil
+	case config.DriverSQLite:
+		return sqlite.NewSQLite(cfg.SQLite), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Database.Driver)
+	}
+}
+
+// NewGorm initialize session with database, retrying with exponential
+// backoff while the database is not reachable.
+func NewGorm(dialector gorm.Dialector, cfg config.DatabaseConfig, log *slog.Logger) (*gorm.DB, error) {
+	var (
+		db  *gorm.DB
+		err error
+	)
+
+	backoff := cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		db, err = gorm.Open(dialector)
+		if err == nil || attempt >= cfg.ConnectRetries {
+			break
+		}
+		log.Warn("database not reachable, retrying", "attempt", attempt+1, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
 	if err != nil {
-		log.Fatalf("failed to initialize session with database: %v", err)
+		return nil, fmt.Errorf("failed to initialize session with database: %w", err)
 	}
-	return db
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to access database pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db, nil
 }
@@ -0,0 +1,97 @@
+//go:build unit
+
+package hasher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	argon := &Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	argonHash, err := argon.Hash("Xpto-123456")
+	is.Nil(err)
+	is.Regexp(`^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, argonHash)
+
+	legacy := &Bcrypt{Cost: bcrypt.MinCost}
+	bcryptHash, err := legacy.Hash("Xpto-123456")
+	is.Nil(err)
+
+	tests := []struct {
+		name       string
+		encoded    string
+		password   string
+		expectedOk bool
+		hasErr     bool
+	}{
+		{
+			name:       "when argon2id password matches, should return true",
+			encoded:    argonHash,
+			password:   "Xpto-123456",
+			expectedOk: true,
+		},
+		{
+			name:     "when argon2id password doesn't match, should return false",
+			encoded:  argonHash,
+			password: "wrong",
+		},
+		{
+			name:       "when legacy bcrypt password matches, should return true",
+			encoded:    bcryptHash,
+			password:   "Xpto-123456",
+			expectedOk: true,
+		},
+		{
+			name:     "when legacy bcrypt password doesn't match, should return false",
+			encoded:  bcryptHash,
+			password: "wrong",
+		},
+		{
+			name:     "when algorithm is unknown, should return error",
+			encoded:  "$md5$abc",
+			password: "Xpto-123456",
+			hasErr:   true,
+		},
+		{
+			name:     "when argon2id hash is malformed, should return error",
+			encoded:  "$argon2id$v=19$m=x$salt$key",
+			password: "Xpto-123456",
+			hasErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ok, err := Verify(test.encoded, test.password)
+			is.Equal(test.hasErr, err != nil)
+			is.Equal(test.expectedOk, ok)
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	current := &Argon2id{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	weaker := &Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	currentHash, err := current.Hash("Xpto-123456")
+	is.Nil(err)
+	weakerHash, err := weaker.Hash("Xpto-123456")
+	is.Nil(err)
+	bcryptHash, err := (&Bcrypt{Cost: bcrypt.MinCost}).Hash("Xpto-123456")
+	is.Nil(err)
+
+	is.False(current.NeedsRehash(currentHash))
+	is.True(current.NeedsRehash(weakerHash))
+	is.True(current.NeedsRehash(bcryptHash))
+	is.False((&Bcrypt{Cost: bcrypt.MinCost}).NeedsRehash(bcryptHash))
+	is.True((&Bcrypt{Cost: bcrypt.MinCost + 1}).NeedsRehash(bcryptHash))
+}
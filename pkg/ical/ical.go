@@ -0,0 +1,169 @@
+// Package ical writes RFC 5545 calendars meant to be published to
+// subscribers, such as the fixtures of a competition.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	// lineLimit is the maximum length of a content line in octets, CRLF
+	// excluded.
+	lineLimit = 75
+
+	utcLayout  = "20060102T150405Z"
+	dateLayout = "20060102"
+)
+
+// Calendar is a VCALENDAR published with METHOD:PUBLISH.
+type Calendar struct {
+	ProdID string
+	Name   string
+	// TimeZone is the IANA zone clients should display the events in.
+	// Event times are always written in UTC.
+	TimeZone string
+	// Refresh suggests how often subscribers should reload the calendar.
+	Refresh time.Duration
+	Events  []Event
+}
+
+// Event is a VEVENT. UID must stay the same across publications so clients
+// update the event instead of duplicating it, and Sequence must grow when it
+// is rescheduled.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time
+	LastModified time.Time
+	Start        time.Time
+	End          time.Time
+	// AllDay writes Start and End as dates in their own location, e.g. for
+	// events whose time is not known yet.
+	AllDay      bool
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+}
+
+// Encode renders the calendar with CRLF line endings and folded lines.
+func (c Calendar) Encode() []byte {
+	var w writer
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", Escape(c.Name))
+		w.line("NAME", Escape(c.Name))
+	}
+	if c.TimeZone != "" {
+		w.line("X-WR-TIMEZONE", c.TimeZone)
+	}
+	if c.Refresh > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION", Duration(c.Refresh))
+		w.line("X-PUBLISHED-TTL", Duration(c.Refresh))
+	}
+	for _, event := range c.Events {
+		event.encode(&w)
+	}
+	w.line("END", "VCALENDAR")
+	return w.Bytes()
+}
+
+func (e Event) encode(w *writer) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", e.UID)
+	w.line("DTSTAMP", e.Stamp.UTC().Format(utcLayout))
+	if !e.LastModified.IsZero() {
+		w.line("LAST-MODIFIED", e.LastModified.UTC().Format(utcLayout))
+	}
+	w.line("SEQUENCE", fmt.Sprint(e.Sequence))
+	if e.AllDay {
+		w.line("DTSTART;VALUE=DATE", e.Start.Format(dateLayout))
+		w.line("DTEND;VALUE=DATE", e.End.Format(dateLayout))
+		w.line("TRANSP", "TRANSPARENT")
+	} else {
+		w.line("DTSTART", e.Start.UTC().Format(utcLayout))
+		w.line("DTEND", e.End.UTC().Format(utcLayout))
+	}
+	w.line("SUMMARY", Escape(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION", Escape(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION", Escape(e.Location))
+	}
+	if e.Status != "" {
+		w.line("STATUS", e.Status)
+	}
+	w.line("END", "VEVENT")
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// Escape quotes a TEXT value (RFC 5545 section 3.3.11).
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// Duration formats d as an RFC 5545 duration, e.g. PT1H30M.
+func Duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+	var out strings.Builder
+	out.WriteString("PT")
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&out, "%dH", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		fmt.Fprintf(&out, "%dM", minutes)
+		d -= minutes * time.Minute
+	}
+	if seconds := d / time.Second; seconds > 0 {
+		fmt.Fprintf(&out, "%dS", seconds)
+	}
+	return out.String()
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+// line writes a content line folded at lineLimit octets without splitting
+// UTF-8 sequences. Continuation lines start with a space.
+func (w *writer) line(name string, value string) {
+	content := name + ":" + value
+	limit := lineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		limit = lineLimit - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
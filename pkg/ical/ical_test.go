@@ -0,0 +1,105 @@
+//go:build unit
+
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	is.Equal(`Arsenal\, Chelsea\; C:\\temp\nnext`, Escape("Arsenal, Chelsea; C:\\temp\r\nnext"))
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		expected string
+	}{
+		{name: "when duration is whole hours, should only write hours", duration: time.Hour, expected: "PT1H"},
+		{name: "when duration mixes units, should write each of them", duration: 90*time.Minute + 5*time.Second, expected: "PT1H30M5S"},
+		{name: "when duration is zero, should write zero seconds", expected: "PT0S"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			is.Equal(test.expected, Duration(test.duration))
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	kickoff := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	is.Nil(err)
+
+	calendar := Calendar{
+		ProdID:   "-//fut-app//partidas//PT",
+		Name:     "Campeonato Brasileiro Série A",
+		TimeZone: "America/Sao_Paulo",
+		Refresh:  time.Hour,
+		Events: []Event{
+			{
+				UID:      "partida-1@fut-app",
+				Sequence: 3,
+				Stamp:    kickoff,
+				Start:    kickoff,
+				End:      kickoff.Add(2 * time.Hour),
+				Summary:  "Arsenal FC x Chelsea FC",
+				Location: strings.Repeat("Estádio Olímpico ", 6),
+				Status:   StatusConfirmed,
+			},
+			{
+				UID:     "partida-2@fut-app",
+				Stamp:   kickoff,
+				Start:   time.Date(2026, 10, 25, 0, 0, 0, 0, saoPaulo),
+				End:     time.Date(2026, 10, 26, 0, 0, 0, 0, saoPaulo),
+				AllDay:  true,
+				Summary: "Santos FC x SE Palmeiras",
+			},
+		},
+	}
+	encoded := string(calendar.Encode())
+
+	t.Run("when encoded, should end every line with CRLF", func(t *testing.T) {
+		is.True(strings.HasSuffix(encoded, "END:VCALENDAR\r\n"))
+		is.NotContains(strings.ReplaceAll(encoded, "\r\n", ""), "\n")
+	})
+
+	t.Run("when a line is too long, should fold it without splitting characters", func(t *testing.T) {
+		for _, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+			is.LessOrEqual(len(line), lineLimit, line)
+			is.True(strings.ToValidUTF8(line, "") == line, line)
+		}
+		unfolded := strings.ReplaceAll(encoded, "\r\n ", "")
+		is.Contains(unfolded, "LOCATION:"+strings.Repeat("Estádio Olímpico ", 6)+"\r\n")
+	})
+
+	t.Run("when an event is timed, should write it in UTC", func(t *testing.T) {
+		is.Contains(encoded, "DTSTART:20261018T140000Z\r\nDTEND:20261018T160000Z\r\n")
+		is.Contains(encoded, "SEQUENCE:3\r\n")
+	})
+
+	t.Run("when an event is all day, should write dates", func(t *testing.T) {
+		is.Contains(encoded, "DTSTART;VALUE=DATE:20261025\r\nDTEND;VALUE=DATE:20261026\r\n")
+	})
+
+	t.Run("when refresh is set, should suggest it to subscribers", func(t *testing.T) {
+		is.Contains(encoded, "REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n")
+		is.Contains(encoded, "X-WR-TIMEZONE:America/Sao_Paulo\r\n")
+	})
+}
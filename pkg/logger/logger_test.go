@@ -0,0 +1,71 @@
+//go:build unit
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/fut-app/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+	tests := []struct {
+		name   string
+		cfg    config.LogConfig
+		hasErr bool
+	}{
+		{
+			name: "when level and format are valid, should return logger",
+			cfg:  config.LogConfig{Level: "debug", Format: "text"},
+		},
+		{
+			name:   "when level is unknown, should return error",
+			cfg:    config.LogConfig{Level: "verbose", Format: "json"},
+			hasErr: true,
+		},
+		{
+			name:   "when format is unknown, should return error",
+			cfg:    config.LogConfig{Level: "info", Format: "xml"},
+			hasErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			log, err := NewLogger(&bytes.Buffer{}, test.cfg)
+			if test.hasErr {
+				is.NotNil(err)
+				return
+			}
+			is.Nil(err)
+			is.NotNil(log)
+		})
+	}
+}
+
+func TestLoggerContextAndRedaction(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	var buf bytes.Buffer
+	log, err := NewLogger(&buf, config.LogConfig{Level: "info", Format: "json"})
+	is.Nil(err)
+
+	ctx := AppendCtx(context.Background(), slog.String("request_id", "abc"))
+	log.InfoContext(ctx, "login", "user", "admin", "password", "xpto123", "access_token", "jwt")
+
+	var record map[string]any
+	is.Nil(json.Unmarshal(buf.Bytes(), &record))
+	is.Equal("abc", record["request_id"])
+	is.Equal("admin", record["user"])
+	is.Equal("[REDACTED]", record["password"])
+	is.Equal("[REDACTED]", record["access_token"])
+}
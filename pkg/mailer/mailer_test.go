@@ -0,0 +1,134 @@
+//go:build unit
+
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	var out bytes.Buffer
+	m := NewWriter(&out, "fut-app <no-reply@localhost>")
+	err := m.Send(context.Background(), Message{
+		To:      "fan@example.com\r\nBcc: evil@example.com",
+		Subject: "reset",
+		Body:    "line 1\nline 2",
+	})
+	is.Nil(err)
+
+	mail := out.String()
+	is.Contains(mail, "From: fut-app <no-reply@localhost>\r\n")
+	is.Contains(mail, "To: fan@example.comBcc: evil@example.com\r\n")
+	is.False(strings.Contains(mail, "\r\nBcc:"))
+	is.Contains(mail, "\r\n\r\nline 1\r\nline 2\r\n")
+}
+
+// fakeSMTP answers a single SMTP session on a local listener and sends the
+// commands it received, or hangs without a greeting when silent is set.
+func fakeSMTP(t *testing.T, silent bool) (string, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if silent {
+			conn.Read(make([]byte, 1))
+			return
+		}
+
+		var received []string
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("220 fake ESMTP\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			received = append(received, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				conn.Write([]byte("250 fake\r\n"))
+			case line == "DATA":
+				conn.Write([]byte("354 go ahead\r\n"))
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+				}
+				conn.Write([]byte("250 queued\r\n"))
+			case line == "QUIT":
+				conn.Write([]byte("221 bye\r\n"))
+				commands <- received
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+		commands <- received
+	}()
+	return listener.Addr().String(), commands
+}
+
+func TestSMTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when from has a display name, should send the bare address as envelope sender", func(t *testing.T) {
+		t.Parallel()
+		is := require.New(t)
+
+		addr, commands := fakeSMTP(t, false)
+		m := &SMTP{Addr: addr, From: "fut-app <no-reply@localhost>", Sender: "no-reply@localhost", Timeout: time.Second}
+		is.Nil(m.Send(context.Background(), Message{To: "Fan <fan@example.com>", Subject: "reset", Body: "token"}))
+
+		received := <-commands
+		is.Contains(received, "MAIL FROM:<no-reply@localhost>")
+		is.Contains(received, "RCPT TO:<fan@example.com>")
+	})
+
+	t.Run("when the server hangs, should give up once the context is done", func(t *testing.T) {
+		t.Parallel()
+		is := require.New(t)
+
+		addr, _ := fakeSMTP(t, true)
+		m := &SMTP{Addr: addr, From: "no-reply@localhost", Sender: "no-reply@localhost", Timeout: time.Minute}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		is.ErrorIs(m.Send(ctx, Message{To: "fan@example.com"}), context.DeadlineExceeded)
+		is.Less(time.Since(started), 5*time.Second)
+	})
+}
+
+func TestNewSMTP(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	m, err := NewSMTP(config.MailConfig{From: "fut-app <no-reply@localhost>", SMTPHost: "smtp.example.com", SMTPPort: "587"})
+	is.Nil(err)
+	is.Equal("no-reply@localhost", m.Sender)
+
+	_, err = NewSMTP(config.MailConfig{From: "fut-app"})
+	is.NotNil(err)
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fut-app/internal/config"
 	"gorm.io/driver/postgres"
@@ -16,12 +17,29 @@ func NewPostgres(cfg config.PostgresConfig) gorm.Dialector {
 }
 
 func mountDNS(cfg config.PostgresConfig) string {
-	return fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.Host,
-		cfg.User,
-		cfg.Password,
-		cfg.Name,
-		cfg.Port,
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s connect_timeout=%d statement_timeout=%d",
+		quote(cfg.Host),
+		quote(cfg.User),
+		quote(cfg.Password),
+		quote(cfg.Name),
+		quote(cfg.Port),
+		quote(cfg.SSLMode),
+		int(cfg.ConnectTimeout.Seconds()),
+		cfg.StatementTimeout.Milliseconds(),
 	)
+	if cfg.SSLRootCert != "" {
+		dsn += " sslrootcert=" + quote(cfg.SSLRootCert)
+	}
+	return dsn
+}
+
+// quote escapes a value for the key/value connection string format.
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
 }
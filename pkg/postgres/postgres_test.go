@@ -0,0 +1,38 @@
+//go:build unit
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fut-app/internal/config"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountDNS(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	cfg := config.PostgresConfig{
+		Host:             "db",
+		Port:             "5432",
+		User:             "postgres",
+		Password:         "it's a secret",
+		Name:             "futapp",
+		SSLMode:          "disable",
+		ConnectTimeout:   5 * time.Second,
+		StatementTimeout: 2 * time.Second,
+	}
+
+	parsed, err := pgconn.ParseConfig(mountDNS(cfg))
+	is.Nil(err)
+	is.Equal("db", parsed.Host)
+	is.Equal(uint16(5432), parsed.Port)
+	is.Equal("it's a secret", parsed.Password)
+	is.Equal("futapp", parsed.Database)
+	is.Equal(5*time.Second, parsed.ConnectTimeout)
+	is.Equal("2000", parsed.RuntimeParams["statement_timeout"])
+	is.Nil(parsed.TLSConfig)
+}
@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/fut-app/internal/config"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+const InMemory = ":memory:"
+
+// NewSQLite returns a pure Go SQLite dialector with foreign keys enforced.
+func NewSQLite(cfg config.SQLiteConfig) gorm.Dialector {
+	return sqlite.Open(mountDSN(cfg))
+}
+
+func mountDSN(cfg config.SQLiteConfig) string {
+	separator := "?"
+	if strings.Contains(cfg.Path, "?") {
+		separator = "&"
+	}
+	return cfg.Path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
@@ -0,0 +1,29 @@
+// Package sqlitetest provides in-memory SQLite databases for tests.
+package sqlitetest
+
+import (
+	"testing"
+
+	"github.com/fut-app/internal/config"
+	"github.com/fut-app/pkg/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// NewDB opens an in-memory database with models migrated, closed when the
+// test ends. It holds a single connection, since each connection to
+// ":memory:" would open a database of its own.
+func NewDB(t testing.TB, models ...any) *gorm.DB {
+	t.Helper()
+	is := require.New(t)
+
+	db, err := gorm.Open(sqlite.NewSQLite(config.SQLiteConfig{Path: sqlite.InMemory}))
+	is.Nil(err)
+	sqlDB, err := db.DB()
+	is.Nil(err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	is.Nil(db.AutoMigrate(models...))
+	return db
+}
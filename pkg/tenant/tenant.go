@@ -0,0 +1,19 @@
+// Package tenant carries the organization of the authenticated caller in the
+// request context, so repositories can scope their queries to it.
+package tenant
+
+import "context"
+
+type ctxKey struct{}
+
+// WithOrganization returns a copy of ctx scoped to the organization.
+func WithOrganization(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, ctxKey{}, orgID)
+}
+
+// Organization returns the organization ctx is scoped to. Requests without
+// one, such as logins or platform admins, are not scoped.
+func Organization(ctx context.Context) (int, bool) {
+	orgID, ok := ctx.Value(ctxKey{}).(int)
+	return orgID, ok
+}
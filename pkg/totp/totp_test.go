@@ -0,0 +1,63 @@
+//go:build unit
+
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	// RFC 6238 appendix B vectors (SHA1), truncated to six digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		name     string
+		unix     int64
+		expected string
+	}{
+		{name: "when time is 59, should return 287082", unix: 59, expected: "287082"},
+		{name: "when time is 1111111109, should return 081804", unix: 1111111109, expected: "081804"},
+		{name: "when time is 1234567890, should return 005924", unix: 1234567890, expected: "005924"},
+		{name: "when time is 2000000000, should return 279037", unix: 2000000000, expected: "279037"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			code, err := Code(secret, Step(time.Unix(test.unix, 0)))
+			is.Nil(err)
+			is.Equal(test.expected, code)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	is := require.New(t)
+
+	secret, err := GenerateSecret()
+	is.Nil(err)
+	now := time.Now()
+
+	previous, err := Code(secret, Step(now)-1)
+	is.Nil(err)
+	step, ok := Validate(secret, previous, now)
+	is.True(ok)
+	is.Equal(Step(now)-1, step)
+
+	old, err := Code(secret, Step(now)-3)
+	is.Nil(err)
+	_, ok = Validate(secret, old, now)
+	is.False(ok)
+
+	uri := ProvisioningURI("fut-app", "admin", secret)
+	is.True(strings.HasPrefix(uri, "otpauth://totp/fut-app:admin?"))
+	is.Contains(uri, "secret="+secret)
+}